@@ -1,8 +1,10 @@
 package main
 
 import (
+	"github.com/KumKeeHyun/gin-realworld/pkg/crypto"
 	"github.com/spf13/viper"
 	"strings"
+	"time"
 )
 
 type config struct {
@@ -16,10 +18,68 @@ type config struct {
 		Port     string `yaml:"port"`
 		CertFile string `yaml:"certFile"`
 		KeyFile  string `yaml:"keyFile"`
+		// TrustedProxies are addresses or CIDRs of proxies whose X-Forwarded-For is trusted for client ip,
+		// none by default so that clients can not forge their ip
+		TrustedProxies []string `yaml:"trustedProxies"`
 	} `yaml:"server"`
 	Jwt struct {
-		SecretKey string `yaml:"secretKey"`
+		// SecretKey is HS256 shared secret, only used for verification when Keys exist
+		SecretKey    string `yaml:"secretKey"`
+		SigningKeyID string `yaml:"signingKeyId"`
+		Keys         []struct {
+			ID   string `yaml:"id"`
+			File string `yaml:"file"`
+		} `yaml:"keys"`
 	} `yaml:"jwt"`
+	Auth struct {
+		RequireEmailVerification bool `yaml:"requireEmailVerification"`
+		// Admins are emails of users promoted to admin on startup, other admins are managed through api
+		Admins []string `yaml:"admins"`
+		// Password is argon2id parameters, passwords hashed with others are rehashed on login
+		Password struct {
+			Memory      uint32 `yaml:"memory"`
+			Iterations  uint32 `yaml:"iterations"`
+			Parallelism uint8  `yaml:"parallelism"`
+		} `yaml:"password"`
+	} `yaml:"auth"`
+	OAuth struct {
+		// Providers are OpenID Connect issuers, RedirectURL should be /api/users/oauth/{name}/callback
+		Providers []struct {
+			Name         string   `yaml:"name"`
+			Issuer       string   `yaml:"issuer"`
+			ClientID     string   `yaml:"clientId"`
+			ClientSecret string   `yaml:"clientSecret"`
+			RedirectURL  string   `yaml:"redirectUrl"`
+			Scopes       []string `yaml:"scopes"`
+		} `yaml:"providers"`
+	} `yaml:"oauth"`
+	Mailer struct {
+		// Type is one of log, smtp
+		Type    string `yaml:"type"`
+		From    string `yaml:"from"`
+		LogFile string `yaml:"logFile"`
+		Smtp    struct {
+			Host     string `yaml:"host"`
+			Port     string `yaml:"port"`
+			Username string `yaml:"username"`
+			Password string `yaml:"password"`
+		} `yaml:"smtp"`
+	} `yaml:"mailer"`
+	Pagination struct {
+		// CursorKey signs cursors of pages, cursors are invalidated on restart if it is not set
+		CursorKey string `yaml:"cursorKey"`
+	} `yaml:"pagination"`
+	Comment struct {
+		// MaxDepth is the maximum depth of replies, 0 disallows replies
+		MaxDepth int `yaml:"maxDepth"`
+		// MaxTreeReplies is the maximum number of replies nested in one page of tree layout
+		MaxTreeReplies int `yaml:"maxTreeReplies"`
+	} `yaml:"comment"`
+	Scheduler struct {
+		// Enabled on every replica is safe, jobs are run by the replica holding the lease
+		Enabled  bool          `yaml:"enabled"`
+		Interval time.Duration `yaml:"interval"`
+	} `yaml:"scheduler"`
 	Logger struct {
 		Profile string `yaml:"profile"`
 	} `yaml:"logger"`
@@ -34,7 +94,26 @@ func readConfig() (*config, error) {
 	viper.SetDefault("server.port", "8080")
 	viper.SetDefault("server.certFile", "")
 	viper.SetDefault("server.keyFile", "")
-	viper.SetDefault("jwt.secretKey", "realworld-secret-key")
+	viper.SetDefault("server.trustedProxies", []string{})
+	viper.SetDefault("jwt.secretKey", "")
+	viper.SetDefault("jwt.signingKeyId", "")
+	viper.SetDefault("auth.requireEmailVerification", false)
+	viper.SetDefault("auth.admins", []string{})
+	viper.SetDefault("auth.password.memory", crypto.DefaultArgon2Params.Memory)
+	viper.SetDefault("auth.password.iterations", crypto.DefaultArgon2Params.Iterations)
+	viper.SetDefault("auth.password.parallelism", crypto.DefaultArgon2Params.Threads)
+	viper.SetDefault("mailer.type", "log")
+	viper.SetDefault("mailer.from", "noreply@conduit.local")
+	viper.SetDefault("mailer.logFile", "")
+	viper.SetDefault("mailer.smtp.host", "")
+	viper.SetDefault("mailer.smtp.port", "587")
+	viper.SetDefault("mailer.smtp.username", "")
+	viper.SetDefault("mailer.smtp.password", "")
+	viper.SetDefault("pagination.cursorKey", "")
+	viper.SetDefault("comment.maxDepth", 5)
+	viper.SetDefault("comment.maxTreeReplies", 200)
+	viper.SetDefault("scheduler.enabled", true)
+	viper.SetDefault("scheduler.interval", "30s")
 	viper.SetDefault("logger.profile", "dev")
 
 	// yaml
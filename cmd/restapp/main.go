@@ -1,8 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/service"
+	"github.com/KumKeeHyun/gin-realworld/internal/mailer"
+	"github.com/KumKeeHyun/gin-realworld/internal/oidc"
+	postgresrepo "github.com/KumKeeHyun/gin-realworld/internal/repository/postgres"
+	sqliterepo "github.com/KumKeeHyun/gin-realworld/internal/repository/sqlite"
+	"github.com/KumKeeHyun/gin-realworld/internal/rest/middleware"
+	"github.com/KumKeeHyun/gin-realworld/internal/scheduler"
+	"github.com/KumKeeHyun/gin-realworld/pkg/clock"
+	"github.com/KumKeeHyun/gin-realworld/pkg/crypto"
+	"github.com/KumKeeHyun/gin-realworld/pkg/cursor"
 	"github.com/KumKeeHyun/gin-realworld/pkg/jwtutil"
 	"github.com/gin-gonic/gin"
 	"github.com/glebarez/sqlite"
@@ -12,6 +24,9 @@ import (
 	"gorm.io/gorm"
 	"log"
 	"moul.io/zapgorm2"
+	"net/http"
+	"os"
+	"time"
 )
 
 func main() {
@@ -26,11 +41,28 @@ func main() {
 	defer logger.Sync()
 	logger.Sugar().Infow("read config", "config", config)
 
-	r, err := InitRouter(config, logger)
+	err = InitPasswordHashing(config)
 	if err != nil {
 		panic(err)
 	}
 
+	a, err := InitApp(config, logger)
+	if err != nil {
+		panic(err)
+	}
+	if len(os.Args) > 1 {
+		if err := a.runCommand(os.Args[1], logger); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	a.scheduler.Start(context.Background())
+
+	r := a.router
+	// client ip keys login throttle, it must not be taken from headers of untrusted clients
+	if err := r.SetTrustedProxies(config.Server.TrustedProxies); err != nil {
+		panic(err)
+	}
 	addr := config.Server.Host + ":" + config.Server.Port
 	if config.Server.CertFile == "" || config.Server.KeyFile == "" {
 		log.Fatal(r.Run(addr))
@@ -39,13 +71,61 @@ func main() {
 	}
 }
 
-func InitRouter(config *config, logger *zap.Logger) (*gin.Engine, error) {
+type app struct {
+	router         *gin.Engine
+	scheduler      *scheduler.Scheduler
+	authService    ports.AuthService
+	articleService ports.ArticleService
+}
+
+func newApp(router *gin.Engine, scheduler *scheduler.Scheduler, authService ports.AuthService, articleService ports.ArticleService) *app {
+	return &app{
+		router:         router,
+		scheduler:      scheduler,
+		authService:    authService,
+		articleService: articleService,
+	}
+}
+
+// runCommand runs maintenance command instead of serving api, such as `restapp reconcile-favorites` or `restapp check-authors`.
+func (a *app) runCommand(command string, logger *zap.Logger) error {
+	switch command {
+	case "reconcile-favorites":
+		reconciled, err := a.articleService.ReconcileFavoritesCounts()
+		if err != nil {
+			return err
+		}
+		logger.Sugar().Infow("favorites counts are reconciled", "articles", reconciled)
+		return nil
+	case "check-authors":
+		drifted, err := a.authService.CheckAuthors()
+		if err != nil {
+			return err
+		}
+		if len(drifted) > 0 {
+			return fmt.Errorf("author copies of users %v are drifted, run repair-authors", drifted)
+		}
+		logger.Sugar().Infow("author copies are consistent with users")
+		return nil
+	case "repair-authors":
+		repaired, err := a.authService.RepairAuthors()
+		if err != nil {
+			return err
+		}
+		logger.Sugar().Infow("author copies are repaired", "users", repaired)
+		return nil
+	default:
+		return fmt.Errorf("unknown command: %s", command)
+	}
+}
+
+func InitApp(config *config, logger *zap.Logger) (*app, error) {
 	switch config.Datasource.DBType {
 	case "sqlite":
-		return InitRouterUsingSqlite(config, logger)
+		return InitAppUsingSqlite(config, logger)
 	case "postgres":
 		gin.SetMode(gin.ReleaseMode)
-		return InitRouterUsingPostgres(config, logger)
+		return InitAppUsingPostgres(config, logger)
 	default:
 		return nil, fmt.Errorf("invalid dbType: %s", config.Datasource.DBType)
 	}
@@ -67,6 +147,8 @@ func InitDatasource(config *config, logger *zap.Logger) (db *gorm.DB, err error)
 	gormLogger.SetAsDefault()
 	gormCfg := &gorm.Config{
 		Logger: gormLogger,
+		// unique constraint violations are returned as gorm.ErrDuplicatedKey
+		TranslateError: true,
 	}
 
 	switch config.Datasource.DBType {
@@ -77,16 +159,208 @@ func InitDatasource(config *config, logger *zap.Logger) (db *gorm.DB, err error)
 	default:
 		return nil, fmt.Errorf("invalid dbType: %s", config.Datasource.DBType)
 	}
+	err = migrateUniqueFavorites(db)
+	if err != nil {
+		return
+	}
 	err = db.AutoMigrate(
 		&domain.User{},
 		&domain.Follow{},
 		&domain.Article{},
+		&domain.ArticleRevision{},
+		&domain.SlugHistory{},
 		&domain.Favorite{},
 		&domain.Comment{},
+		&domain.CommentRevision{},
+		&domain.RefreshToken{},
+		&domain.DeniedToken{},
+		&domain.OneTimeToken{},
+		&domain.Identity{},
+		&domain.LoginState{},
+		&domain.PersonalAccessToken{},
+		&domain.TwoFactor{},
+		&domain.RecoveryCode{},
+		&domain.LoginThrottle{},
+		&domain.Lease{},
 	)
+	if err != nil {
+		return
+	}
+	err = migrateFullTextSearch(db, config.Datasource.DBType)
+	if err != nil {
+		return
+	}
+	err = promoteAdmins(db, config.Auth.Admins, logger)
 	return
 }
 
-func InitJwtUtil(config *config) *jwtutil.JwtUtil {
-	return jwtutil.New(jwt.SigningMethodHS256, []byte(config.Jwt.SecretKey))
+// migrateUniqueFavorites removes soft-deleted and duplicated favorites before their unique index is created.
+// Favorites counts are not changed, run reconcile-favorites command after migration.
+func migrateUniqueFavorites(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable(&domain.Favorite{}) || m.HasIndex(&domain.Favorite{}, "idx_favorite_user_article") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`DELETE FROM favorites WHERE deleted_at IS NOT NULL OR id NOT IN (
+			SELECT MIN(id) FROM favorites WHERE deleted_at IS NULL GROUP BY user_id, article_id
+		)`).Error
+		if err != nil {
+			return err
+		}
+		if tx.Migrator().HasIndex(&domain.Favorite{}, "idx_user_article") {
+			return tx.Migrator().DropIndex(&domain.Favorite{}, "idx_user_article")
+		}
+		return nil
+	})
+}
+
+// migrateFullTextSearch creates index for full-text search, which is not expressed by gorm models.
+func migrateFullTextSearch(db *gorm.DB, dbType string) error {
+	switch dbType {
+	case "sqlite":
+		return sqliterepo.MigrateFullTextSearch(db)
+	case "postgres":
+		return postgresrepo.MigrateFullTextSearch(db)
+	default:
+		return fmt.Errorf("invalid dbType: %s", dbType)
+	}
+}
+
+func promoteAdmins(db *gorm.DB, emails []string, logger *zap.Logger) error {
+	if len(emails) == 0 {
+		return nil
+	}
+	tx := db.Model(&domain.User{}).
+		Where("email IN ?", emails).
+		Update("role", domain.RoleAdmin)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	logger.Sugar().Infow("admins are promoted", "count", tx.RowsAffected)
+	return nil
+}
+
+func InitPasswordHashing(config *config) error {
+	params := crypto.DefaultArgon2Params
+	params.Memory = config.Auth.Password.Memory
+	params.Iterations = config.Auth.Password.Iterations
+	params.Threads = config.Auth.Password.Parallelism
+	return crypto.SetPasswordParams(params)
+}
+
+func InitMailer(config *config, logger *zap.Logger) (ports.Mailer, error) {
+	switch config.Mailer.Type {
+	case "log":
+		return mailer.NewLogMailer(config.Mailer.LogFile, logger), nil
+	case "smtp":
+		return mailer.NewSmtpMailer(mailer.SmtpConfig{
+			Host:     config.Mailer.Smtp.Host,
+			Port:     config.Mailer.Smtp.Port,
+			Username: config.Mailer.Smtp.Username,
+			Password: config.Mailer.Smtp.Password,
+			From:     config.Mailer.From,
+		}), nil
+	default:
+		return nil, fmt.Errorf("invalid mailer type: %s", config.Mailer.Type)
+	}
+}
+
+func InitIdentityProviders(config *config) ([]ports.IdentityProvider, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	providers := make([]ports.IdentityProvider, 0, len(config.OAuth.Providers))
+	names := map[string]bool{}
+	for _, p := range config.OAuth.Providers {
+		if p.Name == "" || p.Issuer == "" || p.ClientID == "" || p.RedirectURL == "" {
+			return nil, fmt.Errorf("oauth provider requires name, issuer, clientId and redirectUrl: %s", p.Name)
+		}
+		if names[p.Name] {
+			return nil, fmt.Errorf("duplicated oauth provider: %s", p.Name)
+		}
+		names[p.Name] = true
+		providers = append(providers, oidc.NewProvider(oidc.Config{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		}, client))
+	}
+	return providers, nil
+}
+
+func InitScheduler(config *config, db *gorm.DB, leaseRepo ports.LeaseRepository, articleService ports.ArticleService, authService ports.AuthService, logger *zap.Logger) (*scheduler.Scheduler, error) {
+	s := scheduler.New(db, leaseRepo, clock.New(), logger)
+	if !config.Scheduler.Enabled {
+		return s, nil
+	}
+	if config.Scheduler.Interval <= 0 {
+		return nil, fmt.Errorf("invalid scheduler interval: %s", config.Scheduler.Interval)
+	}
+	s.Add(scheduler.NewPublishJob(articleService, config.Scheduler.Interval))
+	s.Add(scheduler.NewPurgeDeniedTokensJob(authService, config.Scheduler.Interval))
+	return s, nil
+}
+
+func InitEnsureVerifiedMiddleware(config *config, userRepo ports.UserRepository, logger *zap.Logger) middleware.EnsureVerifiedMiddleware {
+	return middleware.NewEnsureVerifiedMiddleware(config.Auth.RequireEmailVerification, userRepo, logger)
+}
+
+func InitCommentOptions(config *config) (service.CommentOptions, error) {
+	if config.Comment.MaxDepth < 0 {
+		return service.CommentOptions{}, fmt.Errorf("invalid comment max depth: %d", config.Comment.MaxDepth)
+	}
+	if config.Comment.MaxTreeReplies <= 0 {
+		return service.CommentOptions{}, fmt.Errorf("invalid comment max tree replies: %d", config.Comment.MaxTreeReplies)
+	}
+	return service.CommentOptions{
+		MaxDepth:       config.Comment.MaxDepth,
+		MaxTreeReplies: config.Comment.MaxTreeReplies,
+	}, nil
+}
+
+func InitCursorCodec(config *config, logger *zap.Logger) (*cursor.Codec, error) {
+	if config.Pagination.CursorKey != "" {
+		return cursor.New([]byte(config.Pagination.CursorKey)), nil
+	}
+	logger.Warn("cursor key not configured, use ephemeral key which is lost on restart")
+	return cursor.NewEphemeral()
+}
+
+func InitJwtUtil(config *config, logger *zap.Logger) (*jwtutil.JwtUtil, error) {
+	if len(config.Jwt.Keys) == 0 {
+		if config.Jwt.SecretKey != "" {
+			return jwtutil.New(jwt.SigningMethodHS256, []byte(config.Jwt.SecretKey)), nil
+		}
+		logger.Warn("jwt key not configured, use ephemeral key which is lost on restart")
+		key, err := jwtutil.NewEphemeralKey("ephemeral")
+		if err != nil {
+			return nil, err
+		}
+		return jwtutil.NewWithKeys(key)
+	}
+
+	var signing jwtutil.Key
+	var others []jwtutil.Key
+	for _, k := range config.Jwt.Keys {
+		key, err := jwtutil.LoadKeyFile(k.ID, k.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load jwt key %s: %w", k.ID, err)
+		}
+		if k.ID == config.Jwt.SigningKeyID {
+			signing = key
+		} else {
+			others = append(others, key)
+		}
+	}
+	if signing.Sign == nil {
+		return nil, fmt.Errorf("invalid jwt signingKeyId: %s", config.Jwt.SigningKeyID)
+	}
+	if config.Jwt.SecretKey != "" {
+		// keep accepting tokens signed by shared secret while migrating to asymmetric keys
+		secret := []byte(config.Jwt.SecretKey)
+		others = append(others, jwtutil.Key{Method: jwt.SigningMethodHS256, Verify: secret})
+	}
+	return jwtutil.NewWithKeys(signing, others...)
 }
@@ -10,7 +10,6 @@ import (
 	"github.com/KumKeeHyun/gin-realworld/internal/rest"
 	"github.com/KumKeeHyun/gin-realworld/internal/rest/controller"
 	"github.com/KumKeeHyun/gin-realworld/internal/rest/middleware"
-	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 	"go.uber.org/zap"
 )
@@ -19,12 +18,24 @@ var SqliteRepositorySet = wire.NewSet(
 	sqlite.NewUserRepository,
 	sqlite.NewArticleRepository,
 	sqlite.NewCommentRepository,
+	sqlite.NewAuthorRepository,
+	sqlite.NewTokenRepository,
+	sqlite.NewIdentityRepository,
+	sqlite.NewMFARepository,
+	sqlite.NewLoginThrottleRepository,
+	sqlite.NewLeaseRepository,
 )
 
 var PostgresRepositorySet = wire.NewSet(
 	postgres.NewUserRepository,
 	postgres.NewArticleRepository,
 	postgres.NewCommentRepository,
+	postgres.NewAuthorRepository,
+	postgres.NewTokenRepository,
+	postgres.NewIdentityRepository,
+	postgres.NewMFARepository,
+	postgres.NewLoginThrottleRepository,
+	postgres.NewLeaseRepository,
 )
 
 var ServiceSet = wire.NewSet(
@@ -32,6 +43,9 @@ var ServiceSet = wire.NewSet(
 	service.NewProfileService,
 	service.NewArticleService,
 	service.NewCommentService,
+	service.NewTokenService,
+	service.NewAdminService,
+	service.NewAccountService,
 )
 
 var ControllerSet = wire.NewSet(
@@ -39,22 +53,35 @@ var ControllerSet = wire.NewSet(
 	controller.NewProfileController,
 	controller.NewArticleController,
 	controller.NewCommentController,
+	controller.NewTokenController,
+	controller.NewAdminController,
+	controller.NewAccountController,
+	controller.NewJwksController,
+	controller.NewPaginator,
 )
 
 var MiddlewareSet = wire.NewSet(
 	middleware.NewCheckJwtMiddleware,
 	middleware.NewEnsureAuthMiddleware,
 	middleware.NewEnsureNotAuthMiddleware,
+	InitEnsureVerifiedMiddleware,
+	middleware.NewScopeMiddleware,
 	middleware.NewTransactionMiddleware,
 	middleware.NewErrorsMiddleware,
 	middleware.NewMetricMiddleware,
 )
 
-func InitRouterUsingSqlite(cfg *config, logger *zap.Logger) (*gin.Engine, error) {
+func InitAppUsingSqlite(cfg *config, logger *zap.Logger) (*app, error) {
 	wire.Build(
 		InitDatasource,
 		InitJwtUtil,
+		InitCursorCodec,
+		InitCommentOptions,
+		InitMailer,
+		InitIdentityProviders,
+		InitScheduler,
 		rest.NewRouter,
+		newApp,
 
 		MiddlewareSet,
 		ControllerSet,
@@ -64,11 +91,17 @@ func InitRouterUsingSqlite(cfg *config, logger *zap.Logger) (*gin.Engine, error)
 	return nil, nil
 }
 
-func InitRouterUsingPostgres(cfg *config, logger *zap.Logger) (*gin.Engine, error) {
+func InitAppUsingPostgres(cfg *config, logger *zap.Logger) (*app, error) {
 	wire.Build(
 		InitDatasource,
 		InitJwtUtil,
+		InitCursorCodec,
+		InitCommentOptions,
+		InitMailer,
+		InitIdentityProviders,
+		InitScheduler,
 		rest.NewRouter,
+		newApp,
 
 		MiddlewareSet,
 		ControllerSet,
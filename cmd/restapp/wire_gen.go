@@ -13,75 +13,154 @@ import (
 	"github.com/KumKeeHyun/gin-realworld/internal/rest"
 	"github.com/KumKeeHyun/gin-realworld/internal/rest/controller"
 	"github.com/KumKeeHyun/gin-realworld/internal/rest/middleware"
-	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 	"go.uber.org/zap"
 )
 
 // Injectors from wire.go:
 
-func InitRouterUsingSqlite(cfg *config, logger *zap.Logger) (*gin.Engine, error) {
-	jwtUtil := InitJwtUtil(cfg)
-	checkJwtMiddleware := middleware.NewCheckJwtMiddleware(jwtUtil, logger)
-	ensureAuthMiddleware := middleware.NewEnsureAuthMiddleware(logger)
-	ensureNotAuthMiddleware := middleware.NewEnsureNotAuthMiddleware(logger)
+func InitAppUsingSqlite(cfg *config, logger *zap.Logger) (*app, error) {
+	jwtUtil, err := InitJwtUtil(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
 	db, err := InitDatasource(cfg, logger)
 	if err != nil {
 		return nil, err
 	}
+	tokenRepository := sqlite.NewTokenRepository(db)
+	userRepository := sqlite.NewUserRepository(db)
+	checkJwtMiddleware := middleware.NewCheckJwtMiddleware(jwtUtil, tokenRepository, userRepository, logger)
+	ensureAuthMiddleware := middleware.NewEnsureAuthMiddleware(logger)
+	ensureNotAuthMiddleware := middleware.NewEnsureNotAuthMiddleware(logger)
+	ensureVerifiedMiddleware := InitEnsureVerifiedMiddleware(cfg, userRepository, logger)
+	scopeMiddleware := middleware.NewScopeMiddleware(logger)
 	transactionMiddleware := middleware.NewTransactionMiddleware(db, logger)
 	errorsMiddleware := middleware.NewErrorsMiddleware(logger)
 	metricMiddleware := middleware.NewMetricMiddleware()
-	userRepository := sqlite.NewUserRepository(db)
 	articleRepository := sqlite.NewArticleRepository(db)
-	authService := service.NewAuthService(userRepository, articleRepository, jwtUtil, logger)
+	identityRepository := sqlite.NewIdentityRepository(db)
+	mfaRepository := sqlite.NewMFARepository(db)
+	loginThrottleRepository := sqlite.NewLoginThrottleRepository(db)
+	authorRepository := sqlite.NewAuthorRepository(db)
+	mailer, err := InitMailer(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	v, err := InitIdentityProviders(cfg)
+	if err != nil {
+		return nil, err
+	}
+	authService := service.NewAuthService(userRepository, authorRepository, tokenRepository, identityRepository, mfaRepository, loginThrottleRepository, mailer, v, jwtUtil, logger)
 	authController := controller.NewAuthController(authService)
 	profileService := service.NewProfileService(userRepository, logger)
 	profileController := controller.NewProfileController(profileService)
 	articleService := service.NewArticleService(articleRepository, userRepository, logger)
-	articleController := controller.NewArticleController(articleService)
+	codec, err := InitCursorCodec(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	paginator := controller.NewPaginator(codec)
+	articleController := controller.NewArticleController(articleService, paginator)
 	commentRepository := sqlite.NewCommentRepository(db)
-	commentService := service.NewCommentService(commentRepository, articleRepository, userRepository, logger)
-	commentController := controller.NewCommentController(commentService)
-	engine := rest.NewRouter(logger, checkJwtMiddleware, ensureAuthMiddleware, ensureNotAuthMiddleware, transactionMiddleware, errorsMiddleware, metricMiddleware, authController, profileController, articleController, commentController)
-	return engine, nil
+	commentOptions, err := InitCommentOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	commentService := service.NewCommentService(commentRepository, articleRepository, userRepository, commentOptions, logger)
+	commentController := controller.NewCommentController(commentService, paginator)
+	tokenService := service.NewTokenService(tokenRepository, logger)
+	tokenController := controller.NewTokenController(tokenService)
+	adminService := service.NewAdminService(userRepository, tokenRepository, logger)
+	adminController := controller.NewAdminController(adminService)
+	accountService := service.NewAccountService(userRepository, articleRepository, authorRepository, commentRepository, tokenRepository, identityRepository, mfaRepository, logger)
+	accountController := controller.NewAccountController(accountService)
+	jwksController := controller.NewJwksController(jwtUtil)
+	engine := rest.NewRouter(logger, checkJwtMiddleware, ensureAuthMiddleware, ensureNotAuthMiddleware, ensureVerifiedMiddleware, scopeMiddleware, transactionMiddleware, errorsMiddleware, metricMiddleware, authController, profileController, articleController, commentController, tokenController, adminController, accountController, jwksController)
+	leaseRepository := sqlite.NewLeaseRepository(db)
+	schedulerScheduler, err := InitScheduler(cfg, db, leaseRepository, articleService, authService, logger)
+	if err != nil {
+		return nil, err
+	}
+	mainApp := newApp(engine, schedulerScheduler, authService, articleService)
+	return mainApp, nil
 }
 
-func InitRouterUsingPostgres(cfg *config, logger *zap.Logger) (*gin.Engine, error) {
-	jwtUtil := InitJwtUtil(cfg)
-	checkJwtMiddleware := middleware.NewCheckJwtMiddleware(jwtUtil, logger)
-	ensureAuthMiddleware := middleware.NewEnsureAuthMiddleware(logger)
-	ensureNotAuthMiddleware := middleware.NewEnsureNotAuthMiddleware(logger)
+func InitAppUsingPostgres(cfg *config, logger *zap.Logger) (*app, error) {
+	jwtUtil, err := InitJwtUtil(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
 	db, err := InitDatasource(cfg, logger)
 	if err != nil {
 		return nil, err
 	}
+	tokenRepository := postgres.NewTokenRepository(db)
+	userRepository := postgres.NewUserRepository(db)
+	checkJwtMiddleware := middleware.NewCheckJwtMiddleware(jwtUtil, tokenRepository, userRepository, logger)
+	ensureAuthMiddleware := middleware.NewEnsureAuthMiddleware(logger)
+	ensureNotAuthMiddleware := middleware.NewEnsureNotAuthMiddleware(logger)
+	ensureVerifiedMiddleware := InitEnsureVerifiedMiddleware(cfg, userRepository, logger)
+	scopeMiddleware := middleware.NewScopeMiddleware(logger)
 	transactionMiddleware := middleware.NewTransactionMiddleware(db, logger)
 	errorsMiddleware := middleware.NewErrorsMiddleware(logger)
 	metricMiddleware := middleware.NewMetricMiddleware()
-	userRepository := postgres.NewUserRepository(db)
 	articleRepository := postgres.NewArticleRepository(db)
-	authService := service.NewAuthService(userRepository, articleRepository, jwtUtil, logger)
+	identityRepository := postgres.NewIdentityRepository(db)
+	mfaRepository := postgres.NewMFARepository(db)
+	loginThrottleRepository := postgres.NewLoginThrottleRepository(db)
+	authorRepository := postgres.NewAuthorRepository(db)
+	mailer, err := InitMailer(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	v, err := InitIdentityProviders(cfg)
+	if err != nil {
+		return nil, err
+	}
+	authService := service.NewAuthService(userRepository, authorRepository, tokenRepository, identityRepository, mfaRepository, loginThrottleRepository, mailer, v, jwtUtil, logger)
 	authController := controller.NewAuthController(authService)
 	profileService := service.NewProfileService(userRepository, logger)
 	profileController := controller.NewProfileController(profileService)
 	articleService := service.NewArticleService(articleRepository, userRepository, logger)
-	articleController := controller.NewArticleController(articleService)
+	codec, err := InitCursorCodec(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	paginator := controller.NewPaginator(codec)
+	articleController := controller.NewArticleController(articleService, paginator)
 	commentRepository := postgres.NewCommentRepository(db)
-	commentService := service.NewCommentService(commentRepository, articleRepository, userRepository, logger)
-	commentController := controller.NewCommentController(commentService)
-	engine := rest.NewRouter(logger, checkJwtMiddleware, ensureAuthMiddleware, ensureNotAuthMiddleware, transactionMiddleware, errorsMiddleware, metricMiddleware, authController, profileController, articleController, commentController)
-	return engine, nil
+	commentOptions, err := InitCommentOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	commentService := service.NewCommentService(commentRepository, articleRepository, userRepository, commentOptions, logger)
+	commentController := controller.NewCommentController(commentService, paginator)
+	tokenService := service.NewTokenService(tokenRepository, logger)
+	tokenController := controller.NewTokenController(tokenService)
+	adminService := service.NewAdminService(userRepository, tokenRepository, logger)
+	adminController := controller.NewAdminController(adminService)
+	accountService := service.NewAccountService(userRepository, articleRepository, authorRepository, commentRepository, tokenRepository, identityRepository, mfaRepository, logger)
+	accountController := controller.NewAccountController(accountService)
+	jwksController := controller.NewJwksController(jwtUtil)
+	engine := rest.NewRouter(logger, checkJwtMiddleware, ensureAuthMiddleware, ensureNotAuthMiddleware, ensureVerifiedMiddleware, scopeMiddleware, transactionMiddleware, errorsMiddleware, metricMiddleware, authController, profileController, articleController, commentController, tokenController, adminController, accountController, jwksController)
+	leaseRepository := postgres.NewLeaseRepository(db)
+	schedulerScheduler, err := InitScheduler(cfg, db, leaseRepository, articleService, authService, logger)
+	if err != nil {
+		return nil, err
+	}
+	mainApp := newApp(engine, schedulerScheduler, authService, articleService)
+	return mainApp, nil
 }
 
 // wire.go:
 
-var SqliteRepositorySet = wire.NewSet(sqlite.NewUserRepository, sqlite.NewArticleRepository, sqlite.NewCommentRepository)
+var SqliteRepositorySet = wire.NewSet(sqlite.NewUserRepository, sqlite.NewArticleRepository, sqlite.NewCommentRepository, sqlite.NewTokenRepository)
 
-var PostgresRepositorySet = wire.NewSet(postgres.NewUserRepository, postgres.NewArticleRepository, postgres.NewCommentRepository)
+var PostgresRepositorySet = wire.NewSet(postgres.NewUserRepository, postgres.NewArticleRepository, postgres.NewCommentRepository, postgres.NewTokenRepository)
 
 var ServiceSet = wire.NewSet(service.NewAuthService, service.NewProfileService, service.NewArticleService, service.NewCommentService)
 
-var ControllerSet = wire.NewSet(controller.NewAuthController, controller.NewProfileController, controller.NewArticleController, controller.NewCommentController)
+var ControllerSet = wire.NewSet(controller.NewAuthController, controller.NewProfileController, controller.NewArticleController, controller.NewCommentController, controller.NewJwksController, controller.NewPaginator)
 
-var MiddlewareSet = wire.NewSet(middleware.NewCheckJwtMiddleware, middleware.NewEnsureAuthMiddleware, middleware.NewEnsureNotAuthMiddleware, middleware.NewTransactionMiddleware, middleware.NewErrorsMiddleware, middleware.NewMetricMiddleware)
+var MiddlewareSet = wire.NewSet(middleware.NewCheckJwtMiddleware, middleware.NewEnsureAuthMiddleware, middleware.NewEnsureNotAuthMiddleware, InitEnsureVerifiedMiddleware, middleware.NewTransactionMiddleware, middleware.NewErrorsMiddleware, middleware.NewMetricMiddleware)
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/KumKeeHyun/gin-realworld/pkg/types"
+	"strings"
+)
+
+const (
+	anonymizedUsernamePrefix = "deleted-"
+	anonymizedEmailDomain    = "@deleted.invalid"
+)
+
+// ReservedUsername reports whether username is kept for placeholders of anonymized users.
+// Others can not take it, otherwise anonymizing the user it belongs to fails on unique constraint.
+func ReservedUsername(username string) bool {
+	return strings.HasPrefix(strings.ToLower(username), anonymizedUsernamePrefix)
+}
+
+// ReservedEmail reports whether email is kept for placeholders of anonymized users, see ReservedUsername.
+func ReservedEmail(email string) bool {
+	return strings.HasSuffix(strings.ToLower(email), anonymizedEmailDomain)
+}
+
+// Anonymized returns user without personal data, it is saved before the user is deleted.
+// Email and username are replaced with unique placeholders, so that they can be registered again.
+// Empty hash never matches any password.
+func (u User) Anonymized() User {
+	u.Email = fmt.Sprintf("%s%d%s", anonymizedUsernamePrefix, u.ID, anonymizedEmailDomain)
+	u.Username = fmt.Sprintf("%s%d", anonymizedUsernamePrefix, u.ID)
+	u.Password = types.Password{String: "", Encrypted: true}
+	u.Bio = ""
+	u.Image = sql.NullString{}
+	u.VerifiedAt = sql.NullTime{}
+	return u
+}
+
+// AccountExport is all data of user, returned by data export request.
+type AccountExport struct {
+	User       User
+	Articles   []Article
+	Comments   []Comment
+	Favorites  []Article
+	Followings []User
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"html"
+	"strings"
 	"time"
 )
 
@@ -14,6 +16,19 @@ type Author struct {
 	Image    sql.NullString
 }
 
+type ArticleStatus string
+
+const (
+	StatusDraft     ArticleStatus = "draft"
+	StatusPublished ArticleStatus = "published"
+	// StatusUnlisted is readable by anyone with the link, but is not listed.
+	StatusUnlisted ArticleStatus = "unlisted"
+)
+
+func (s ArticleStatus) Valid() bool {
+	return s == StatusDraft || s == StatusPublished || s == StatusUnlisted
+}
+
 type Article struct {
 	gorm.Model
 	Slug           string `gorm:"unique;index"`
@@ -22,27 +37,121 @@ type Article struct {
 	Body           string
 	Tags           pq.StringArray `gorm:"type:text[]"`
 	FavoritesCount int
+	Status         ArticleStatus `gorm:"not null;default:published;index"`
+	PublishedAt    sql.NullTime
+	// PublishAt is when non-published article is published by scheduler
+	PublishAt sql.NullTime `gorm:"index"`
 	// Denormalize Article <-> User
 	Author Author `gorm:"embedded;embeddedPrefix:author_"`
 }
 
+// ReadableBy reports whether reader can access article by slug. Draft is only readable by author.
+func (a Article) ReadableBy(readerID uint) bool {
+	return a.Status != StatusDraft || a.Author.ID == readerID
+}
+
+func (a Article) Published() bool {
+	return a.Status == StatusPublished
+}
+
+func (a *Article) Publish(now time.Time) {
+	a.Status = StatusPublished
+	a.PublishedAt = sql.NullTime{Time: now, Valid: true}
+	a.PublishAt = sql.NullTime{}
+}
+
+// SlugHistory keeps previous slug of article, so that links with old slug are redirected to article.
+type SlugHistory struct {
+	gorm.Model
+	Slug      string `gorm:"unique;index"`
+	ArticleID uint   `gorm:"index"`
+}
+
+// ArticleRevision is immutable snapshot of article content. It is appended on create, update and restore.
+type ArticleRevision struct {
+	gorm.Model
+	ArticleID   uint `gorm:"uniqueIndex:idx_article_revision"`
+	Number      int  `gorm:"uniqueIndex:idx_article_revision"`
+	Title       string
+	Description string
+	Body        string
+	EditorID    uint
+	// RestoredFrom is the number of revision restored by this revision
+	RestoredFrom sql.NullInt32
+}
+
+func NewArticleRevision(article Article, number int, editorID uint) ArticleRevision {
+	return ArticleRevision{
+		ArticleID:   article.ID,
+		Number:      number,
+		Title:       article.Title,
+		Description: article.Description,
+		Body:        article.Body,
+		EditorID:    editorID,
+	}
+}
+
+// ContentEquals reports whether title, description and body of articles are the same.
+func (a Article) ContentEquals(other Article) bool {
+	return a.Title == other.Title && a.Description == other.Description && a.Body == other.Body
+}
+
+// RevisionDiff is unified diff of title, description and body between two revisions.
+type RevisionDiff struct {
+	From    int
+	To      int
+	Unified string
+}
+
+// Favorite is unique for user and article, which is deleted permanently so that it can be created again.
 type Favorite struct {
 	gorm.Model
-	UserID    uint `gorm:"index:idx_user_article"`
+	UserID    uint `gorm:"uniqueIndex:idx_favorite_user_article"`
 	User      User
-	ArticleID uint `gorm:"index:idx_user_article"`
+	ArticleID uint `gorm:"uniqueIndex:idx_favorite_user_article"`
 	Article   Article
 }
 
+// DeletedCommentBody is shown instead of tombstoned comment.
+const DeletedCommentBody = "[deleted]"
+
 type Comment struct {
 	gorm.Model
 	Body      string
 	ArticleID uint
 	Article   Article
+	// ParentID is id of comment which comment replies to, nil for top level comment
+	ParentID *uint `gorm:"index"`
+	// Depth is the number of ancestors, 0 for top level comment
+	Depth int `gorm:"not null;default:0"`
+	// Tombstoned comment is deleted but kept without body, so that its replies stay in thread
+	Tombstoned bool `gorm:"not null;default:false"`
+	// EditedAt is when body was last edited, previous bodies are kept as CommentRevision
+	EditedAt sql.NullTime
 	// Denormalize Comment <-> User
 	Author Author `gorm:"embedded;embeddedPrefix:author_"`
 }
 
+// CommentRevision is previous body of comment replaced by edit. It is only visible to moderators.
+type CommentRevision struct {
+	gorm.Model
+	CommentID uint `gorm:"index"`
+	Body      string
+	EditorID  uint
+}
+
+// Edit replaces body of comment, and returns revision keeping the previous body.
+func (c *Comment) Edit(body string, editorID uint, now time.Time) CommentRevision {
+	revision := CommentRevision{
+		CommentID: c.ID,
+		Body:      c.Body,
+		EditorID:  editorID,
+	}
+	c.Body = body
+	c.EditedAt = sql.NullTime{Time: now, Valid: true}
+	return revision
+}
+
 type ArticleView struct {
 	ID        uint
 	CreatedAt time.Time
@@ -56,6 +165,11 @@ type ArticleView struct {
 	Tags           pq.StringArray
 	Favorited      bool
 	FavoritesCount int
+	Status         ArticleStatus
+	PublishedAt    sql.NullTime
+	PublishAt      sql.NullTime
+	// Snippet is highlighted part of article matched by full-text search, it is escaped html
+	Snippet string
 
 	AuthorID        uint
 	AuthorUsername  string
@@ -64,6 +178,20 @@ type ArticleView struct {
 	AuthorFollowing bool
 }
 
+// Full-text search highlights matched words of snippet with these marks.
+// They are private use characters, so they are not taken for html of article.
+const (
+	SnippetStartMark = "\uE000"
+	SnippetStopMark  = "\uE001"
+)
+
+var snippetReplacer = strings.NewReplacer(SnippetStartMark, "<mark>", SnippetStopMark, "</mark>")
+
+// HighlightSnippet escapes html written by author in snippet, then replaces marks with <mark> tags.
+func HighlightSnippet(snippet string) string {
+	return snippetReplacer.Replace(html.EscapeString(snippet))
+}
+
 func NewArticleView(article Article, favorited, following bool) ArticleView {
 	return ArticleView{
 		ID:              article.ID,
@@ -77,6 +205,9 @@ func NewArticleView(article Article, favorited, following bool) ArticleView {
 		Tags:            article.Tags,
 		Favorited:       favorited,
 		FavoritesCount:  article.FavoritesCount,
+		Status:          article.Status,
+		PublishedAt:     article.PublishedAt,
+		PublishAt:       article.PublishAt,
 		AuthorID:        article.Author.ID,
 		AuthorUsername:  article.Author.Username,
 		AuthorBio:       article.Author.Bio,
@@ -93,14 +224,36 @@ type CommentView struct {
 	Body      string
 
 	ArticleID       uint
+	ParentID        *uint
+	Tombstoned      bool
+	EditedAt        sql.NullTime
 	AuthorID        uint
 	AuthorUsername  string
 	AuthorBio       string
 	AuthorImage     sql.NullString
 	AuthorFollowing bool
+	// Replies are nested only when comments are listed as tree
+	Replies []CommentView
+	// RepliesCount is the number of direct replies, which is larger than Replies if thread is cut.
+	// It is set only when comments are listed as tree.
+	RepliesCount int
 }
 
+// NewCommentView hides body and author of tombstoned comment.
 func NewCommentView(comment Comment, following bool) CommentView {
+	if comment.Tombstoned {
+		return CommentView{
+			ID:             comment.ID,
+			CreatedAt:      comment.CreatedAt,
+			UpdatedAt:      comment.UpdatedAt,
+			DeletedAt:      comment.DeletedAt,
+			Body:           DeletedCommentBody,
+			ArticleID:      comment.ArticleID,
+			ParentID:       comment.ParentID,
+			Tombstoned:     true,
+			AuthorUsername: DeletedCommentBody,
+		}
+	}
 	return CommentView{
 		ID:              comment.ID,
 		CreatedAt:       comment.CreatedAt,
@@ -108,6 +261,8 @@ func NewCommentView(comment Comment, following bool) CommentView {
 		DeletedAt:       comment.DeletedAt,
 		Body:            comment.Body,
 		ArticleID:       comment.ArticleID,
+		ParentID:        comment.ParentID,
+		EditedAt:        comment.EditedAt,
 		AuthorID:        comment.Author.ID,
 		AuthorUsername:  comment.Author.Username,
 		AuthorBio:       comment.Author.Bio,
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"database/sql"
+	"github.com/KumKeeHyun/gin-realworld/pkg/crypto"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Identity links an account of external identity provider to User.
+type Identity struct {
+	gorm.Model
+	UserID   uint   `gorm:"index"`
+	Provider string `gorm:"uniqueIndex:idx_provider_subject"`
+	Subject  string `gorm:"uniqueIndex:idx_provider_subject"`
+	Email    string
+}
+
+// ExternalIdentity is the user information asserted by identity provider.
+type ExternalIdentity struct {
+	Provider          string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+}
+
+const LoginStateTTL = 10 * time.Minute
+
+// LoginState keeps the secrets of authorization code flow between redirect and callback.
+// State is sent to identity provider, so only its hash is stored.
+type LoginState struct {
+	gorm.Model
+	Provider     string
+	StateHash    string `gorm:"unique;index"`
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	UsedAt       sql.NullTime
+	State        string `gorm:"-:all"`
+}
+
+func NewLoginState(provider string) (LoginState, error) {
+	state, err := crypto.GenerateToken()
+	if err != nil {
+		return LoginState{}, err
+	}
+	nonce, err := crypto.GenerateToken()
+	if err != nil {
+		return LoginState{}, err
+	}
+	verifier, err := crypto.GenerateToken()
+	if err != nil {
+		return LoginState{}, err
+	}
+	return LoginState{
+		Provider:     provider,
+		StateHash:    crypto.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(LoginStateTTL),
+		State:        state,
+	}, nil
+}
+
+func (s LoginState) Usable() bool {
+	return !s.UsedAt.Valid && time.Now().Before(s.ExpiresAt)
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// Lease is held by one replica at a time, so that background jobs run only once across replicas.
+// Holder renews the lease on each run, others can take it over after it expires.
+type Lease struct {
+	ID        uint   `gorm:"primarykey"`
+	Name      string `gorm:"unique;index"`
+	Holder    string
+	ExpiresAt time.Time
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// AccountLoginThreshold is the number of failures of an account after which login is locked for a while.
+	AccountLoginThreshold = 5
+	// IPLoginThreshold is higher than account's, because many users can share an address behind NAT.
+	IPLoginThreshold = 20
+
+	LoginBackoffBase   = time.Second
+	LoginLockoutMax    = 15 * time.Minute
+	LoginFailureWindow = time.Hour
+)
+
+// LoginThrottle counts failed logins of an account or a client address.
+// Key of unknown email is tracked as well, so that lockout does not reveal whether an account exists.
+type LoginThrottle struct {
+	ID            uint   `gorm:"primarykey"`
+	Key           string `gorm:"unique;index"`
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   time.Time
+}
+
+func AccountLoginKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func IPLoginKey(ip string) string {
+	return "ip:" + ip
+}
+
+func (t LoginThrottle) Locked(now time.Time) bool {
+	return now.Before(t.LockedUntil)
+}
+
+// Stale reports whether the last failure is old enough to start counting again.
+func (t LoginThrottle) Stale(now time.Time) bool {
+	return now.Sub(t.LastFailureAt) > LoginFailureWindow
+}
+
+// Backoff returns how long login is locked after the current failures.
+// It doubles on each failure over threshold, up to LoginLockoutMax.
+func (t LoginThrottle) Backoff(threshold int) time.Duration {
+	over := t.Failures - threshold
+	if over < 0 {
+		return 0
+	}
+	if over >= 20 {
+		return LoginLockoutMax
+	}
+	backoff := LoginBackoffBase << over
+	if backoff > LoginLockoutMax {
+		return LoginLockoutMax
+	}
+	return backoff
+}
+
+func LoginThreshold(key string) int {
+	if strings.HasPrefix(key, "ip:") {
+		return IPLoginThreshold
+	}
+	return AccountLoginThreshold
+}
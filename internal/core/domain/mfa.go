@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"github.com/KumKeeHyun/gin-realworld/pkg/crypto"
+	"github.com/KumKeeHyun/gin-realworld/pkg/totp"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+const (
+	TOTPIssuer = "Conduit"
+	// TOTPSkew accepts codes of one step before and after, for clock drift of devices
+	TOTPSkew = 1
+
+	RecoveryCodeCount = 10
+	MFAChallengeTTL   = 5 * time.Minute
+)
+
+// TwoFactor is TOTP setting of user. It is enabled only after user confirms a code.
+// Secret is kept in plain text because it is needed to compute codes.
+type TwoFactor struct {
+	gorm.Model
+	UserID       uint `gorm:"unique;index"`
+	Secret       string
+	EnabledAt    sql.NullTime
+	LastUsedStep int64
+}
+
+func NewTwoFactor(userID uint) (TwoFactor, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return TwoFactor{}, err
+	}
+	return TwoFactor{
+		UserID: userID,
+		Secret: secret,
+	}, nil
+}
+
+func (f TwoFactor) Enabled() bool {
+	return f.EnabledAt.Valid
+}
+
+func (f *TwoFactor) Enable() {
+	f.EnabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+}
+
+// Validate returns the matched time step of code.
+func (f TwoFactor) Validate(code string) (int64, bool) {
+	step, ok := totp.Validate(f.Secret, code, time.Now(), TOTPSkew)
+	if !ok || step <= f.LastUsedStep {
+		return 0, false
+	}
+	return step, true
+}
+
+func (f TwoFactor) URI(account string) string {
+	return totp.URI(TOTPIssuer, account, f.Secret)
+}
+
+type TOTPEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// RecoveryCode is a single use code for the case user lost the authenticator.
+type RecoveryCode struct {
+	gorm.Model
+	UserID   uint   `gorm:"index"`
+	CodeHash string `gorm:"index"`
+	UsedAt   sql.NullTime
+}
+
+// NewRecoveryCodes returns codes to show user once and their hashes to store.
+func NewRecoveryCodes(userID uint) ([]string, []RecoveryCode, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	recoveryCodes := make([]RecoveryCode, 0, RecoveryCodeCount)
+	for i := 0; i < RecoveryCodeCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		code := hex.EncodeToString(b)
+		code = code[:5] + "-" + code[5:]
+		codes = append(codes, code)
+		recoveryCodes = append(recoveryCodes, RecoveryCode{
+			UserID:   userID,
+			CodeHash: HashRecoveryCode(code),
+		})
+	}
+	return codes, recoveryCodes, nil
+}
+
+// HashRecoveryCode ignores case and separators user may type differently.
+func HashRecoveryCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+	return crypto.HashToken(normalized)
+}
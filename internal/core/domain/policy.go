@@ -0,0 +1,76 @@
+package domain
+
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+var Roles = []Role{RoleUser, RoleModerator, RoleAdmin}
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleUser, RoleModerator, RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// rank orders roles, each role is allowed everything lower role is allowed.
+// Empty role of tokens issued before roles existed is treated as user.
+func (r Role) rank() int {
+	switch r {
+	case RoleModerator:
+		return 1
+	case RoleAdmin:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func (r Role) AtLeast(role Role) bool {
+	return r.rank() >= role.rank()
+}
+
+type Action string
+
+const (
+	ActionUpdateArticle Action = "article:update"
+	ActionDeleteArticle Action = "article:delete"
+	ActionUpdateComment Action = "comment:update"
+	ActionDeleteComment Action = "comment:delete"
+	// ActionViewCommentHistory is to see previous bodies of edited comments
+	ActionViewCommentHistory Action = "comment:history"
+	ActionManageUsers        Action = "user:manage"
+)
+
+// Actor is the user requesting an action, services check it against the policy below.
+type Actor struct {
+	ID   uint
+	Role Role
+}
+
+// Can is the single authorization policy of services.
+// ownerID is the owner of the target content, it is ignored for actions not on content.
+//
+//   - owner can update and delete own content
+//   - moderator can delete any content and see edit history of comments, but can not rewrite others' words
+//   - admin can do everything moderator can, and manage users
+func (a Actor) Can(action Action, ownerID uint) bool {
+	switch action {
+	case ActionUpdateArticle, ActionUpdateComment:
+		return a.ID == ownerID
+	case ActionDeleteArticle, ActionDeleteComment:
+		return a.ID == ownerID || a.Role.AtLeast(RoleModerator)
+	case ActionViewCommentHistory:
+		return a.Role.AtLeast(RoleModerator)
+	case ActionManageUsers:
+		return a.Role.AtLeast(RoleAdmin)
+	default:
+		return false
+	}
+}
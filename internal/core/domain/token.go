@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"database/sql"
+	"github.com/KumKeeHyun/gin-realworld/pkg/crypto"
+	"github.com/lib/pq"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+	"time"
+)
+
+const RefreshTokenTTL = 14 * 24 * time.Hour
+
+type RefreshToken struct {
+	gorm.Model
+	UserID    uint   `gorm:"index"`
+	TokenHash string `gorm:"unique;index"`
+	ExpiresAt time.Time
+	RevokedAt sql.NullTime
+	Token     string `gorm:"-:all"`
+}
+
+func NewRefreshToken(userID uint) (RefreshToken, error) {
+	token, err := crypto.GenerateToken()
+	if err != nil {
+		return RefreshToken{}, err
+	}
+	return RefreshToken{
+		UserID:    userID,
+		TokenHash: crypto.HashToken(token),
+		ExpiresAt: time.Now().Add(RefreshTokenTTL),
+		Token:     token,
+	}, nil
+}
+
+func (t RefreshToken) Revoked() bool {
+	return t.RevokedAt.Valid
+}
+
+func (t RefreshToken) Expired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// DeniedToken is an access token revoked before its expiration, identified by jti.
+type DeniedToken struct {
+	gorm.Model
+	JTI       string `gorm:"unique;index"`
+	ExpiresAt time.Time
+}
+
+type TokenPurpose string
+
+const (
+	PurposePasswordReset     TokenPurpose = "password_reset"
+	PurposeEmailVerification TokenPurpose = "email_verification"
+	PurposeMFAChallenge      TokenPurpose = "mfa_challenge"
+)
+
+const (
+	PasswordResetTokenTTL     = time.Hour
+	EmailVerificationTokenTTL = 24 * time.Hour
+)
+
+// OneTimeToken is a single-use token sent to user by mail.
+type OneTimeToken struct {
+	gorm.Model
+	UserID    uint         `gorm:"index"`
+	Purpose   TokenPurpose `gorm:"index"`
+	TokenHash string       `gorm:"unique;index"`
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+	Token     string `gorm:"-:all"`
+}
+
+func NewOneTimeToken(userID uint, purpose TokenPurpose, ttl time.Duration) (OneTimeToken, error) {
+	token, err := crypto.GenerateToken()
+	if err != nil {
+		return OneTimeToken{}, err
+	}
+	return OneTimeToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: crypto.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+		Token:     token,
+	}, nil
+}
+
+func (t OneTimeToken) Usable() bool {
+	return !t.UsedAt.Valid && time.Now().Before(t.ExpiresAt)
+}
+
+// PersonalAccessTokenPrefix distinguishes personal access tokens from jwt in Authorization header.
+const PersonalAccessTokenPrefix = "pat_"
+
+const (
+	ScopeArticlesWrite  = "articles:write"
+	ScopeCommentsWrite  = "comments:write"
+	ScopeFavoritesWrite = "favorites:write"
+	ScopeProfilesWrite  = "profiles:write"
+)
+
+var Scopes = []string{ScopeArticlesWrite, ScopeCommentsWrite, ScopeFavoritesWrite, ScopeProfilesWrite}
+
+// PersonalAccessToken is a long-lived token for scripts. It can read anything user can,
+// but writes are limited to its scopes.
+type PersonalAccessToken struct {
+	gorm.Model
+	UserID     uint `gorm:"index"`
+	Name       string
+	TokenHash  string         `gorm:"unique;index"`
+	Scopes     pq.StringArray `gorm:"type:text[]"`
+	ExpiresAt  sql.NullTime
+	LastUsedAt sql.NullTime
+	Token      string `gorm:"-:all"`
+}
+
+func NewPersonalAccessToken(userID uint, name string, scopes []string, expiresAt *time.Time) (PersonalAccessToken, error) {
+	token, err := crypto.GenerateToken()
+	if err != nil {
+		return PersonalAccessToken{}, err
+	}
+	token = PersonalAccessTokenPrefix + token
+	pat := PersonalAccessToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: crypto.HashToken(token),
+		Scopes:    lo.Uniq(scopes),
+		Token:     token,
+	}
+	if expiresAt != nil {
+		pat.ExpiresAt = sql.NullTime{Time: *expiresAt, Valid: true}
+	}
+	return pat, nil
+}
+
+func (t PersonalAccessToken) Expired() bool {
+	return t.ExpiresAt.Valid && time.Now().After(t.ExpiresAt.Time)
+}
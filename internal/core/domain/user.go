@@ -5,6 +5,7 @@ import (
 	"github.com/KumKeeHyun/gin-realworld/pkg/crypto"
 	"github.com/KumKeeHyun/gin-realworld/pkg/types"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/samber/lo"
 	"gorm.io/gorm"
 	"time"
@@ -12,12 +13,30 @@ import (
 
 type User struct {
 	gorm.Model
-	Email    string `gorm:"unique;index"`
-	Username string `gorm:"unique;index"`
-	Password types.Password
-	Bio      string
-	Image    sql.NullString
-	Token    string `gorm:"-:all"`
+	Email      string `gorm:"unique;index"`
+	Username   string `gorm:"unique;index"`
+	Password   types.Password
+	Bio        string
+	Image      sql.NullString
+	VerifiedAt sql.NullTime
+	Role       Role   `gorm:"not null;default:user"`
+	Token      string `gorm:"-:all"`
+	// RefreshToken is set only when new tokens are issued
+	RefreshToken string `gorm:"-:all"`
+	// MFAChallenge is set instead of tokens when login needs second factor
+	MFAChallenge string `gorm:"-:all"`
+}
+
+// BeforeCreate sets role explicitly, so that created user has the role without reading it back.
+func (u *User) BeforeCreate(*gorm.DB) error {
+	if u.Role == "" {
+		u.Role = RoleUser
+	}
+	return nil
+}
+
+func (u User) Actor() Actor {
+	return Actor{ID: u.ID, Role: u.Role}
 }
 
 func (u *User) UpdatePassword(password string) {
@@ -28,6 +47,19 @@ func (u User) ValidPassword(password string) bool {
 	return crypto.CheckHashAndPassword(u.Password.String, password)
 }
 
+// PasswordOutdated reports whether password is hashed by old algorithm or parameters.
+func (u User) PasswordOutdated() bool {
+	return crypto.NeedsRehash(u.Password.String)
+}
+
+func (u User) Verified() bool {
+	return u.VerifiedAt.Valid
+}
+
+func (u *User) Verify() {
+	u.VerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+}
+
 type Follow struct {
 	gorm.Model
 	FollowerID  uint `gorm:"index:idx_follower_ing"`
@@ -47,6 +79,7 @@ type Profile struct {
 func (u User) AccessClaim() AccessClaim {
 	return AccessClaim{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Issuer:    "go-gin-realworld",
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
@@ -56,6 +89,8 @@ func (u User) AccessClaim() AccessClaim {
 		Username: u.Username,
 		Bio:      u.Bio,
 		Image:    lo.If(u.Image.Valid, &u.Image.String).Else(nil),
+		Verified: u.Verified(),
+		Role:     u.Role,
 	}
 }
 
@@ -66,4 +101,10 @@ type AccessClaim struct {
 	Username string  `json:"username"`
 	Bio      string  `json:"bio"`
 	Image    *string `json:"image"`
+	Verified bool    `json:"verified"`
+	Role     Role    `json:"role,omitempty"`
+}
+
+func (c AccessClaim) Actor() Actor {
+	return Actor{ID: c.UID, Role: c.Role}
 }
@@ -0,0 +1,14 @@
+package ports
+
+//go:generate mockgen -destination=./mock_ports/mock_identity.go -package=mock_ports github.com/KumKeeHyun/gin-realworld/internal/core/ports IdentityProvider
+
+import "github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+
+// IdentityProvider is an external login provider using authorization code flow with PKCE.
+type IdentityProvider interface {
+	Name() string
+	// AuthCodeURL returns the url user agent is redirected to. Code challenge is derived from codeVerifier.
+	AuthCodeURL(state, nonce, codeVerifier string) (string, error)
+	// Exchange redeems code and returns the verified identity.
+	Exchange(code, codeVerifier, nonce string) (domain.ExternalIdentity, error)
+}
@@ -0,0 +1,13 @@
+package ports
+
+//go:generate mockgen -destination=./mock_ports/mock_mailer.go -package=mock_ports github.com/KumKeeHyun/gin-realworld/internal/core/ports Mailer
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(mail Mail) error
+}
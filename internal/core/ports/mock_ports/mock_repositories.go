@@ -1,11 +1,12 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/KumKeeHyun/gin-realworld/internal/core/ports (interfaces: UserRepository,ArticleRepository,CommentRepository)
+// Source: github.com/KumKeeHyun/gin-realworld/internal/core/ports (interfaces: UserRepository,ArticleRepository,CommentRepository,AuthorRepository,TokenRepository,IdentityRepository,MFARepository,LoginThrottleRepository,LeaseRepository)
 
 // Package mock_ports is a generated GoMock package.
 package mock_ports
 
 import (
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/KumKeeHyun/gin-realworld/internal/core/domain"
 	ports "github.com/KumKeeHyun/gin-realworld/internal/core/ports"
@@ -51,6 +52,20 @@ func (mr *MockUserRepositoryMockRecorder) CreateFollow(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFollow", reflect.TypeOf((*MockUserRepository)(nil).CreateFollow), arg0, arg1)
 }
 
+// Delete mocks base method.
+func (m *MockUserRepository) Delete(arg0 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUserRepositoryMockRecorder) Delete(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserRepository)(nil).Delete), arg0)
+}
+
 // DeleteFollow mocks base method.
 func (m *MockUserRepository) DeleteFollow(arg0, arg1 uint) error {
 	m.ctrl.T.Helper()
@@ -65,6 +80,35 @@ func (mr *MockUserRepositoryMockRecorder) DeleteFollow(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFollow", reflect.TypeOf((*MockUserRepository)(nil).DeleteFollow), arg0, arg1)
 }
 
+// DeleteFollows mocks base method.
+func (m *MockUserRepository) DeleteFollows(arg0 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFollows", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFollows indicates an expected call of DeleteFollows.
+func (mr *MockUserRepositoryMockRecorder) DeleteFollows(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFollows", reflect.TypeOf((*MockUserRepository)(nil).DeleteFollows), arg0)
+}
+
+// FindAll mocks base method.
+func (m *MockUserRepository) FindAll(arg0 ports.Pageable) ([]domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", arg0)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockUserRepositoryMockRecorder) FindAll(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockUserRepository)(nil).FindAll), arg0)
+}
+
 // FindByEmail mocks base method.
 func (m *MockUserRepository) FindByEmail(arg0 string) (domain.User, error) {
 	m.ctrl.T.Helper()
@@ -140,6 +184,21 @@ func (mr *MockUserRepositoryMockRecorder) FindFollow(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFollow", reflect.TypeOf((*MockUserRepository)(nil).FindFollow), arg0, arg1)
 }
 
+// FindFollowings mocks base method.
+func (m *MockUserRepository) FindFollowings(arg0 uint) ([]domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFollowings", arg0)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFollowings indicates an expected call of FindFollowings.
+func (mr *MockUserRepositoryMockRecorder) FindFollowings(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFollowings", reflect.TypeOf((*MockUserRepository)(nil).FindFollowings), arg0)
+}
+
 // FindFollows mocks base method.
 func (m *MockUserRepository) FindFollows(arg0 uint, arg1 []uint) ([]domain.Follow, error) {
 	m.ctrl.T.Helper()
@@ -185,6 +244,20 @@ func (mr *MockUserRepositoryMockRecorder) Save(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockUserRepository)(nil).Save), arg0)
 }
 
+// UpdateRole mocks base method.
+func (m *MockUserRepository) UpdateRole(arg0 uint, arg1 domain.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockUserRepositoryMockRecorder) UpdateRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockUserRepository)(nil).UpdateRole), arg0, arg1)
+}
+
 // WithTx mocks base method.
 func (m *MockUserRepository) WithTx(arg0 *gorm.DB) ports.UserRepository {
 	m.ctrl.T.Helper()
@@ -223,10 +296,10 @@ func (m *MockArticleRepository) EXPECT() *MockArticleRepositoryMockRecorder {
 }
 
 // CreateFavorite mocks base method.
-func (m *MockArticleRepository) CreateFavorite(arg0, arg1 uint) (domain.Favorite, error) {
+func (m *MockArticleRepository) CreateFavorite(arg0, arg1 uint) (int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateFavorite", arg0, arg1)
-	ret0, _ := ret[0].(domain.Favorite)
+	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -252,11 +325,12 @@ func (mr *MockArticleRepositoryMockRecorder) DeleteBySlug(arg0 interface{}) *gom
 }
 
 // DeleteFavorite mocks base method.
-func (m *MockArticleRepository) DeleteFavorite(arg0, arg1 uint) error {
+func (m *MockArticleRepository) DeleteFavorite(arg0, arg1 uint) (int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteFavorite", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteFavorite indicates an expected call of DeleteFavorite.
@@ -265,15 +339,74 @@ func (mr *MockArticleRepositoryMockRecorder) DeleteFavorite(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFavorite", reflect.TypeOf((*MockArticleRepository)(nil).DeleteFavorite), arg0, arg1)
 }
 
-// FindBySearchConditions mocks base method.
-func (m *MockArticleRepository) FindBySearchConditions(arg0 ports.ArticleSearchConditions) ([]domain.Article, error) {
+// DeleteFavorites mocks base method.
+func (m *MockArticleRepository) DeleteFavorites(arg0 uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindBySearchConditions", arg0)
+	ret := m.ctrl.Call(m, "DeleteFavorites", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFavorites indicates an expected call of DeleteFavorites.
+func (mr *MockArticleRepositoryMockRecorder) DeleteFavorites(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFavorites", reflect.TypeOf((*MockArticleRepository)(nil).DeleteFavorites), arg0)
+}
+
+// DeleteSlugHistory mocks base method.
+func (m *MockArticleRepository) DeleteSlugHistory(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSlugHistory", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSlugHistory indicates an expected call of DeleteSlugHistory.
+func (mr *MockArticleRepositoryMockRecorder) DeleteSlugHistory(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSlugHistory", reflect.TypeOf((*MockArticleRepository)(nil).DeleteSlugHistory), arg0)
+}
+
+// FindByAuthor mocks base method.
+func (m *MockArticleRepository) FindByAuthor(arg0 uint) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByAuthor", arg0)
 	ret0, _ := ret[0].([]domain.Article)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// FindByAuthor indicates an expected call of FindByAuthor.
+func (mr *MockArticleRepositoryMockRecorder) FindByAuthor(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByAuthor", reflect.TypeOf((*MockArticleRepository)(nil).FindByAuthor), arg0)
+}
+
+// FindByOldSlug mocks base method.
+func (m *MockArticleRepository) FindByOldSlug(arg0 string) (domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByOldSlug", arg0)
+	ret0, _ := ret[0].(domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByOldSlug indicates an expected call of FindByOldSlug.
+func (mr *MockArticleRepositoryMockRecorder) FindByOldSlug(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOldSlug", reflect.TypeOf((*MockArticleRepository)(nil).FindByOldSlug), arg0)
+}
+
+// FindBySearchConditions mocks base method.
+func (m *MockArticleRepository) FindBySearchConditions(arg0 ports.ArticleSearchConditions) ([]domain.Article, ports.Page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBySearchConditions", arg0)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(ports.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // FindBySearchConditions indicates an expected call of FindBySearchConditions.
 func (mr *MockArticleRepositoryMockRecorder) FindBySearchConditions(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
@@ -295,6 +428,22 @@ func (mr *MockArticleRepositoryMockRecorder) FindBySlug(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBySlug", reflect.TypeOf((*MockArticleRepository)(nil).FindBySlug), arg0)
 }
 
+// FindDrafts mocks base method.
+func (m *MockArticleRepository) FindDrafts(arg0 uint, arg1 ports.Pageable) ([]domain.Article, ports.Page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDrafts", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(ports.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindDrafts indicates an expected call of FindDrafts.
+func (mr *MockArticleRepositoryMockRecorder) FindDrafts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDrafts", reflect.TypeOf((*MockArticleRepository)(nil).FindDrafts), arg0, arg1)
+}
+
 // FindFavorite mocks base method.
 func (m *MockArticleRepository) FindFavorite(arg0, arg1 uint) (domain.Favorite, error) {
 	m.ctrl.T.Helper()
@@ -310,6 +459,21 @@ func (mr *MockArticleRepositoryMockRecorder) FindFavorite(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFavorite", reflect.TypeOf((*MockArticleRepository)(nil).FindFavorite), arg0, arg1)
 }
 
+// FindFavoritedArticles mocks base method.
+func (m *MockArticleRepository) FindFavoritedArticles(arg0 uint) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFavoritedArticles", arg0)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFavoritedArticles indicates an expected call of FindFavoritedArticles.
+func (mr *MockArticleRepositoryMockRecorder) FindFavoritedArticles(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFavoritedArticles", reflect.TypeOf((*MockArticleRepository)(nil).FindFavoritedArticles), arg0)
+}
+
 // FindFavorites mocks base method.
 func (m *MockArticleRepository) FindFavorites(arg0 uint, arg1 []uint) ([]domain.Favorite, error) {
 	m.ctrl.T.Helper()
@@ -326,12 +490,13 @@ func (mr *MockArticleRepositoryMockRecorder) FindFavorites(arg0, arg1 interface{
 }
 
 // FindFeed mocks base method.
-func (m *MockArticleRepository) FindFeed(arg0 uint, arg1 ports.Pageable) ([]domain.Article, error) {
+func (m *MockArticleRepository) FindFeed(arg0 uint, arg1 ports.Pageable) ([]domain.Article, ports.Page, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "FindFeed", arg0, arg1)
 	ret0, _ := ret[0].([]domain.Article)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(ports.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // FindFeed indicates an expected call of FindFeed.
@@ -340,19 +505,109 @@ func (mr *MockArticleRepositoryMockRecorder) FindFeed(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFeed", reflect.TypeOf((*MockArticleRepository)(nil).FindFeed), arg0, arg1)
 }
 
+// FindLatestRevision mocks base method.
+func (m *MockArticleRepository) FindLatestRevision(arg0 uint) (domain.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLatestRevision", arg0)
+	ret0, _ := ret[0].(domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLatestRevision indicates an expected call of FindLatestRevision.
+func (mr *MockArticleRepositoryMockRecorder) FindLatestRevision(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLatestRevision", reflect.TypeOf((*MockArticleRepository)(nil).FindLatestRevision), arg0)
+}
+
+// FindRevision mocks base method.
+func (m *MockArticleRepository) FindRevision(arg0 uint, arg1 int) (domain.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRevision", arg0, arg1)
+	ret0, _ := ret[0].(domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRevision indicates an expected call of FindRevision.
+func (mr *MockArticleRepositoryMockRecorder) FindRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRevision", reflect.TypeOf((*MockArticleRepository)(nil).FindRevision), arg0, arg1)
+}
+
+// FindRevisions mocks base method.
+func (m *MockArticleRepository) FindRevisions(arg0 uint) ([]domain.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRevisions", arg0)
+	ret0, _ := ret[0].([]domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRevisions indicates an expected call of FindRevisions.
+func (mr *MockArticleRepositoryMockRecorder) FindRevisions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRevisions", reflect.TypeOf((*MockArticleRepository)(nil).FindRevisions), arg0)
+}
+
+// FindSnippets mocks base method.
+func (m *MockArticleRepository) FindSnippets(arg0 string, arg1 []uint) (map[uint]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSnippets", arg0, arg1)
+	ret0, _ := ret[0].(map[uint]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSnippets indicates an expected call of FindSnippets.
+func (mr *MockArticleRepositoryMockRecorder) FindSnippets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSnippets", reflect.TypeOf((*MockArticleRepository)(nil).FindSnippets), arg0, arg1)
+}
+
 // FindTags mocks base method.
-func (m *MockArticleRepository) FindTags() ([]string, error) {
+func (m *MockArticleRepository) FindTags(arg0 uint) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindTags")
+	ret := m.ctrl.Call(m, "FindTags", arg0)
 	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindTags indicates an expected call of FindTags.
-func (mr *MockArticleRepositoryMockRecorder) FindTags() *gomock.Call {
+func (mr *MockArticleRepositoryMockRecorder) FindTags(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTags", reflect.TypeOf((*MockArticleRepository)(nil).FindTags), arg0)
+}
+
+// PublishDueArticles mocks base method.
+func (m *MockArticleRepository) PublishDueArticles(arg0 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDueArticles", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishDueArticles indicates an expected call of PublishDueArticles.
+func (mr *MockArticleRepositoryMockRecorder) PublishDueArticles(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDueArticles", reflect.TypeOf((*MockArticleRepository)(nil).PublishDueArticles), arg0)
+}
+
+// ReconcileFavoritesCounts mocks base method.
+func (m *MockArticleRepository) ReconcileFavoritesCounts() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileFavoritesCounts")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileFavoritesCounts indicates an expected call of ReconcileFavoritesCounts.
+func (mr *MockArticleRepositoryMockRecorder) ReconcileFavoritesCounts() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTags", reflect.TypeOf((*MockArticleRepository)(nil).FindTags))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileFavoritesCounts", reflect.TypeOf((*MockArticleRepository)(nil).ReconcileFavoritesCounts))
 }
 
 // Save mocks base method.
@@ -370,18 +625,34 @@ func (mr *MockArticleRepositoryMockRecorder) Save(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockArticleRepository)(nil).Save), arg0)
 }
 
-// UpdateAuthorInfo mocks base method.
-func (m *MockArticleRepository) UpdateAuthorInfo(arg0 domain.User) error {
+// SaveRevision mocks base method.
+func (m *MockArticleRepository) SaveRevision(arg0 domain.ArticleRevision) (domain.ArticleRevision, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAuthorInfo", arg0)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "SaveRevision", arg0)
+	ret0, _ := ret[0].(domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateAuthorInfo indicates an expected call of UpdateAuthorInfo.
-func (mr *MockArticleRepositoryMockRecorder) UpdateAuthorInfo(arg0 interface{}) *gomock.Call {
+// SaveRevision indicates an expected call of SaveRevision.
+func (mr *MockArticleRepositoryMockRecorder) SaveRevision(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRevision", reflect.TypeOf((*MockArticleRepository)(nil).SaveRevision), arg0)
+}
+
+// SaveSlugHistory mocks base method.
+func (m *MockArticleRepository) SaveSlugHistory(arg0 domain.SlugHistory) (domain.SlugHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSlugHistory", arg0)
+	ret0, _ := ret[0].(domain.SlugHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveSlugHistory indicates an expected call of SaveSlugHistory.
+func (mr *MockArticleRepositoryMockRecorder) SaveSlugHistory(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthorInfo", reflect.TypeOf((*MockArticleRepository)(nil).UpdateAuthorInfo), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSlugHistory", reflect.TypeOf((*MockArticleRepository)(nil).SaveSlugHistory), arg0)
 }
 
 // WithTx mocks base method.
@@ -421,35 +692,126 @@ func (m *MockCommentRepository) EXPECT() *MockCommentRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CountReplies mocks base method.
+func (m *MockCommentRepository) CountReplies(arg0 []uint) (map[uint]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountReplies", arg0)
+	ret0, _ := ret[0].(map[uint]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountReplies indicates an expected call of CountReplies.
+func (mr *MockCommentRepositoryMockRecorder) CountReplies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReplies", reflect.TypeOf((*MockCommentRepository)(nil).CountReplies), arg0)
+}
+
 // Delete mocks base method.
-func (m *MockCommentRepository) Delete(arg0, arg1 uint) error {
+func (m *MockCommentRepository) Delete(arg0 uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret := m.ctrl.Call(m, "Delete", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockCommentRepositoryMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockCommentRepositoryMockRecorder) Delete(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCommentRepository)(nil).Delete), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCommentRepository)(nil).Delete), arg0)
 }
 
-// FindFromArticle mocks base method.
-func (m *MockCommentRepository) FindFromArticle(arg0 string) ([]domain.Comment, error) {
+// FindByAuthor mocks base method.
+func (m *MockCommentRepository) FindByAuthor(arg0 uint) ([]domain.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindFromArticle", arg0)
+	ret := m.ctrl.Call(m, "FindByAuthor", arg0)
 	ret0, _ := ret[0].([]domain.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// FindByAuthor indicates an expected call of FindByAuthor.
+func (mr *MockCommentRepositoryMockRecorder) FindByAuthor(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByAuthor", reflect.TypeOf((*MockCommentRepository)(nil).FindByAuthor), arg0)
+}
+
+// FindByID mocks base method.
+func (m *MockCommentRepository) FindByID(arg0 uint) (domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0)
+	ret0, _ := ret[0].(domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockCommentRepositoryMockRecorder) FindByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockCommentRepository)(nil).FindByID), arg0)
+}
+
+// FindFromArticle mocks base method.
+func (m *MockCommentRepository) FindFromArticle(arg0 ports.CommentSearchConditions) ([]domain.Comment, ports.Page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFromArticle", arg0)
+	ret0, _ := ret[0].([]domain.Comment)
+	ret1, _ := ret[1].(ports.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // FindFromArticle indicates an expected call of FindFromArticle.
 func (mr *MockCommentRepositoryMockRecorder) FindFromArticle(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFromArticle", reflect.TypeOf((*MockCommentRepository)(nil).FindFromArticle), arg0)
 }
 
+// FindReplies mocks base method.
+func (m *MockCommentRepository) FindReplies(arg0 []uint, arg1 int) ([]domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReplies", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReplies indicates an expected call of FindReplies.
+func (mr *MockCommentRepositoryMockRecorder) FindReplies(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReplies", reflect.TypeOf((*MockCommentRepository)(nil).FindReplies), arg0, arg1)
+}
+
+// FindRevisions mocks base method.
+func (m *MockCommentRepository) FindRevisions(arg0 uint) ([]domain.CommentRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRevisions", arg0)
+	ret0, _ := ret[0].([]domain.CommentRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRevisions indicates an expected call of FindRevisions.
+func (mr *MockCommentRepositoryMockRecorder) FindRevisions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRevisions", reflect.TypeOf((*MockCommentRepository)(nil).FindRevisions), arg0)
+}
+
+// LockByID mocks base method.
+func (m *MockCommentRepository) LockByID(arg0 uint) (domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockByID", arg0)
+	ret0, _ := ret[0].(domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockByID indicates an expected call of LockByID.
+func (mr *MockCommentRepositoryMockRecorder) LockByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockByID", reflect.TypeOf((*MockCommentRepository)(nil).LockByID), arg0)
+}
+
 // Save mocks base method.
 func (m *MockCommentRepository) Save(arg0 domain.Comment) (domain.Comment, error) {
 	m.ctrl.T.Helper()
@@ -465,6 +827,21 @@ func (mr *MockCommentRepositoryMockRecorder) Save(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockCommentRepository)(nil).Save), arg0)
 }
 
+// SaveRevision mocks base method.
+func (m *MockCommentRepository) SaveRevision(arg0 domain.CommentRevision) (domain.CommentRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRevision", arg0)
+	ret0, _ := ret[0].(domain.CommentRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveRevision indicates an expected call of SaveRevision.
+func (mr *MockCommentRepositoryMockRecorder) SaveRevision(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRevision", reflect.TypeOf((*MockCommentRepository)(nil).SaveRevision), arg0)
+}
+
 // WithTx mocks base method.
 func (m *MockCommentRepository) WithTx(arg0 *gorm.DB) ports.CommentRepository {
 	m.ctrl.T.Helper()
@@ -478,3 +855,750 @@ func (mr *MockCommentRepositoryMockRecorder) WithTx(arg0 interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockCommentRepository)(nil).WithTx), arg0)
 }
+
+// MockAuthorRepository is a mock of AuthorRepository interface.
+type MockAuthorRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorRepositoryMockRecorder
+}
+
+// MockAuthorRepositoryMockRecorder is the mock recorder for MockAuthorRepository.
+type MockAuthorRepositoryMockRecorder struct {
+	mock *MockAuthorRepository
+}
+
+// NewMockAuthorRepository creates a new mock instance.
+func NewMockAuthorRepository(ctrl *gomock.Controller) *MockAuthorRepository {
+	mock := &MockAuthorRepository{ctrl: ctrl}
+	mock.recorder = &MockAuthorRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorRepository) EXPECT() *MockAuthorRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindDriftedAuthorIDs mocks base method.
+func (m *MockAuthorRepository) FindDriftedAuthorIDs() ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDriftedAuthorIDs")
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDriftedAuthorIDs indicates an expected call of FindDriftedAuthorIDs.
+func (mr *MockAuthorRepositoryMockRecorder) FindDriftedAuthorIDs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDriftedAuthorIDs", reflect.TypeOf((*MockAuthorRepository)(nil).FindDriftedAuthorIDs))
+}
+
+// RepairAuthorInfo mocks base method.
+func (m *MockAuthorRepository) RepairAuthorInfo(arg0 []uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepairAuthorInfo", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepairAuthorInfo indicates an expected call of RepairAuthorInfo.
+func (mr *MockAuthorRepositoryMockRecorder) RepairAuthorInfo(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairAuthorInfo", reflect.TypeOf((*MockAuthorRepository)(nil).RepairAuthorInfo), arg0)
+}
+
+// UpdateAuthorInfo mocks base method.
+func (m *MockAuthorRepository) UpdateAuthorInfo(arg0 domain.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAuthorInfo", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAuthorInfo indicates an expected call of UpdateAuthorInfo.
+func (mr *MockAuthorRepositoryMockRecorder) UpdateAuthorInfo(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthorInfo", reflect.TypeOf((*MockAuthorRepository)(nil).UpdateAuthorInfo), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockAuthorRepository) WithTx(arg0 *gorm.DB) ports.AuthorRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(ports.AuthorRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockAuthorRepositoryMockRecorder) WithTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockAuthorRepository)(nil).WithTx), arg0)
+}
+
+// MockTokenRepository is a mock of TokenRepository interface.
+type MockTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenRepositoryMockRecorder
+}
+
+// MockTokenRepositoryMockRecorder is the mock recorder for MockTokenRepository.
+type MockTokenRepositoryMockRecorder struct {
+	mock *MockTokenRepository
+}
+
+// NewMockTokenRepository creates a new mock instance.
+func NewMockTokenRepository(ctrl *gomock.Controller) *MockTokenRepository {
+	mock := &MockTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenRepository) EXPECT() *MockTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateDeniedToken mocks base method.
+func (m *MockTokenRepository) CreateDeniedToken(arg0 string, arg1 time.Time) (domain.DeniedToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeniedToken", arg0, arg1)
+	ret0, _ := ret[0].(domain.DeniedToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDeniedToken indicates an expected call of CreateDeniedToken.
+func (mr *MockTokenRepositoryMockRecorder) CreateDeniedToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeniedToken", reflect.TypeOf((*MockTokenRepository)(nil).CreateDeniedToken), arg0, arg1)
+}
+
+// DeleteExpiredDeniedTokens mocks base method.
+func (m *MockTokenRepository) DeleteExpiredDeniedTokens(arg0 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredDeniedTokens", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredDeniedTokens indicates an expected call of DeleteExpiredDeniedTokens.
+func (mr *MockTokenRepositoryMockRecorder) DeleteExpiredDeniedTokens(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredDeniedTokens", reflect.TypeOf((*MockTokenRepository)(nil).DeleteExpiredDeniedTokens), arg0)
+}
+
+// DeletePersonalAccessToken mocks base method.
+func (m *MockTokenRepository) DeletePersonalAccessToken(arg0, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePersonalAccessToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePersonalAccessToken indicates an expected call of DeletePersonalAccessToken.
+func (mr *MockTokenRepositoryMockRecorder) DeletePersonalAccessToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePersonalAccessToken", reflect.TypeOf((*MockTokenRepository)(nil).DeletePersonalAccessToken), arg0, arg1)
+}
+
+// DeletePersonalAccessTokens mocks base method.
+func (m *MockTokenRepository) DeletePersonalAccessTokens(arg0 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePersonalAccessTokens", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePersonalAccessTokens indicates an expected call of DeletePersonalAccessTokens.
+func (mr *MockTokenRepositoryMockRecorder) DeletePersonalAccessTokens(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePersonalAccessTokens", reflect.TypeOf((*MockTokenRepository)(nil).DeletePersonalAccessTokens), arg0)
+}
+
+// ExistsDeniedToken mocks base method.
+func (m *MockTokenRepository) ExistsDeniedToken(arg0 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsDeniedToken", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsDeniedToken indicates an expected call of ExistsDeniedToken.
+func (mr *MockTokenRepositoryMockRecorder) ExistsDeniedToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsDeniedToken", reflect.TypeOf((*MockTokenRepository)(nil).ExistsDeniedToken), arg0)
+}
+
+// FindOneTimeToken mocks base method.
+func (m *MockTokenRepository) FindOneTimeToken(arg0 domain.TokenPurpose, arg1 string) (domain.OneTimeToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOneTimeToken", arg0, arg1)
+	ret0, _ := ret[0].(domain.OneTimeToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOneTimeToken indicates an expected call of FindOneTimeToken.
+func (mr *MockTokenRepositoryMockRecorder) FindOneTimeToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOneTimeToken", reflect.TypeOf((*MockTokenRepository)(nil).FindOneTimeToken), arg0, arg1)
+}
+
+// FindPersonalAccessToken mocks base method.
+func (m *MockTokenRepository) FindPersonalAccessToken(arg0 string) (domain.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPersonalAccessToken", arg0)
+	ret0, _ := ret[0].(domain.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPersonalAccessToken indicates an expected call of FindPersonalAccessToken.
+func (mr *MockTokenRepositoryMockRecorder) FindPersonalAccessToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPersonalAccessToken", reflect.TypeOf((*MockTokenRepository)(nil).FindPersonalAccessToken), arg0)
+}
+
+// FindPersonalAccessTokens mocks base method.
+func (m *MockTokenRepository) FindPersonalAccessTokens(arg0 uint) ([]domain.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPersonalAccessTokens", arg0)
+	ret0, _ := ret[0].([]domain.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPersonalAccessTokens indicates an expected call of FindPersonalAccessTokens.
+func (mr *MockTokenRepositoryMockRecorder) FindPersonalAccessTokens(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPersonalAccessTokens", reflect.TypeOf((*MockTokenRepository)(nil).FindPersonalAccessTokens), arg0)
+}
+
+// FindRefreshToken mocks base method.
+func (m *MockTokenRepository) FindRefreshToken(arg0 string) (domain.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRefreshToken", arg0)
+	ret0, _ := ret[0].(domain.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRefreshToken indicates an expected call of FindRefreshToken.
+func (mr *MockTokenRepositoryMockRecorder) FindRefreshToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRefreshToken", reflect.TypeOf((*MockTokenRepository)(nil).FindRefreshToken), arg0)
+}
+
+// RevokeRefreshToken mocks base method.
+func (m *MockTokenRepository) RevokeRefreshToken(arg0 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshToken", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshToken indicates an expected call of RevokeRefreshToken.
+func (mr *MockTokenRepositoryMockRecorder) RevokeRefreshToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshToken", reflect.TypeOf((*MockTokenRepository)(nil).RevokeRefreshToken), arg0)
+}
+
+// RevokeRefreshTokens mocks base method.
+func (m *MockTokenRepository) RevokeRefreshTokens(arg0 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshTokens", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshTokens indicates an expected call of RevokeRefreshTokens.
+func (mr *MockTokenRepositoryMockRecorder) RevokeRefreshTokens(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshTokens", reflect.TypeOf((*MockTokenRepository)(nil).RevokeRefreshTokens), arg0)
+}
+
+// SaveOneTimeToken mocks base method.
+func (m *MockTokenRepository) SaveOneTimeToken(arg0 domain.OneTimeToken) (domain.OneTimeToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOneTimeToken", arg0)
+	ret0, _ := ret[0].(domain.OneTimeToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveOneTimeToken indicates an expected call of SaveOneTimeToken.
+func (mr *MockTokenRepositoryMockRecorder) SaveOneTimeToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOneTimeToken", reflect.TypeOf((*MockTokenRepository)(nil).SaveOneTimeToken), arg0)
+}
+
+// SavePersonalAccessToken mocks base method.
+func (m *MockTokenRepository) SavePersonalAccessToken(arg0 domain.PersonalAccessToken) (domain.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePersonalAccessToken", arg0)
+	ret0, _ := ret[0].(domain.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SavePersonalAccessToken indicates an expected call of SavePersonalAccessToken.
+func (mr *MockTokenRepositoryMockRecorder) SavePersonalAccessToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePersonalAccessToken", reflect.TypeOf((*MockTokenRepository)(nil).SavePersonalAccessToken), arg0)
+}
+
+// SaveRefreshToken mocks base method.
+func (m *MockTokenRepository) SaveRefreshToken(arg0 domain.RefreshToken) (domain.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRefreshToken", arg0)
+	ret0, _ := ret[0].(domain.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveRefreshToken indicates an expected call of SaveRefreshToken.
+func (mr *MockTokenRepositoryMockRecorder) SaveRefreshToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRefreshToken", reflect.TypeOf((*MockTokenRepository)(nil).SaveRefreshToken), arg0)
+}
+
+// TouchPersonalAccessToken mocks base method.
+func (m *MockTokenRepository) TouchPersonalAccessToken(arg0 uint, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchPersonalAccessToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchPersonalAccessToken indicates an expected call of TouchPersonalAccessToken.
+func (mr *MockTokenRepositoryMockRecorder) TouchPersonalAccessToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchPersonalAccessToken", reflect.TypeOf((*MockTokenRepository)(nil).TouchPersonalAccessToken), arg0, arg1)
+}
+
+// UseOneTimeToken mocks base method.
+func (m *MockTokenRepository) UseOneTimeToken(arg0 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseOneTimeToken", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseOneTimeToken indicates an expected call of UseOneTimeToken.
+func (mr *MockTokenRepositoryMockRecorder) UseOneTimeToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseOneTimeToken", reflect.TypeOf((*MockTokenRepository)(nil).UseOneTimeToken), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockTokenRepository) WithTx(arg0 *gorm.DB) ports.TokenRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(ports.TokenRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockTokenRepositoryMockRecorder) WithTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockTokenRepository)(nil).WithTx), arg0)
+}
+
+// MockIdentityRepository is a mock of IdentityRepository interface.
+type MockIdentityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityRepositoryMockRecorder
+}
+
+// MockIdentityRepositoryMockRecorder is the mock recorder for MockIdentityRepository.
+type MockIdentityRepositoryMockRecorder struct {
+	mock *MockIdentityRepository
+}
+
+// NewMockIdentityRepository creates a new mock instance.
+func NewMockIdentityRepository(ctrl *gomock.Controller) *MockIdentityRepository {
+	mock := &MockIdentityRepository{ctrl: ctrl}
+	mock.recorder = &MockIdentityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityRepository) EXPECT() *MockIdentityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteIdentities mocks base method.
+func (m *MockIdentityRepository) DeleteIdentities(arg0 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdentities", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdentities indicates an expected call of DeleteIdentities.
+func (mr *MockIdentityRepositoryMockRecorder) DeleteIdentities(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdentities", reflect.TypeOf((*MockIdentityRepository)(nil).DeleteIdentities), arg0)
+}
+
+// FindIdentity mocks base method.
+func (m *MockIdentityRepository) FindIdentity(arg0, arg1 string) (domain.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIdentity", arg0, arg1)
+	ret0, _ := ret[0].(domain.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIdentity indicates an expected call of FindIdentity.
+func (mr *MockIdentityRepositoryMockRecorder) FindIdentity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdentity", reflect.TypeOf((*MockIdentityRepository)(nil).FindIdentity), arg0, arg1)
+}
+
+// FindLoginState mocks base method.
+func (m *MockIdentityRepository) FindLoginState(arg0 string) (domain.LoginState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLoginState", arg0)
+	ret0, _ := ret[0].(domain.LoginState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLoginState indicates an expected call of FindLoginState.
+func (mr *MockIdentityRepositoryMockRecorder) FindLoginState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLoginState", reflect.TypeOf((*MockIdentityRepository)(nil).FindLoginState), arg0)
+}
+
+// SaveIdentity mocks base method.
+func (m *MockIdentityRepository) SaveIdentity(arg0 domain.Identity) (domain.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIdentity", arg0)
+	ret0, _ := ret[0].(domain.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveIdentity indicates an expected call of SaveIdentity.
+func (mr *MockIdentityRepositoryMockRecorder) SaveIdentity(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdentity", reflect.TypeOf((*MockIdentityRepository)(nil).SaveIdentity), arg0)
+}
+
+// SaveLoginState mocks base method.
+func (m *MockIdentityRepository) SaveLoginState(arg0 domain.LoginState) (domain.LoginState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLoginState", arg0)
+	ret0, _ := ret[0].(domain.LoginState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveLoginState indicates an expected call of SaveLoginState.
+func (mr *MockIdentityRepositoryMockRecorder) SaveLoginState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLoginState", reflect.TypeOf((*MockIdentityRepository)(nil).SaveLoginState), arg0)
+}
+
+// UseLoginState mocks base method.
+func (m *MockIdentityRepository) UseLoginState(arg0 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseLoginState", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseLoginState indicates an expected call of UseLoginState.
+func (mr *MockIdentityRepositoryMockRecorder) UseLoginState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseLoginState", reflect.TypeOf((*MockIdentityRepository)(nil).UseLoginState), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockIdentityRepository) WithTx(arg0 *gorm.DB) ports.IdentityRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(ports.IdentityRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockIdentityRepositoryMockRecorder) WithTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockIdentityRepository)(nil).WithTx), arg0)
+}
+
+// MockMFARepository is a mock of MFARepository interface.
+type MockMFARepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMFARepositoryMockRecorder
+}
+
+// MockMFARepositoryMockRecorder is the mock recorder for MockMFARepository.
+type MockMFARepositoryMockRecorder struct {
+	mock *MockMFARepository
+}
+
+// NewMockMFARepository creates a new mock instance.
+func NewMockMFARepository(ctrl *gomock.Controller) *MockMFARepository {
+	mock := &MockMFARepository{ctrl: ctrl}
+	mock.recorder = &MockMFARepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMFARepository) EXPECT() *MockMFARepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteTwoFactor mocks base method.
+func (m *MockMFARepository) DeleteTwoFactor(arg0 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTwoFactor", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTwoFactor indicates an expected call of DeleteTwoFactor.
+func (mr *MockMFARepositoryMockRecorder) DeleteTwoFactor(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTwoFactor", reflect.TypeOf((*MockMFARepository)(nil).DeleteTwoFactor), arg0)
+}
+
+// FindTwoFactor mocks base method.
+func (m *MockMFARepository) FindTwoFactor(arg0 uint) (domain.TwoFactor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTwoFactor", arg0)
+	ret0, _ := ret[0].(domain.TwoFactor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTwoFactor indicates an expected call of FindTwoFactor.
+func (mr *MockMFARepositoryMockRecorder) FindTwoFactor(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTwoFactor", reflect.TypeOf((*MockMFARepository)(nil).FindTwoFactor), arg0)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockMFARepository) ReplaceRecoveryCodes(arg0 uint, arg1 []domain.RecoveryCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockMFARepositoryMockRecorder) ReplaceRecoveryCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockMFARepository)(nil).ReplaceRecoveryCodes), arg0, arg1)
+}
+
+// SaveTwoFactor mocks base method.
+func (m *MockMFARepository) SaveTwoFactor(arg0 domain.TwoFactor) (domain.TwoFactor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTwoFactor", arg0)
+	ret0, _ := ret[0].(domain.TwoFactor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveTwoFactor indicates an expected call of SaveTwoFactor.
+func (mr *MockMFARepositoryMockRecorder) SaveTwoFactor(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTwoFactor", reflect.TypeOf((*MockMFARepository)(nil).SaveTwoFactor), arg0)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockMFARepository) UseRecoveryCode(arg0 uint, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockMFARepositoryMockRecorder) UseRecoveryCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockMFARepository)(nil).UseRecoveryCode), arg0, arg1)
+}
+
+// UseTwoFactorStep mocks base method.
+func (m *MockMFARepository) UseTwoFactorStep(arg0 uint, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTwoFactorStep", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseTwoFactorStep indicates an expected call of UseTwoFactorStep.
+func (mr *MockMFARepositoryMockRecorder) UseTwoFactorStep(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTwoFactorStep", reflect.TypeOf((*MockMFARepository)(nil).UseTwoFactorStep), arg0, arg1)
+}
+
+// WithTx mocks base method.
+func (m *MockMFARepository) WithTx(arg0 *gorm.DB) ports.MFARepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(ports.MFARepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockMFARepositoryMockRecorder) WithTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockMFARepository)(nil).WithTx), arg0)
+}
+
+// MockLoginThrottleRepository is a mock of LoginThrottleRepository interface.
+type MockLoginThrottleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginThrottleRepositoryMockRecorder
+}
+
+// MockLoginThrottleRepositoryMockRecorder is the mock recorder for MockLoginThrottleRepository.
+type MockLoginThrottleRepositoryMockRecorder struct {
+	mock *MockLoginThrottleRepository
+}
+
+// NewMockLoginThrottleRepository creates a new mock instance.
+func NewMockLoginThrottleRepository(ctrl *gomock.Controller) *MockLoginThrottleRepository {
+	mock := &MockLoginThrottleRepository{ctrl: ctrl}
+	mock.recorder = &MockLoginThrottleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginThrottleRepository) EXPECT() *MockLoginThrottleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteLoginThrottle mocks base method.
+func (m *MockLoginThrottleRepository) DeleteLoginThrottle(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLoginThrottle", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLoginThrottle indicates an expected call of DeleteLoginThrottle.
+func (mr *MockLoginThrottleRepositoryMockRecorder) DeleteLoginThrottle(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoginThrottle", reflect.TypeOf((*MockLoginThrottleRepository)(nil).DeleteLoginThrottle), arg0)
+}
+
+// FindLoginThrottles mocks base method.
+func (m *MockLoginThrottleRepository) FindLoginThrottles(arg0 []string) ([]domain.LoginThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLoginThrottles", arg0)
+	ret0, _ := ret[0].([]domain.LoginThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLoginThrottles indicates an expected call of FindLoginThrottles.
+func (mr *MockLoginThrottleRepositoryMockRecorder) FindLoginThrottles(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLoginThrottles", reflect.TypeOf((*MockLoginThrottleRepository)(nil).FindLoginThrottles), arg0)
+}
+
+// LockLogin mocks base method.
+func (m *MockLoginThrottleRepository) LockLogin(arg0 string, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockLogin", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockLogin indicates an expected call of LockLogin.
+func (mr *MockLoginThrottleRepositoryMockRecorder) LockLogin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockLogin", reflect.TypeOf((*MockLoginThrottleRepository)(nil).LockLogin), arg0, arg1)
+}
+
+// RecordLoginFailure mocks base method.
+func (m *MockLoginThrottleRepository) RecordLoginFailure(arg0 string, arg1 time.Time) (domain.LoginThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLoginFailure", arg0, arg1)
+	ret0, _ := ret[0].(domain.LoginThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordLoginFailure indicates an expected call of RecordLoginFailure.
+func (mr *MockLoginThrottleRepositoryMockRecorder) RecordLoginFailure(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginFailure", reflect.TypeOf((*MockLoginThrottleRepository)(nil).RecordLoginFailure), arg0, arg1)
+}
+
+// WithTx mocks base method.
+func (m *MockLoginThrottleRepository) WithTx(arg0 *gorm.DB) ports.LoginThrottleRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(ports.LoginThrottleRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockLoginThrottleRepositoryMockRecorder) WithTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockLoginThrottleRepository)(nil).WithTx), arg0)
+}
+
+// MockLeaseRepository is a mock of LeaseRepository interface.
+type MockLeaseRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeaseRepositoryMockRecorder
+}
+
+// MockLeaseRepositoryMockRecorder is the mock recorder for MockLeaseRepository.
+type MockLeaseRepositoryMockRecorder struct {
+	mock *MockLeaseRepository
+}
+
+// NewMockLeaseRepository creates a new mock instance.
+func NewMockLeaseRepository(ctrl *gomock.Controller) *MockLeaseRepository {
+	mock := &MockLeaseRepository{ctrl: ctrl}
+	mock.recorder = &MockLeaseRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeaseRepository) EXPECT() *MockLeaseRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AcquireLease mocks base method.
+func (m *MockLeaseRepository) AcquireLease(arg0, arg1 string, arg2, arg3 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireLease", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireLease indicates an expected call of AcquireLease.
+func (mr *MockLeaseRepositoryMockRecorder) AcquireLease(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireLease", reflect.TypeOf((*MockLeaseRepository)(nil).AcquireLease), arg0, arg1, arg2, arg3)
+}
+
+// WithTx mocks base method.
+func (m *MockLeaseRepository) WithTx(arg0 *gorm.DB) ports.LeaseRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(ports.LeaseRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockLeaseRepositoryMockRecorder) WithTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockLeaseRepository)(nil).WithTx), arg0)
+}
@@ -1,11 +1,12 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/KumKeeHyun/gin-realworld/internal/core/ports (interfaces: AuthService,ProfileService,ArticleService,CommentService)
+// Source: github.com/KumKeeHyun/gin-realworld/internal/core/ports (interfaces: AuthService,ProfileService,ArticleService,CommentService,TokenService,AdminService,AccountService)
 
 // Package mock_ports is a generated GoMock package.
 package mock_ports
 
 import (
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/KumKeeHyun/gin-realworld/internal/core/domain"
 	ports "github.com/KumKeeHyun/gin-realworld/internal/core/ports"
@@ -36,19 +37,196 @@ func (m *MockAuthService) EXPECT() *MockAuthServiceMockRecorder {
 	return m.recorder
 }
 
+// BeginExternalLogin mocks base method.
+func (m *MockAuthService) BeginExternalLogin(arg0 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginExternalLogin", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginExternalLogin indicates an expected call of BeginExternalLogin.
+func (mr *MockAuthServiceMockRecorder) BeginExternalLogin(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginExternalLogin", reflect.TypeOf((*MockAuthService)(nil).BeginExternalLogin), arg0)
+}
+
+// CheckAuthors mocks base method.
+func (m *MockAuthService) CheckAuthors() ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckAuthors")
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckAuthors indicates an expected call of CheckAuthors.
+func (mr *MockAuthServiceMockRecorder) CheckAuthors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAuthors", reflect.TypeOf((*MockAuthService)(nil).CheckAuthors))
+}
+
+// CompleteExternalLogin mocks base method.
+func (m *MockAuthService) CompleteExternalLogin(arg0, arg1, arg2 string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteExternalLogin", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteExternalLogin indicates an expected call of CompleteExternalLogin.
+func (mr *MockAuthServiceMockRecorder) CompleteExternalLogin(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteExternalLogin", reflect.TypeOf((*MockAuthService)(nil).CompleteExternalLogin), arg0, arg1, arg2)
+}
+
+// ConfirmTOTP mocks base method.
+func (m *MockAuthService) ConfirmTOTP(arg0 uint, arg1 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTP", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTOTP indicates an expected call of ConfirmTOTP.
+func (mr *MockAuthServiceMockRecorder) ConfirmTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTP", reflect.TypeOf((*MockAuthService)(nil).ConfirmTOTP), arg0, arg1)
+}
+
+// DisableTOTP mocks base method.
+func (m *MockAuthService) DisableTOTP(arg0 uint, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTP", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockAuthServiceMockRecorder) DisableTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockAuthService)(nil).DisableTOTP), arg0, arg1)
+}
+
+// EnrollTOTP mocks base method.
+func (m *MockAuthService) EnrollTOTP(arg0 uint) (domain.TOTPEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTOTP", arg0)
+	ret0, _ := ret[0].(domain.TOTPEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTOTP indicates an expected call of EnrollTOTP.
+func (mr *MockAuthServiceMockRecorder) EnrollTOTP(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTOTP", reflect.TypeOf((*MockAuthService)(nil).EnrollTOTP), arg0)
+}
+
+// ExternalProviders mocks base method.
+func (m *MockAuthService) ExternalProviders() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExternalProviders")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// ExternalProviders indicates an expected call of ExternalProviders.
+func (mr *MockAuthServiceMockRecorder) ExternalProviders() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExternalProviders", reflect.TypeOf((*MockAuthService)(nil).ExternalProviders))
+}
+
 // Login mocks base method.
-func (m *MockAuthService) Login(arg0, arg1 string) (domain.User, error) {
+func (m *MockAuthService) Login(arg0, arg1, arg2 string) (domain.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", arg0, arg1)
+	ret := m.ctrl.Call(m, "Login", arg0, arg1, arg2)
 	ret0, _ := ret[0].(domain.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockAuthServiceMockRecorder) Login(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockAuthServiceMockRecorder) Login(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthService)(nil).Login), arg0, arg1, arg2)
+}
+
+// LoginMFA mocks base method.
+func (m *MockAuthService) LoginMFA(arg0, arg1 string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginMFA", arg0, arg1)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginMFA indicates an expected call of LoginMFA.
+func (mr *MockAuthServiceMockRecorder) LoginMFA(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginMFA", reflect.TypeOf((*MockAuthService)(nil).LoginMFA), arg0, arg1)
+}
+
+// Logout mocks base method.
+func (m *MockAuthService) Logout(arg0 domain.AccessClaim) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthServiceMockRecorder) Logout(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthService)(nil).Logout), arg0)
+}
+
+// PurgeDeniedTokens mocks base method.
+func (m *MockAuthService) PurgeDeniedTokens(arg0 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeniedTokens", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeniedTokens indicates an expected call of PurgeDeniedTokens.
+func (mr *MockAuthServiceMockRecorder) PurgeDeniedTokens(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeniedTokens", reflect.TypeOf((*MockAuthService)(nil).PurgeDeniedTokens), arg0)
+}
+
+// Refresh mocks base method.
+func (m *MockAuthService) Refresh(arg0 string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", arg0)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockAuthServiceMockRecorder) Refresh(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockAuthService)(nil).Refresh), arg0)
+}
+
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockAuthService) RegenerateRecoveryCodes(arg0 uint, arg1 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockAuthServiceMockRecorder) RegenerateRecoveryCodes(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthService)(nil).Login), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockAuthService)(nil).RegenerateRecoveryCodes), arg0, arg1)
 }
 
 // Register mocks base method.
@@ -66,6 +244,63 @@ func (mr *MockAuthServiceMockRecorder) Register(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthService)(nil).Register), arg0, arg1, arg2)
 }
 
+// RepairAuthors mocks base method.
+func (m *MockAuthService) RepairAuthors() ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepairAuthors")
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepairAuthors indicates an expected call of RepairAuthors.
+func (mr *MockAuthServiceMockRecorder) RepairAuthors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairAuthors", reflect.TypeOf((*MockAuthService)(nil).RepairAuthors))
+}
+
+// RequestPasswordReset mocks base method.
+func (m *MockAuthService) RequestPasswordReset(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestPasswordReset", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestPasswordReset indicates an expected call of RequestPasswordReset.
+func (mr *MockAuthServiceMockRecorder) RequestPasswordReset(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestPasswordReset", reflect.TypeOf((*MockAuthService)(nil).RequestPasswordReset), arg0)
+}
+
+// ResendVerification mocks base method.
+func (m *MockAuthService) ResendVerification(arg0 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerification", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendVerification indicates an expected call of ResendVerification.
+func (mr *MockAuthServiceMockRecorder) ResendVerification(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerification", reflect.TypeOf((*MockAuthService)(nil).ResendVerification), arg0)
+}
+
+// ResetPassword mocks base method.
+func (m *MockAuthService) ResetPassword(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAuthServiceMockRecorder) ResetPassword(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthService)(nil).ResetPassword), arg0, arg1)
+}
+
 // Update mocks base method.
 func (m *MockAuthService) Update(arg0 uint, arg1 ports.UserUpdateFields) (domain.User, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +316,20 @@ func (mr *MockAuthServiceMockRecorder) Update(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuthService)(nil).Update), arg0, arg1)
 }
 
+// Verify mocks base method.
+func (m *MockAuthService) Verify(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockAuthServiceMockRecorder) Verify(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockAuthService)(nil).Verify), arg0)
+}
+
 // WithTx mocks base method.
 func (m *MockAuthService) WithTx(arg0 *gorm.DB) ports.AuthService {
 	m.ctrl.T.Helper()
@@ -201,22 +450,22 @@ func (m *MockArticleService) EXPECT() *MockArticleServiceMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockArticleService) Create(arg0 uint, arg1, arg2, arg3 string, arg4 []string) (domain.ArticleView, error) {
+func (m *MockArticleService) Create(arg0 uint, arg1, arg2, arg3 string, arg4 []string, arg5 domain.ArticleStatus, arg6 *time.Time, arg7 *string) (domain.ArticleView, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 	ret0, _ := ret[0].(domain.ArticleView)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockArticleServiceMockRecorder) Create(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+func (mr *MockArticleServiceMockRecorder) Create(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockArticleService)(nil).Create), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockArticleService)(nil).Create), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 }
 
 // Delete mocks base method.
-func (m *MockArticleService) Delete(arg0 uint, arg1 string) error {
+func (m *MockArticleService) Delete(arg0 domain.Actor, arg1 string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -229,6 +478,21 @@ func (mr *MockArticleServiceMockRecorder) Delete(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockArticleService)(nil).Delete), arg0, arg1)
 }
 
+// DiffRevisions mocks base method.
+func (m *MockArticleService) DiffRevisions(arg0 uint, arg1 string, arg2, arg3 int) (domain.RevisionDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffRevisions", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(domain.RevisionDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffRevisions indicates an expected call of DiffRevisions.
+func (mr *MockArticleServiceMockRecorder) DiffRevisions(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffRevisions", reflect.TypeOf((*MockArticleService)(nil).DiffRevisions), arg0, arg1, arg2, arg3)
+}
+
 // Favorite mocks base method.
 func (m *MockArticleService) Favorite(arg0 uint, arg1 string) (domain.ArticleView, error) {
 	m.ctrl.T.Helper()
@@ -259,13 +523,29 @@ func (mr *MockArticleServiceMockRecorder) Find(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockArticleService)(nil).Find), arg0, arg1)
 }
 
+// FindRevision mocks base method.
+func (m *MockArticleService) FindRevision(arg0 uint, arg1 string, arg2 int) (domain.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRevision", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRevision indicates an expected call of FindRevision.
+func (mr *MockArticleServiceMockRecorder) FindRevision(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRevision", reflect.TypeOf((*MockArticleService)(nil).FindRevision), arg0, arg1, arg2)
+}
+
 // ListByConditions mocks base method.
-func (m *MockArticleService) ListByConditions(arg0 uint, arg1 ports.ArticleSearchConditions) ([]domain.ArticleView, error) {
+func (m *MockArticleService) ListByConditions(arg0 uint, arg1 ports.ArticleSearchConditions) ([]domain.ArticleView, ports.Page, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListByConditions", arg0, arg1)
 	ret0, _ := ret[0].([]domain.ArticleView)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(ports.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // ListByConditions indicates an expected call of ListByConditions.
@@ -274,13 +554,30 @@ func (mr *MockArticleServiceMockRecorder) ListByConditions(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByConditions", reflect.TypeOf((*MockArticleService)(nil).ListByConditions), arg0, arg1)
 }
 
+// ListDrafts mocks base method.
+func (m *MockArticleService) ListDrafts(arg0 uint, arg1 ports.Pageable) ([]domain.ArticleView, ports.Page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDrafts", arg0, arg1)
+	ret0, _ := ret[0].([]domain.ArticleView)
+	ret1, _ := ret[1].(ports.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDrafts indicates an expected call of ListDrafts.
+func (mr *MockArticleServiceMockRecorder) ListDrafts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDrafts", reflect.TypeOf((*MockArticleService)(nil).ListDrafts), arg0, arg1)
+}
+
 // ListFeed mocks base method.
-func (m *MockArticleService) ListFeed(arg0 uint, arg1 ports.Pageable) ([]domain.ArticleView, error) {
+func (m *MockArticleService) ListFeed(arg0 uint, arg1 ports.Pageable) ([]domain.ArticleView, ports.Page, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListFeed", arg0, arg1)
 	ret0, _ := ret[0].([]domain.ArticleView)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(ports.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // ListFeed indicates an expected call of ListFeed.
@@ -289,19 +586,94 @@ func (mr *MockArticleServiceMockRecorder) ListFeed(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeed", reflect.TypeOf((*MockArticleService)(nil).ListFeed), arg0, arg1)
 }
 
+// ListRevisions mocks base method.
+func (m *MockArticleService) ListRevisions(arg0 uint, arg1 string) ([]domain.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", arg0, arg1)
+	ret0, _ := ret[0].([]domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockArticleServiceMockRecorder) ListRevisions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockArticleService)(nil).ListRevisions), arg0, arg1)
+}
+
 // ListTags mocks base method.
-func (m *MockArticleService) ListTags() ([]string, error) {
+func (m *MockArticleService) ListTags(arg0 uint) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTags")
+	ret := m.ctrl.Call(m, "ListTags", arg0)
 	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListTags indicates an expected call of ListTags.
-func (mr *MockArticleServiceMockRecorder) ListTags() *gomock.Call {
+func (mr *MockArticleServiceMockRecorder) ListTags(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockArticleService)(nil).ListTags))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockArticleService)(nil).ListTags), arg0)
+}
+
+// Publish mocks base method.
+func (m *MockArticleService) Publish(arg0 domain.Actor, arg1 string) (domain.ArticleView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", arg0, arg1)
+	ret0, _ := ret[0].(domain.ArticleView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockArticleServiceMockRecorder) Publish(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockArticleService)(nil).Publish), arg0, arg1)
+}
+
+// PublishScheduled mocks base method.
+func (m *MockArticleService) PublishScheduled(arg0 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishScheduled", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishScheduled indicates an expected call of PublishScheduled.
+func (mr *MockArticleServiceMockRecorder) PublishScheduled(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishScheduled", reflect.TypeOf((*MockArticleService)(nil).PublishScheduled), arg0)
+}
+
+// ReconcileFavoritesCounts mocks base method.
+func (m *MockArticleService) ReconcileFavoritesCounts() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileFavoritesCounts")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileFavoritesCounts indicates an expected call of ReconcileFavoritesCounts.
+func (mr *MockArticleServiceMockRecorder) ReconcileFavoritesCounts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileFavoritesCounts", reflect.TypeOf((*MockArticleService)(nil).ReconcileFavoritesCounts))
+}
+
+// RestoreRevision mocks base method.
+func (m *MockArticleService) RestoreRevision(arg0 domain.Actor, arg1 string, arg2 int) (domain.ArticleView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRevision", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.ArticleView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreRevision indicates an expected call of RestoreRevision.
+func (mr *MockArticleServiceMockRecorder) RestoreRevision(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRevision", reflect.TypeOf((*MockArticleService)(nil).RestoreRevision), arg0, arg1, arg2)
 }
 
 // Unfavorite mocks base method.
@@ -320,7 +692,7 @@ func (mr *MockArticleServiceMockRecorder) Unfavorite(arg0, arg1 interface{}) *go
 }
 
 // Update mocks base method.
-func (m *MockArticleService) Update(arg0 uint, arg1 string, arg2 ports.ArticleUpdateFields) (domain.ArticleView, error) {
+func (m *MockArticleService) Update(arg0 domain.Actor, arg1 string, arg2 ports.ArticleUpdateFields) (domain.ArticleView, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2)
 	ret0, _ := ret[0].(domain.ArticleView)
@@ -372,22 +744,22 @@ func (m *MockCommentService) EXPECT() *MockCommentServiceMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockCommentService) Create(arg0 uint, arg1, arg2 string) (domain.CommentView, error) {
+func (m *MockCommentService) Create(arg0 uint, arg1, arg2 string, arg3 *uint) (domain.CommentView, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(domain.CommentView)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockCommentServiceMockRecorder) Create(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockCommentServiceMockRecorder) Create(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCommentService)(nil).Create), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCommentService)(nil).Create), arg0, arg1, arg2, arg3)
 }
 
 // Delete mocks base method.
-func (m *MockCommentService) Delete(arg0, arg1 uint) error {
+func (m *MockCommentService) Delete(arg0 domain.Actor, arg1 uint) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -401,18 +773,49 @@ func (mr *MockCommentServiceMockRecorder) Delete(arg0, arg1 interface{}) *gomock
 }
 
 // GetFromArticle mocks base method.
-func (m *MockCommentService) GetFromArticle(arg0 uint, arg1 string) ([]domain.CommentView, error) {
+func (m *MockCommentService) GetFromArticle(arg0 uint, arg1 string, arg2 ports.CommentListOptions, arg3 ports.Pageable) ([]domain.CommentView, ports.Page, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFromArticle", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetFromArticle", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].([]domain.CommentView)
+	ret1, _ := ret[1].(ports.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFromArticle indicates an expected call of GetFromArticle.
+func (mr *MockCommentServiceMockRecorder) GetFromArticle(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFromArticle", reflect.TypeOf((*MockCommentService)(nil).GetFromArticle), arg0, arg1, arg2, arg3)
+}
+
+// ListRevisions mocks base method.
+func (m *MockCommentService) ListRevisions(arg0 domain.Actor, arg1 string, arg2 uint) ([]domain.CommentRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]domain.CommentRevision)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetFromArticle indicates an expected call of GetFromArticle.
-func (mr *MockCommentServiceMockRecorder) GetFromArticle(arg0, arg1 interface{}) *gomock.Call {
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockCommentServiceMockRecorder) ListRevisions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockCommentService)(nil).ListRevisions), arg0, arg1, arg2)
+}
+
+// Update mocks base method.
+func (m *MockCommentService) Update(arg0 domain.Actor, arg1 string, arg2 uint, arg3 string) (domain.CommentView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(domain.CommentView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCommentServiceMockRecorder) Update(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFromArticle", reflect.TypeOf((*MockCommentService)(nil).GetFromArticle), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCommentService)(nil).Update), arg0, arg1, arg2, arg3)
 }
 
 // WithTx mocks base method.
@@ -428,3 +831,217 @@ func (mr *MockCommentServiceMockRecorder) WithTx(arg0 interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockCommentService)(nil).WithTx), arg0)
 }
+
+// MockTokenService is a mock of TokenService interface.
+type MockTokenService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenServiceMockRecorder
+}
+
+// MockTokenServiceMockRecorder is the mock recorder for MockTokenService.
+type MockTokenServiceMockRecorder struct {
+	mock *MockTokenService
+}
+
+// NewMockTokenService creates a new mock instance.
+func NewMockTokenService(ctrl *gomock.Controller) *MockTokenService {
+	mock := &MockTokenService{ctrl: ctrl}
+	mock.recorder = &MockTokenServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenService) EXPECT() *MockTokenServiceMockRecorder {
+	return m.recorder
+}
+
+// CreatePersonalAccessToken mocks base method.
+func (m *MockTokenService) CreatePersonalAccessToken(arg0 uint, arg1 string, arg2 []string, arg3 *time.Time) (domain.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePersonalAccessToken", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(domain.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePersonalAccessToken indicates an expected call of CreatePersonalAccessToken.
+func (mr *MockTokenServiceMockRecorder) CreatePersonalAccessToken(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePersonalAccessToken", reflect.TypeOf((*MockTokenService)(nil).CreatePersonalAccessToken), arg0, arg1, arg2, arg3)
+}
+
+// ListPersonalAccessTokens mocks base method.
+func (m *MockTokenService) ListPersonalAccessTokens(arg0 uint) ([]domain.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPersonalAccessTokens", arg0)
+	ret0, _ := ret[0].([]domain.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPersonalAccessTokens indicates an expected call of ListPersonalAccessTokens.
+func (mr *MockTokenServiceMockRecorder) ListPersonalAccessTokens(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPersonalAccessTokens", reflect.TypeOf((*MockTokenService)(nil).ListPersonalAccessTokens), arg0)
+}
+
+// RevokePersonalAccessToken mocks base method.
+func (m *MockTokenService) RevokePersonalAccessToken(arg0, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokePersonalAccessToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokePersonalAccessToken indicates an expected call of RevokePersonalAccessToken.
+func (mr *MockTokenServiceMockRecorder) RevokePersonalAccessToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokePersonalAccessToken", reflect.TypeOf((*MockTokenService)(nil).RevokePersonalAccessToken), arg0, arg1)
+}
+
+// WithTx mocks base method.
+func (m *MockTokenService) WithTx(arg0 *gorm.DB) ports.TokenService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(ports.TokenService)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockTokenServiceMockRecorder) WithTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockTokenService)(nil).WithTx), arg0)
+}
+
+// MockAdminService is a mock of AdminService interface.
+type MockAdminService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminServiceMockRecorder
+}
+
+// MockAdminServiceMockRecorder is the mock recorder for MockAdminService.
+type MockAdminServiceMockRecorder struct {
+	mock *MockAdminService
+}
+
+// NewMockAdminService creates a new mock instance.
+func NewMockAdminService(ctrl *gomock.Controller) *MockAdminService {
+	mock := &MockAdminService{ctrl: ctrl}
+	mock.recorder = &MockAdminServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminService) EXPECT() *MockAdminServiceMockRecorder {
+	return m.recorder
+}
+
+// ListUsers mocks base method.
+func (m *MockAdminService) ListUsers(arg0 domain.Actor, arg1 ports.Pageable) ([]domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", arg0, arg1)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockAdminServiceMockRecorder) ListUsers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockAdminService)(nil).ListUsers), arg0, arg1)
+}
+
+// UpdateRole mocks base method.
+func (m *MockAdminService) UpdateRole(arg0 domain.Actor, arg1 string, arg2 domain.Role) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockAdminServiceMockRecorder) UpdateRole(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockAdminService)(nil).UpdateRole), arg0, arg1, arg2)
+}
+
+// WithTx mocks base method.
+func (m *MockAdminService) WithTx(arg0 *gorm.DB) ports.AdminService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(ports.AdminService)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockAdminServiceMockRecorder) WithTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockAdminService)(nil).WithTx), arg0)
+}
+
+// MockAccountService is a mock of AccountService interface.
+type MockAccountService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountServiceMockRecorder
+}
+
+// MockAccountServiceMockRecorder is the mock recorder for MockAccountService.
+type MockAccountServiceMockRecorder struct {
+	mock *MockAccountService
+}
+
+// NewMockAccountService creates a new mock instance.
+func NewMockAccountService(ctrl *gomock.Controller) *MockAccountService {
+	mock := &MockAccountService{ctrl: ctrl}
+	mock.recorder = &MockAccountServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountService) EXPECT() *MockAccountServiceMockRecorder {
+	return m.recorder
+}
+
+// DeleteAccount mocks base method.
+func (m *MockAccountService) DeleteAccount(arg0 domain.AccessClaim) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockAccountServiceMockRecorder) DeleteAccount(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockAccountService)(nil).DeleteAccount), arg0)
+}
+
+// ExportAccount mocks base method.
+func (m *MockAccountService) ExportAccount(arg0 uint) (domain.AccountExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportAccount", arg0)
+	ret0, _ := ret[0].(domain.AccountExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportAccount indicates an expected call of ExportAccount.
+func (mr *MockAccountServiceMockRecorder) ExportAccount(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAccount", reflect.TypeOf((*MockAccountService)(nil).ExportAccount), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockAccountService) WithTx(arg0 *gorm.DB) ports.AccountService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(ports.AccountService)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockAccountServiceMockRecorder) WithTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockAccountService)(nil).WithTx), arg0)
+}
@@ -1,9 +1,10 @@
 package ports
 
-//go:generate mockgen -destination=./mock_ports/mock_repositories.go -package=mock_ports github.com/KumKeeHyun/gin-realworld/internal/core/ports UserRepository,ArticleRepository,CommentRepository
+//go:generate mockgen -destination=./mock_ports/mock_repositories.go -package=mock_ports github.com/KumKeeHyun/gin-realworld/internal/core/ports UserRepository,ArticleRepository,CommentRepository,AuthorRepository,TokenRepository,IdentityRepository,MFARepository,LoginThrottleRepository,LeaseRepository
 
 import (
 	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"time"
 )
 
 type UserRepository interface {
@@ -13,43 +14,226 @@ type UserRepository interface {
 	FindByEmail(email string) (domain.User, error)
 	FindByUsername(username string) (domain.User, error)
 	FindByEmailOrUsername(email, username string) (domain.User, error)
+	FindAll(pageable Pageable) ([]domain.User, error)
+	UpdateRole(id uint, role domain.Role) error
 	FindProfile(curUserID, profileUserID uint) (domain.Profile, error)
 	CreateFollow(followerID, followingID uint) (domain.Follow, error)
 	FindFollow(followerID, followingID uint) (domain.Follow, error)
 	FindFollows(followerID uint, followingIDs []uint) ([]domain.Follow, error)
 	DeleteFollow(followerID, followingID uint) error
+	FindFollowings(followerID uint) ([]domain.User, error)
+	DeleteFollows(userID uint) error
+	Delete(id uint) error
 }
 
 type Pageable struct {
 	Limit  int
 	Offset int
+	// Cursor switches to keyset pagination in order of creation, Offset is ignored
+	Cursor *Cursor
 }
 
+// Cursor points to the row which the page starts after, or ends before if Before is set.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uint
+	Before    bool
+}
+
+// Page describes where found rows are placed in all matched rows.
+type Page struct {
+	Total   int64
+	HasPrev bool
+	HasNext bool
+}
+
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "any"
+	TagMatchAll TagMatch = "all"
+)
+
+type ArticleSort string
+
+const (
+	SortRecent        ArticleSort = "recent"
+	SortOldest        ArticleSort = "oldest"
+	SortMostFavorited ArticleSort = "mostFavorited"
+	// SortTrending orders articles by favorites in TrendingWindow
+	SortTrending ArticleSort = "trending"
+)
+
+const TrendingWindow = 7 * 24 * time.Hour
+
+// ArticleSearchConditions lists published articles, and also non-published articles of reader.
+// Articles are ordered by Sort, or by rank of full-text search when Sort is not set and Query is set,
+// otherwise by creation from the newest. Ties are broken by creation from the newest.
 type ArticleSearchConditions struct {
-	Tag       *string
-	Author    *string
-	Favorited *string
+	Query *string
+	// Tags are matched by TagMatch, any of them by default
+	Tags        []string
+	TagMatch    TagMatch
+	ExcludeTags []string
+	Authors     []string
+	Favorited   *string
+	// CreatedAfter is inclusive and CreatedBefore is exclusive
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// FollowedOnly finds only articles of authors followed by reader
+	FollowedOnly bool
+	Sort         ArticleSort
+	// TrendingSince is start of TrendingWindow, only used for SortTrending
+	TrendingSince time.Time
+	ReaderID      uint
 	Pageable
 }
 
+// OrderedByCreation reports whether articles are ordered by (created_at, id), which cursor can point to.
+func (c ArticleSearchConditions) OrderedByCreation() bool {
+	switch c.Sort {
+	case SortRecent, SortOldest:
+		return true
+	case "":
+		return c.Query == nil
+	}
+	return false
+}
+
 type ArticleRepository interface {
 	Transactional[ArticleRepository]
 	Save(article domain.Article) (domain.Article, error)
 	FindBySlug(slug string) (domain.Article, error)
-	FindBySearchConditions(cond ArticleSearchConditions) ([]domain.Article, error)
-	FindFeed(userID uint, pageable Pageable) ([]domain.Article, error)
+	FindByOldSlug(slug string) (domain.Article, error)
+	SaveSlugHistory(history domain.SlugHistory) (domain.SlugHistory, error)
+	DeleteSlugHistory(slug string) error
+	FindBySearchConditions(cond ArticleSearchConditions) ([]domain.Article, Page, error)
+	// FindSnippets returns escaped html of matched parts, see domain.HighlightSnippet.
+	FindSnippets(query string, articleIDs []uint) (map[uint]string, error)
+	FindFeed(userID uint, pageable Pageable) ([]domain.Article, Page, error)
+	FindDrafts(authorID uint, pageable Pageable) ([]domain.Article, Page, error)
+	PublishDueArticles(now time.Time) (int64, error)
 	DeleteBySlug(slug string) error
-	UpdateAuthorInfo(user domain.User) error
-	CreateFavorite(userID, articleID uint) (domain.Favorite, error)
+	// CreateFavorite and DeleteFavorite update favorites count of article with favorite, and return the count.
+	// They are idempotent, and count is changed only when favorite is actually created or deleted.
+	CreateFavorite(userID, articleID uint) (int, error)
 	FindFavorite(userID uint, articleID uint) (domain.Favorite, error)
 	FindFavorites(userID uint, articleIDs []uint) ([]domain.Favorite, error)
-	DeleteFavorite(userID, articleID uint) error
-	FindTags() ([]string, error)
+	DeleteFavorite(userID, articleID uint) (int, error)
+	FindByAuthor(authorID uint) ([]domain.Article, error)
+	FindFavoritedArticles(userID uint) ([]domain.Article, error)
+	DeleteFavorites(userID uint) error
+	ReconcileFavoritesCounts() (int64, error)
+	FindTags(readerID uint) ([]string, error)
+	SaveRevision(revision domain.ArticleRevision) (domain.ArticleRevision, error)
+	FindRevisions(articleID uint) ([]domain.ArticleRevision, error)
+	FindRevision(articleID uint, number int) (domain.ArticleRevision, error)
+	FindLatestRevision(articleID uint) (domain.ArticleRevision, error)
+}
+
+type CommentSort string
+
+const (
+	CommentSortOldest CommentSort = "oldest"
+	CommentSortNewest CommentSort = "newest"
+	// CommentSortTop orders comments by the number of direct replies
+	CommentSortTop CommentSort = "top"
+)
+
+// OrderedByCreation reports whether comments are ordered by (created_at, id), which cursor can point to.
+func (s CommentSort) OrderedByCreation() bool {
+	return s != CommentSortTop
+}
+
+type CommentSearchConditions struct {
+	Slug string
+	// TopLevel finds only comments which are not replies
+	TopLevel bool
+	// ParentID finds only direct replies of the comment
+	ParentID *uint
+	// Sort is the oldest first by default
+	Sort CommentSort
+	Pageable
 }
 
 type CommentRepository interface {
 	Transactional[CommentRepository]
 	Save(comment domain.Comment) (domain.Comment, error)
-	FindFromArticle(slug string) ([]domain.Comment, error)
-	Delete(id, authorID uint) error
+	FindByID(id uint) (domain.Comment, error)
+	// LockByID finds comment and locks it until the transaction ends, so that it is not deleted meanwhile.
+	LockByID(id uint) (domain.Comment, error)
+	FindFromArticle(cond CommentSearchConditions) ([]domain.Comment, Page, error)
+	// FindReplies finds at most limit direct replies of parents from the oldest.
+	FindReplies(parentIDs []uint, limit int) ([]domain.Comment, error)
+	// CountReplies returns the number of direct replies by id of parents, parents without replies are omitted.
+	CountReplies(parentIDs []uint) (map[uint]int, error)
+	FindByAuthor(authorID uint) ([]domain.Comment, error)
+	// Delete leaves comment as tombstone if it has replies, and deletes tombstoned ancestors left without replies.
+	Delete(id uint) error
+	SaveRevision(revision domain.CommentRevision) (domain.CommentRevision, error)
+	FindRevisions(commentID uint) ([]domain.CommentRevision, error)
+}
+
+// AuthorRepository keeps domain.Author copied into articles, comments and other projections in sync with users.
+type AuthorRepository interface {
+	Transactional[AuthorRepository]
+	// UpdateAuthorInfo copies user to every author projection of user.
+	UpdateAuthorInfo(user domain.User) error
+	// FindDriftedAuthorIDs returns ids of users whose author projections differ from them.
+	FindDriftedAuthorIDs() ([]uint, error)
+	// RepairAuthorInfo copies users of ids to their author projections, and returns the number of repaired rows.
+	RepairAuthorInfo(ids []uint) (int64, error)
+}
+
+type TokenRepository interface {
+	Transactional[TokenRepository]
+	SaveRefreshToken(token domain.RefreshToken) (domain.RefreshToken, error)
+	FindRefreshToken(tokenHash string) (domain.RefreshToken, error)
+	RevokeRefreshToken(id uint) error
+	RevokeRefreshTokens(userID uint) error
+	CreateDeniedToken(jti string, expiresAt time.Time) (domain.DeniedToken, error)
+	ExistsDeniedToken(jti string) (bool, error)
+	DeleteExpiredDeniedTokens(now time.Time) (int64, error)
+	SaveOneTimeToken(token domain.OneTimeToken) (domain.OneTimeToken, error)
+	FindOneTimeToken(purpose domain.TokenPurpose, tokenHash string) (domain.OneTimeToken, error)
+	UseOneTimeToken(id uint) error
+	SavePersonalAccessToken(token domain.PersonalAccessToken) (domain.PersonalAccessToken, error)
+	FindPersonalAccessToken(tokenHash string) (domain.PersonalAccessToken, error)
+	FindPersonalAccessTokens(userID uint) ([]domain.PersonalAccessToken, error)
+	TouchPersonalAccessToken(id uint, usedAt time.Time) error
+	DeletePersonalAccessToken(userID, id uint) error
+	DeletePersonalAccessTokens(userID uint) error
+}
+
+type IdentityRepository interface {
+	Transactional[IdentityRepository]
+	SaveIdentity(identity domain.Identity) (domain.Identity, error)
+	FindIdentity(provider, subject string) (domain.Identity, error)
+	SaveLoginState(state domain.LoginState) (domain.LoginState, error)
+	FindLoginState(stateHash string) (domain.LoginState, error)
+	UseLoginState(id uint) error
+	DeleteIdentities(userID uint) error
+}
+
+type MFARepository interface {
+	Transactional[MFARepository]
+	SaveTwoFactor(twoFactor domain.TwoFactor) (domain.TwoFactor, error)
+	FindTwoFactor(userID uint) (domain.TwoFactor, error)
+	UseTwoFactorStep(id uint, step int64) error
+	DeleteTwoFactor(userID uint) error
+	ReplaceRecoveryCodes(userID uint, codes []domain.RecoveryCode) error
+	UseRecoveryCode(userID uint, codeHash string) error
+}
+
+type LoginThrottleRepository interface {
+	Transactional[LoginThrottleRepository]
+	FindLoginThrottles(keys []string) ([]domain.LoginThrottle, error)
+	RecordLoginFailure(key string, at time.Time) (domain.LoginThrottle, error)
+	LockLogin(key string, until time.Time) error
+	DeleteLoginThrottle(key string) error
+}
+
+type LeaseRepository interface {
+	Transactional[LeaseRepository]
+	AcquireLease(name, holder string, now, until time.Time) (bool, error)
 }
@@ -1,19 +1,44 @@
 package ports
 
-//go:generate mockgen -destination=./mock_ports/mock_services.go -package=mock_ports github.com/KumKeeHyun/gin-realworld/internal/core/ports AuthService,ProfileService,ArticleService,CommentService
+//go:generate mockgen -destination=./mock_ports/mock_services.go -package=mock_ports github.com/KumKeeHyun/gin-realworld/internal/core/ports AuthService,ProfileService,ArticleService,CommentService,TokenService,AdminService,AccountService
 
 import (
 	"errors"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"time"
 )
 
 var (
 	ErrInternal                  = errors.New("internal error")
 	ErrResourceNotFound          = errors.New("resource not found")
-	ErrInvalidPassword           = errors.New("invalid password")
+	ErrInvalidCredentials        = errors.New("invalid email or password")
+	ErrTooManyLoginAttempts      = errors.New("too many failed login attempts, try again later")
 	ErrSelfFollowing             = errors.New("can not follow oneself")
 	ErrDuplicatedEmailOrUsername = errors.New("duplicated email or username")
+	ErrReservedEmailOrUsername   = errors.New("email or username is reserved")
 	ErrNonOwnedContent           = errors.New("user is not author of article")
+	ErrPermissionDenied          = errors.New("permission denied")
+	ErrInvalidRole               = errors.New("invalid role")
+	ErrSelfRoleChange            = errors.New("can not change own role")
+	ErrInvalidArticleStatus      = errors.New("invalid article status")
+	ErrInvalidPublishAt          = errors.New("publishAt can only be set to non-published article")
+	ErrInvalidSlug               = errors.New("slug must be lowercase letters, numbers and hyphens")
+	ErrDuplicatedSlug            = errors.New("duplicated slug")
+	ErrInvalidCursor             = errors.New("invalid cursor")
+	ErrUnsupportedCursor         = errors.New("cursor can only be used in order of creation")
+	ErrInvalidParentComment      = errors.New("parent comment not found in article")
+	ErrCommentTooDeep            = errors.New("comment is nested too deeply")
+	ErrInvalidRefreshToken       = errors.New("invalid refresh token")
+	ErrInvalidResetToken         = errors.New("invalid or expired reset token")
+	ErrInvalidVerificationToken  = errors.New("invalid or expired verification token")
+	ErrUnknownProvider           = errors.New("unknown identity provider")
+	ErrInvalidLoginState         = errors.New("invalid or expired login state")
+	ErrExternalLogin             = errors.New("failed to login with identity provider")
+	ErrInvalidScope              = errors.New("invalid token scope")
+	ErrInvalidMFAChallenge       = errors.New("invalid or expired mfa challenge")
+	ErrInvalidMFACode            = errors.New("invalid mfa code")
+	ErrMFAAlreadyEnabled         = errors.New("two factor authentication is already enabled")
+	ErrMFANotEnabled             = errors.New("two factor authentication is not enabled")
 )
 
 type UserUpdateFields struct {
@@ -27,8 +52,27 @@ type UserUpdateFields struct {
 type AuthService interface {
 	Transactional[AuthService]
 	Register(email, username, password string) (domain.User, error)
-	Login(email, password string) (domain.User, error)
+	Login(email, password, clientIP string) (domain.User, error)
+	Refresh(refreshToken string) (domain.User, error)
+	Logout(claim domain.AccessClaim) error
+	// PurgeDeniedTokens deletes denied access tokens which expired by now, it is run by scheduler.
+	PurgeDeniedTokens(now time.Time) (int64, error)
+	RequestPasswordReset(email string) error
+	ResetPassword(token, password string) error
+	Verify(token string) error
+	ResendVerification(userID uint) error
+	ExternalProviders() []string
+	BeginExternalLogin(provider string) (string, error)
+	CompleteExternalLogin(provider, state, code string) (domain.User, error)
+	LoginMFA(challenge, code string) (domain.User, error)
+	EnrollTOTP(userID uint) (domain.TOTPEnrollment, error)
+	ConfirmTOTP(userID uint, code string) ([]string, error)
+	DisableTOTP(userID uint, code string) error
+	RegenerateRecoveryCodes(userID uint, code string) ([]string, error)
 	Update(userID uint, fields UserUpdateFields) (domain.User, error)
+	// CheckAuthors and RepairAuthors find and repair author copies drifted from users.
+	CheckAuthors() ([]uint, error)
+	RepairAuthors() ([]uint, error)
 }
 
 type ProfileService interface {
@@ -42,24 +86,80 @@ type ArticleUpdateFields struct {
 	Title       *string
 	Description *string
 	Body        *string
+	// Slug is generated from Title if it is not set
+	Slug      *string
+	PublishAt *time.Time
 }
 
 type ArticleService interface {
 	Transactional[ArticleService]
-	Create(authorID uint, title, description, body string, tags []string) (domain.ArticleView, error)
+	Create(authorID uint, title, description, body string, tags []string, status domain.ArticleStatus, publishAt *time.Time, slug *string) (domain.ArticleView, error)
 	Find(readerID uint, slug string) (domain.ArticleView, error)
-	ListByConditions(readerID uint, conditions ArticleSearchConditions) ([]domain.ArticleView, error)
-	ListFeed(readerID uint, pageable Pageable) ([]domain.ArticleView, error)
-	Update(authorID uint, slug string, fields ArticleUpdateFields) (domain.ArticleView, error)
-	Delete(authorID uint, slug string) error
+	ListByConditions(readerID uint, conditions ArticleSearchConditions) ([]domain.ArticleView, Page, error)
+	ListFeed(readerID uint, pageable Pageable) ([]domain.ArticleView, Page, error)
+	ListDrafts(authorID uint, pageable Pageable) ([]domain.ArticleView, Page, error)
+	Update(actor domain.Actor, slug string, fields ArticleUpdateFields) (domain.ArticleView, error)
+	Delete(actor domain.Actor, slug string) error
+	Publish(actor domain.Actor, slug string) (domain.ArticleView, error)
+	PublishScheduled(now time.Time) (int64, error)
 	Favorite(userID uint, slug string) (domain.ArticleView, error)
 	Unfavorite(userID uint, slug string) (domain.ArticleView, error)
-	ListTags() ([]string, error)
+	// ReconcileFavoritesCounts fixes favorites count of articles drifted from favorites, and returns the number of them.
+	ReconcileFavoritesCounts() (int64, error)
+	ListTags(readerID uint) ([]string, error)
+	ListRevisions(readerID uint, slug string) ([]domain.ArticleRevision, error)
+	FindRevision(readerID uint, slug string, number int) (domain.ArticleRevision, error)
+	DiffRevisions(readerID uint, slug string, from, to int) (domain.RevisionDiff, error)
+	RestoreRevision(actor domain.Actor, slug string, number int) (domain.ArticleView, error)
+}
+
+// CommentLayout is how comments of article are listed.
+type CommentLayout string
+
+const (
+	// LayoutFlat lists every comment in order, replies refer to their parents by ParentID
+	LayoutFlat CommentLayout = "flat"
+	// LayoutTree lists top level comments, and nests replies into them
+	LayoutTree CommentLayout = "tree"
+)
+
+type CommentListOptions struct {
+	Layout CommentLayout
+	// Sort orders top level comments of LayoutTree, replies are always the oldest first
+	Sort CommentSort
+	// ParentID lists replies of the comment instead of top level comments, to continue a thread cut in LayoutTree
+	ParentID *uint
 }
 
 type CommentService interface {
 	Transactional[CommentService]
-	Create(authorID uint, slug string, body string) (domain.CommentView, error)
-	GetFromArticle(readerID uint, slug string) ([]domain.CommentView, error)
-	Delete(authorID, commentID uint) error
+	// Create replies to parent comment if parentID is set.
+	Create(authorID uint, slug string, body string, parentID *uint) (domain.CommentView, error)
+	// GetFromArticle paginates top level comments if layout is LayoutTree, and every comment otherwise.
+	// Replies nested in LayoutTree are cut at a fixed number per response, see CommentView.RepliesCount.
+	GetFromArticle(readerID uint, slug string, options CommentListOptions, pageable Pageable) ([]domain.CommentView, Page, error)
+	// Update lets only author edit body, and keeps previous body as revision.
+	Update(actor domain.Actor, slug string, commentID uint, body string) (domain.CommentView, error)
+	// ListRevisions finds previous bodies of comment from the oldest, only for moderators.
+	ListRevisions(actor domain.Actor, slug string, commentID uint) ([]domain.CommentRevision, error)
+	Delete(actor domain.Actor, commentID uint) error
+}
+
+type TokenService interface {
+	Transactional[TokenService]
+	CreatePersonalAccessToken(userID uint, name string, scopes []string, expiresAt *time.Time) (domain.PersonalAccessToken, error)
+	ListPersonalAccessTokens(userID uint) ([]domain.PersonalAccessToken, error)
+	RevokePersonalAccessToken(userID, id uint) error
+}
+
+type AdminService interface {
+	Transactional[AdminService]
+	ListUsers(actor domain.Actor, pageable Pageable) ([]domain.User, error)
+	UpdateRole(actor domain.Actor, username string, role domain.Role) (domain.User, error)
+}
+
+type AccountService interface {
+	Transactional[AccountService]
+	DeleteAccount(claim domain.AccessClaim) error
+	ExportAccount(userID uint) (domain.AccountExport, error)
 }
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"errors"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type accountService struct {
+	userRepo     ports.UserRepository
+	articleRepo  ports.ArticleRepository
+	authorRepo   ports.AuthorRepository
+	commentRepo  ports.CommentRepository
+	tokenRepo    ports.TokenRepository
+	identityRepo ports.IdentityRepository
+	mfaRepo      ports.MFARepository
+	logger       *zap.SugaredLogger
+}
+
+func NewAccountService(
+	userRepo ports.UserRepository,
+	articleRepo ports.ArticleRepository,
+	authorRepo ports.AuthorRepository,
+	commentRepo ports.CommentRepository,
+	tokenRepo ports.TokenRepository,
+	identityRepo ports.IdentityRepository,
+	mfaRepo ports.MFARepository,
+	logger *zap.Logger,
+) ports.AccountService {
+	return accountService{
+		userRepo:     userRepo,
+		articleRepo:  articleRepo,
+		authorRepo:   authorRepo,
+		commentRepo:  commentRepo,
+		tokenRepo:    tokenRepo,
+		identityRepo: identityRepo,
+		mfaRepo:      mfaRepo,
+		logger:       logger.Sugar().Named("accountService"),
+	}
+}
+
+func (s accountService) WithTx(tx *gorm.DB) ports.AccountService {
+	s.userRepo = s.userRepo.WithTx(tx)
+	s.articleRepo = s.articleRepo.WithTx(tx)
+	s.authorRepo = s.authorRepo.WithTx(tx)
+	s.commentRepo = s.commentRepo.WithTx(tx)
+	s.tokenRepo = s.tokenRepo.WithTx(tx)
+	s.identityRepo = s.identityRepo.WithTx(tx)
+	s.mfaRepo = s.mfaRepo.WithTx(tx)
+	return s
+}
+
+// DeleteAccount anonymizes personal data of user before soft delete.
+// Articles and comments are kept with anonymized author, relations and credentials are removed.
+func (s accountService) DeleteAccount(claim domain.AccessClaim) error {
+	user, err := s.userRepo.FindByID(claim.UID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ports.ErrResourceNotFound
+	} else if err != nil {
+		s.logger.Errorw("failed to find user by id", "id", claim.UID, "err", err)
+		return ports.ErrInternal
+	}
+
+	anonymized, err := s.userRepo.Save(user.Anonymized())
+	if err != nil {
+		s.logger.Errorw("failed to anonymize user", "id", user.ID, "err", err)
+		return ports.ErrInternal
+	}
+	if err := s.authorRepo.UpdateAuthorInfo(anonymized); err != nil {
+		s.logger.Errorw("failed to anonymize author", "id", user.ID, "err", err)
+		return ports.ErrInternal
+	}
+
+	if err := s.userRepo.DeleteFollows(user.ID); err != nil {
+		s.logger.Errorw("failed to delete follows", "id", user.ID, "err", err)
+		return ports.ErrInternal
+	}
+	if err := s.articleRepo.DeleteFavorites(user.ID); err != nil {
+		s.logger.Errorw("failed to delete favorites", "id", user.ID, "err", err)
+		return ports.ErrInternal
+	}
+
+	if err := s.revokeCredentials(claim); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Delete(user.ID); err != nil {
+		s.logger.Errorw("failed to delete user", "id", user.ID, "err", err)
+		return ports.ErrInternal
+	}
+	s.logger.Infow("account is deleted", "id", user.ID)
+	return nil
+}
+
+func (s accountService) revokeCredentials(claim domain.AccessClaim) error {
+	if err := s.tokenRepo.RevokeRefreshTokens(claim.UID); err != nil {
+		s.logger.Errorw("failed to revoke refresh tokens", "id", claim.UID, "err", err)
+		return ports.ErrInternal
+	}
+	if err := s.tokenRepo.DeletePersonalAccessTokens(claim.UID); err != nil {
+		s.logger.Errorw("failed to delete personal access tokens", "id", claim.UID, "err", err)
+		return ports.ErrInternal
+	}
+	if err := s.identityRepo.DeleteIdentities(claim.UID); err != nil {
+		s.logger.Errorw("failed to delete identities", "id", claim.UID, "err", err)
+		return ports.ErrInternal
+	}
+	if err := s.mfaRepo.DeleteTwoFactor(claim.UID); err != nil {
+		s.logger.Errorw("failed to delete two factor", "id", claim.UID, "err", err)
+		return ports.ErrInternal
+	}
+
+	if claim.ID == "" || claim.ExpiresAt == nil {
+		return nil
+	}
+	if _, err := s.tokenRepo.CreateDeniedToken(claim.ID, claim.ExpiresAt.Time); err != nil {
+		s.logger.Errorw("failed to deny access token", "id", claim.UID, "err", err)
+		return ports.ErrInternal
+	}
+	return nil
+}
+
+func (s accountService) ExportAccount(userID uint) (domain.AccountExport, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.AccountExport{}, ports.ErrResourceNotFound
+	} else if err != nil {
+		s.logger.Errorw("failed to find user by id", "id", userID, "err", err)
+		return domain.AccountExport{}, ports.ErrInternal
+	}
+
+	articles, err := s.articleRepo.FindByAuthor(userID)
+	if err != nil {
+		s.logger.Errorw("failed to find articles by author", "id", userID, "err", err)
+		return domain.AccountExport{}, ports.ErrInternal
+	}
+	comments, err := s.commentRepo.FindByAuthor(userID)
+	if err != nil {
+		s.logger.Errorw("failed to find comments by author", "id", userID, "err", err)
+		return domain.AccountExport{}, ports.ErrInternal
+	}
+	favorites, err := s.articleRepo.FindFavoritedArticles(userID)
+	if err != nil {
+		s.logger.Errorw("failed to find favorited articles", "id", userID, "err", err)
+		return domain.AccountExport{}, ports.ErrInternal
+	}
+	followings, err := s.userRepo.FindFollowings(userID)
+	if err != nil {
+		s.logger.Errorw("failed to find followings", "id", userID, "err", err)
+		return domain.AccountExport{}, ports.ErrInternal
+	}
+
+	return domain.AccountExport{
+		User:       user,
+		Articles:   articles,
+		Comments:   comments,
+		Favorites:  favorites,
+		Followings: followings,
+	}, nil
+}
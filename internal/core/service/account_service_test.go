@@ -0,0 +1,111 @@
+package service
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports/mock_ports"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func Test_accountService_DeleteAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+	ar := mock_ports.NewMockArticleRepository(ctrl)
+	aur := mock_ports.NewMockAuthorRepository(ctrl)
+	cr := mock_ports.NewMockCommentRepository(ctrl)
+	tr := mock_ports.NewMockTokenRepository(ctrl)
+	ir := mock_ports.NewMockIdentityRepository(ctrl)
+	mr := mock_ports.NewMockMFARepository(ctrl)
+
+	expiresAt := time.Now().Add(time.Hour)
+	user := domain.User{Model: gorm.Model{ID: 1}, Email: "test@test.com", Username: "test", Bio: "bio"}
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(1))).
+		Return(user, nil)
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(2))).
+		Return(domain.User{}, gorm.ErrRecordNotFound)
+	ur.EXPECT().
+		Save(gomock.Any()).
+		DoAndReturn(func(u domain.User) (domain.User, error) {
+			assert.Equal(t, "deleted-1", u.Username)
+			assert.Equal(t, "deleted-1@deleted.invalid", u.Email)
+			assert.Empty(t, u.Bio)
+			return u, nil
+		})
+	aur.EXPECT().
+		UpdateAuthorInfo(gomock.Any()).
+		DoAndReturn(func(u domain.User) error {
+			assert.Equal(t, "deleted-1", u.Username)
+			return nil
+		})
+	ur.EXPECT().DeleteFollows(gomock.Eq(uint(1))).Return(nil)
+	ar.EXPECT().DeleteFavorites(gomock.Eq(uint(1))).Return(nil)
+	tr.EXPECT().RevokeRefreshTokens(gomock.Eq(uint(1))).Return(nil)
+	tr.EXPECT().DeletePersonalAccessTokens(gomock.Eq(uint(1))).Return(nil)
+	ir.EXPECT().DeleteIdentities(gomock.Eq(uint(1))).Return(nil)
+	mr.EXPECT().DeleteTwoFactor(gomock.Eq(uint(1))).Return(nil)
+	tr.EXPECT().
+		CreateDeniedToken(gomock.Eq("jti"), gomock.Any()).
+		Return(domain.DeniedToken{}, nil)
+	ur.EXPECT().Delete(gomock.Eq(uint(1))).Return(nil)
+
+	s := NewAccountService(ur, ar, aur, cr, tr, ir, mr, zap.NewNop())
+	t.Run("회원 탈퇴 성공", func(t *testing.T) {
+		err := s.DeleteAccount(domain.AccessClaim{
+			UID: 1,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        "jti",
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+			},
+		})
+
+		assert.NoError(t, err)
+	})
+	t.Run("없는 유저", func(t *testing.T) {
+		err := s.DeleteAccount(domain.AccessClaim{UID: 2})
+
+		assert.ErrorIs(t, err, ports.ErrResourceNotFound)
+	})
+}
+
+func Test_accountService_ExportAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+	ar := mock_ports.NewMockArticleRepository(ctrl)
+	cr := mock_ports.NewMockCommentRepository(ctrl)
+
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(1))).
+		Return(domain.User{Model: gorm.Model{ID: 1}, Username: "test"}, nil)
+	ar.EXPECT().
+		FindByAuthor(gomock.Eq(uint(1))).
+		Return([]domain.Article{{Slug: "mine"}}, nil)
+	cr.EXPECT().
+		FindByAuthor(gomock.Eq(uint(1))).
+		Return([]domain.Comment{{Body: "comment"}}, nil)
+	ar.EXPECT().
+		FindFavoritedArticles(gomock.Eq(uint(1))).
+		Return([]domain.Article{{Slug: "favorite"}}, nil)
+	ur.EXPECT().
+		FindFollowings(gomock.Eq(uint(1))).
+		Return([]domain.User{{Username: "following"}}, nil)
+
+	s := NewAccountService(ur, ar, nil, cr, nil, nil, nil, zap.NewNop())
+	t.Run("개인 데이터 내보내기", func(t *testing.T) {
+		export, err := s.ExportAccount(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "test", export.User.Username)
+		assert.Equal(t, "mine", export.Articles[0].Slug)
+		assert.Equal(t, "comment", export.Comments[0].Body)
+		assert.Equal(t, "favorite", export.Favorites[0].Slug)
+		assert.Equal(t, "following", export.Followings[0].Username)
+	})
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type adminService struct {
+	userRepo  ports.UserRepository
+	tokenRepo ports.TokenRepository
+	logger    *zap.SugaredLogger
+}
+
+func NewAdminService(userRepo ports.UserRepository, tokenRepo ports.TokenRepository, logger *zap.Logger) ports.AdminService {
+	return adminService{
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		logger:    logger.Sugar().Named("adminService"),
+	}
+}
+
+func (s adminService) WithTx(tx *gorm.DB) ports.AdminService {
+	s.userRepo = s.userRepo.WithTx(tx)
+	s.tokenRepo = s.tokenRepo.WithTx(tx)
+	return s
+}
+
+func (s adminService) ListUsers(actor domain.Actor, pageable ports.Pageable) ([]domain.User, error) {
+	if err := s.authorize(actor); err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.FindAll(pageable)
+	if err != nil {
+		s.logger.Errorw("failed to find users", "err", err)
+		return nil, ports.ErrInternal
+	}
+	return users, nil
+}
+
+// UpdateRole changes role of user and revokes refresh tokens, so that claim has new role from next login.
+// Actions allowed by role are checked against stored role, so they follow the change at once.
+func (s adminService) UpdateRole(actor domain.Actor, username string, role domain.Role) (domain.User, error) {
+	if err := s.authorize(actor); err != nil {
+		return domain.User{}, err
+	}
+	if !role.Valid() {
+		return domain.User{}, ports.ErrInvalidRole
+	}
+
+	user, err := s.userRepo.FindByUsername(username)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, ports.ErrResourceNotFound
+	} else if err != nil {
+		s.logger.Errorw("failed to find user by username", "username", username, "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	// admin can not demote oneself, so that there is at least one admin
+	if user.ID == actor.ID {
+		return domain.User{}, ports.ErrSelfRoleChange
+	}
+	if user.Role == role {
+		return user, nil
+	}
+
+	err = s.userRepo.UpdateRole(user.ID, role)
+	if err != nil {
+		s.logger.Errorw("failed to update role", "id", user.ID, "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	err = s.tokenRepo.RevokeRefreshTokens(user.ID)
+	if err != nil {
+		s.logger.Errorw("failed to revoke refresh tokens", "id", user.ID, "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	s.logger.Infow("role is updated", "admin-id", actor.ID, "id", user.ID, "from", user.Role, "to", role)
+
+	user.Role = role
+	return user, nil
+}
+
+// authorize checks role stored in database instead of the claim,
+// so that demoted admin can not manage users with token issued before.
+func (s adminService) authorize(actor domain.Actor) error {
+	allowed, err := authorize(s.userRepo, actor, domain.ActionManageUsers, 0)
+	if err != nil {
+		s.logger.Errorw("failed to find user by id", "id", actor.ID, "err", err)
+		return ports.ErrInternal
+	}
+	if !allowed {
+		s.logger.Infow("illegal request to manage users", "user-id", actor.ID)
+		return ports.ErrPermissionDenied
+	}
+	return nil
+}
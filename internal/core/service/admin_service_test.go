@@ -0,0 +1,69 @@
+package service
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports/mock_ports"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func Test_adminService_UpdateRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+	tr := mock_ports.NewMockTokenRepository(ctrl)
+
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(1))).
+		Return(domain.User{Model: gorm.Model{ID: 1}, Username: "admin", Role: domain.RoleAdmin}, nil).
+		AnyTimes()
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(2))).
+		Return(domain.User{Model: gorm.Model{ID: 2}, Username: "test", Role: domain.RoleUser}, nil).
+		AnyTimes()
+	ur.EXPECT().
+		FindByUsername(gomock.Eq("test")).
+		Return(domain.User{Model: gorm.Model{ID: 2}, Username: "test", Role: domain.RoleUser}, nil).
+		AnyTimes()
+	ur.EXPECT().
+		FindByUsername(gomock.Eq("admin")).
+		Return(domain.User{Model: gorm.Model{ID: 1}, Username: "admin", Role: domain.RoleAdmin}, nil).
+		AnyTimes()
+	ur.EXPECT().
+		UpdateRole(gomock.Eq(uint(2)), gomock.Eq(domain.RoleModerator)).
+		Return(nil)
+	tr.EXPECT().
+		RevokeRefreshTokens(gomock.Eq(uint(2))).
+		Return(nil)
+
+	s := NewAdminService(ur, tr, zap.NewNop())
+	t.Run("역할 변경 성공", func(t *testing.T) {
+		user, err := s.UpdateRole(domain.Actor{ID: 1, Role: domain.RoleAdmin}, "test", domain.RoleModerator)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RoleModerator, user.Role)
+	})
+	t.Run("관리자가 아닌 유저", func(t *testing.T) {
+		_, err := s.UpdateRole(domain.Actor{ID: 2, Role: domain.RoleUser}, "test", domain.RoleAdmin)
+
+		assert.ErrorIs(t, err, ports.ErrPermissionDenied)
+	})
+	t.Run("토큰의 역할보다 저장된 역할 우선", func(t *testing.T) {
+		_, err := s.UpdateRole(domain.Actor{ID: 2, Role: domain.RoleAdmin}, "test", domain.RoleAdmin)
+
+		assert.ErrorIs(t, err, ports.ErrPermissionDenied)
+	})
+	t.Run("없는 역할", func(t *testing.T) {
+		_, err := s.UpdateRole(domain.Actor{ID: 1, Role: domain.RoleAdmin}, "test", domain.Role("root"))
+
+		assert.ErrorIs(t, err, ports.ErrInvalidRole)
+	})
+	t.Run("자신의 역할 변경", func(t *testing.T) {
+		_, err := s.UpdateRole(domain.Actor{ID: 1, Role: domain.RoleAdmin}, "admin", domain.RoleUser)
+
+		assert.ErrorIs(t, err, ports.ErrSelfRoleChange)
+	})
+}
@@ -1,13 +1,18 @@
 package service
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
 	"github.com/KumKeeHyun/gin-realworld/pkg/slugutil"
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/samber/lo"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"strings"
+	"time"
 )
 
 type articleService struct {
@@ -33,14 +38,27 @@ func (s articleService) WithTx(tx *gorm.DB) ports.ArticleService {
 	return s
 }
 
-func (s articleService) Create(authorID uint, title, description, body string, tags []string) (domain.ArticleView, error) {
+func (s articleService) Create(authorID uint, title, description, body string, tags []string, status domain.ArticleStatus, publishAt *time.Time, slug *string) (domain.ArticleView, error) {
+	if !status.Valid() {
+		return domain.ArticleView{}, ports.ErrInvalidArticleStatus
+	}
+	if publishAt != nil && status == domain.StatusPublished {
+		return domain.ArticleView{}, ports.ErrInvalidPublishAt
+	}
+	articleSlug := slugutil.Make(title)
+	if slug != nil {
+		if err := s.checkSlug(0, *slug); err != nil {
+			return domain.ArticleView{}, err
+		}
+		articleSlug = *slug
+	}
 	author, err := s.userRepo.FindByID(authorID)
 	if err != nil {
 		s.logger.Errorw("failed to create article", "err", err)
 		return domain.ArticleView{}, ports.ErrInternal
 	}
 	article := domain.Article{
-		Slug:        slugutil.Make(title),
+		Slug:        articleSlug,
 		Title:       title,
 		Description: description,
 		Body:        body,
@@ -51,22 +69,40 @@ func (s articleService) Create(authorID uint, title, description, body string, t
 			Bio:      author.Bio,
 			Image:    author.Image,
 		},
+		Status: status,
+	}
+	if publishAt != nil {
+		// client sends any offset, it is stored in local zone as other times are, see PublishDueArticles
+		article.PublishAt = sql.NullTime{Time: publishAt.Local(), Valid: true}
+	}
+	if status == domain.StatusPublished {
+		article.Publish(time.Now())
 	}
 	saved, err := s.articleRepo.Save(article)
-	if err != nil {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// slug is taken by concurrent request after it was checked
+		return domain.ArticleView{}, ports.ErrDuplicatedSlug
+	} else if err != nil {
 		s.logger.Errorw("failed to create article", "err", err)
 		return domain.ArticleView{}, ports.ErrInternal
 	}
+	_, err = s.articleRepo.SaveRevision(domain.NewArticleRevision(saved, 1, authorID))
+	if err != nil {
+		s.logger.Errorw("failed to record article revision", "err", err)
+		return domain.ArticleView{}, ports.ErrInternal
+	}
 	return domain.NewArticleView(saved, false, false), nil
 }
 
+// Find also finds article by its old slug, then slug of returned article differs from requested one.
 func (s articleService) Find(readerID uint, slug string) (domain.ArticleView, error) {
 	article, err := s.articleRepo.FindBySlug(slug)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return domain.ArticleView{}, ports.ErrResourceNotFound
-	} else if err != nil {
-		s.logger.Errorw("failed to find article", "err", err)
-		return domain.ArticleView{}, ports.ErrInternal
+		article, err = s.articleRepo.FindByOldSlug(slug)
+	}
+	article, err = s.checkReadable(readerID, article, err)
+	if err != nil {
+		return domain.ArticleView{}, err
 	}
 
 	_, favoriteErr := s.articleRepo.FindFavorite(readerID, article.ID)
@@ -87,31 +123,67 @@ func (s articleService) Find(readerID uint, slug string) (domain.ArticleView, er
 	), nil
 }
 
-func (s articleService) ListByConditions(readerID uint, conditions ports.ArticleSearchConditions) ([]domain.ArticleView, error) {
-	articles, err := s.articleRepo.FindBySearchConditions(conditions)
+// findReadable returns ErrResourceNotFound for draft of others, so that existence of draft is not revealed.
+func (s articleService) findReadable(readerID uint, slug string) (domain.Article, error) {
+	article, err := s.articleRepo.FindBySlug(slug)
+	return s.checkReadable(readerID, article, err)
+}
+
+func (s articleService) checkReadable(readerID uint, article domain.Article, err error) (domain.Article, error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Article{}, ports.ErrResourceNotFound
+	} else if err != nil {
+		s.logger.Errorw("failed to find article", "err", err)
+		return domain.Article{}, ports.ErrInternal
+	}
+	if !article.ReadableBy(readerID) {
+		return domain.Article{}, ports.ErrResourceNotFound
+	}
+	return article, nil
+}
+
+func (s articleService) ListByConditions(readerID uint, conditions ports.ArticleSearchConditions) ([]domain.ArticleView, ports.Page, error) {
+	if conditions.Cursor != nil && !conditions.OrderedByCreation() {
+		return nil, ports.Page{}, ports.ErrUnsupportedCursor
+	}
+	if conditions.Sort == ports.SortTrending {
+		conditions.TrendingSince = time.Now().Add(-ports.TrendingWindow)
+	}
+	conditions.ReaderID = readerID
+	articles, page, err := s.articleRepo.FindBySearchConditions(conditions)
 	if err != nil {
 		s.logger.Errorw("failed to search article", "conditions", conditions, "err", err)
-		return nil, ports.ErrInternal
+		return nil, ports.Page{}, ports.ErrInternal
 	} else if len(articles) == 0 {
-		return nil, nil
+		return nil, page, nil
 	}
 
 	articleIDs := lo.Map(articles, func(article domain.Article, index int) uint { return article.ID })
 	favorites, err := s.articleRepo.FindFavorites(readerID, articleIDs)
 	if err != nil {
 		s.logger.Errorw("failed to find favorites", "err", err)
-		return nil, ports.ErrInternal
+		return nil, ports.Page{}, ports.ErrInternal
 	}
 
 	authorIDs := lo.Map(articles, func(article domain.Article, index int) uint { return article.Author.ID })
 	follows, err := s.userRepo.FindFollows(readerID, authorIDs)
 	if err != nil {
 		s.logger.Errorw("failed to find follows", "err", err)
-		return nil, ports.ErrInternal
+		return nil, ports.Page{}, ports.ErrInternal
 	}
 
 	articleViews := zipToArticleView(articles, favorites, follows)
-	return articleViews, err
+	if conditions.Query != nil {
+		snippets, err := s.articleRepo.FindSnippets(*conditions.Query, articleIDs)
+		if err != nil {
+			s.logger.Errorw("failed to find snippets", "err", err)
+			return nil, ports.Page{}, ports.ErrInternal
+		}
+		for i := range articleViews {
+			articleViews[i].Snippet = snippets[articleViews[i].ID]
+		}
+	}
+	return articleViews, page, nil
 }
 
 func zipToArticleView(articles []domain.Article, favorites []domain.Favorite, follows []domain.Follow) []domain.ArticleView {
@@ -125,89 +197,129 @@ func zipToArticleView(articles []domain.Article, favorites []domain.Favorite, fo
 	})
 }
 
-func (s articleService) ListFeed(readerID uint, pageable ports.Pageable) ([]domain.ArticleView, error) {
-	articles, err := s.articleRepo.FindFeed(readerID, pageable)
+func (s articleService) ListFeed(readerID uint, pageable ports.Pageable) ([]domain.ArticleView, ports.Page, error) {
+	articles, page, err := s.articleRepo.FindFeed(readerID, pageable)
 	if err != nil {
 		s.logger.Errorw("failed to search feed", "err", err)
-		return nil, ports.ErrInternal
+		return nil, ports.Page{}, ports.ErrInternal
 	} else if len(articles) == 0 {
-		return nil, nil
+		return nil, page, nil
 	}
 
 	articleIDs := lo.Map(articles, func(article domain.Article, index int) uint { return article.ID })
 	favorites, err := s.articleRepo.FindFavorites(readerID, articleIDs)
 	if err != nil {
 		s.logger.Errorw("failed to find favorites", "err", err)
-		return nil, ports.ErrInternal
+		return nil, ports.Page{}, ports.ErrInternal
 	}
 
 	authorIDs := lo.Map(articles, func(article domain.Article, index int) uint { return article.Author.ID })
 	follows, err := s.userRepo.FindFollows(readerID, authorIDs)
 	if err != nil {
 		s.logger.Errorw("failed to find follows", "err", err)
-		return nil, ports.ErrInternal
+		return nil, ports.Page{}, ports.ErrInternal
 	}
 
 	articleViews := zipToArticleView(articles, favorites, follows)
-	return articleViews, err
+	return articleViews, page, nil
 }
 
-func (s articleService) Update(authorID uint, slug string, fields ports.ArticleUpdateFields) (domain.ArticleView, error) {
-	article, err := s.articleRepo.FindBySlug(slug)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return domain.ArticleView{}, ports.ErrResourceNotFound
-	} else if err != nil {
-		s.logger.Errorw("failed to find article", "err", err)
-		return domain.ArticleView{}, ports.ErrInternal
+// ListDrafts returns drafts of author. Drafts are not favorited nor followed by author, so views are made without lookup.
+func (s articleService) ListDrafts(authorID uint, pageable ports.Pageable) ([]domain.ArticleView, ports.Page, error) {
+	articles, page, err := s.articleRepo.FindDrafts(authorID, pageable)
+	if err != nil {
+		s.logger.Errorw("failed to find drafts", "user-id", authorID, "err", err)
+		return nil, ports.Page{}, ports.ErrInternal
 	}
+	return lo.Map(articles, func(article domain.Article, index int) domain.ArticleView {
+		return domain.NewArticleView(article, false, false)
+	}), page, nil
+}
 
-	if article.Author.ID != authorID {
-		s.logger.Infow("illegal request to update non-owned article", "user-id", authorID, "err", err)
+func (s articleService) Update(actor domain.Actor, slug string, fields ports.ArticleUpdateFields) (domain.ArticleView, error) {
+	article, err := s.findReadable(actor.ID, slug)
+	if err != nil {
+		return domain.ArticleView{}, err
+	}
+
+	if !actor.Can(domain.ActionUpdateArticle, article.Author.ID) {
+		s.logger.Infow("illegal request to update non-owned article", "user-id", actor.ID, "err", err)
 		return domain.ArticleView{}, ports.ErrNonOwnedContent
 	}
 
+	if fields.PublishAt != nil && article.Published() {
+		return domain.ArticleView{}, ports.ErrInvalidPublishAt
+	}
+	if fields.Slug != nil {
+		if err := s.checkSlug(article.ID, *fields.Slug); err != nil {
+			return domain.ArticleView{}, err
+		}
+	}
+
 	updated := updateArticleFields(article, fields)
 	updated, err = s.articleRepo.Save(updated)
-	if err != nil {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ArticleView{}, ports.ErrDuplicatedSlug
+	} else if err != nil {
 		s.logger.Errorw("failed to update article", "err", err)
 		return domain.ArticleView{}, ports.ErrInternal
 	}
+	if err := s.recordSlugChange(article, updated); err != nil {
+		return domain.ArticleView{}, err
+	}
+	if !updated.ContentEquals(article) {
+		if err := s.recordRevision(article, updated, actor.ID, sql.NullInt32{}); err != nil {
+			return domain.ArticleView{}, err
+		}
+	}
 
-	_, favoriteErr := s.articleRepo.FindFavorite(authorID, article.ID)
-	if err != nil && !errors.Is(favoriteErr, gorm.ErrRecordNotFound) {
-		s.logger.Errorw("failed to find favorite", "err", err)
+	_, favoriteErr := s.articleRepo.FindFavorite(actor.ID, article.ID)
+	if favoriteErr != nil && !errors.Is(favoriteErr, gorm.ErrRecordNotFound) {
+		s.logger.Errorw("failed to find favorite", "err", favoriteErr)
 		return domain.ArticleView{}, ports.ErrInternal
 	}
-	return domain.NewArticleView(article, favoriteErr == nil, false), nil
+	return domain.NewArticleView(updated, favoriteErr == nil, false), nil
 }
 
+// updateArticleFields generates new slug only when title is changed and slug is not set.
 func updateArticleFields(article domain.Article, fields ports.ArticleUpdateFields) domain.Article {
-	if fields.Title != nil {
+	if fields.Title != nil && *fields.Title != article.Title {
 		article.Title = *fields.Title
 		article.Slug = slugutil.Make(article.Title)
 	}
+	if fields.Slug != nil {
+		article.Slug = *fields.Slug
+	}
 	if fields.Description != nil {
 		article.Description = *fields.Description
 	}
 	if fields.Body != nil {
 		article.Body = *fields.Body
 	}
+	if fields.PublishAt != nil {
+		article.PublishAt = sql.NullTime{Time: fields.PublishAt.Local(), Valid: true}
+	}
 	return article
 }
 
-func (s articleService) Delete(authorID uint, slug string) error {
-	article, err := s.articleRepo.FindBySlug(slug)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return ports.ErrResourceNotFound
-	} else if err != nil {
-		s.logger.Errorw("failed to find article", "err", err)
-		return ports.ErrInternal
+func (s articleService) Delete(actor domain.Actor, slug string) error {
+	article, err := s.findReadable(actor.ID, slug)
+	if err != nil {
+		return err
 	}
 
-	if article.Author.ID != authorID {
-		s.logger.Infow("illegal request to delete non-owned article", "user-id", authorID, "err", err)
+	allowed, err := authorize(s.userRepo, actor, domain.ActionDeleteArticle, article.Author.ID)
+	if err != nil {
+		s.logger.Errorw("failed to find user by id", "id", actor.ID, "err", err)
+		return ports.ErrInternal
+	}
+	if !allowed {
+		s.logger.Infow("illegal request to delete non-owned article", "user-id", actor.ID)
 		return ports.ErrNonOwnedContent
 	}
+	if actor.ID != article.Author.ID {
+		s.logger.Infow("article is deleted by moderator", "user-id", actor.ID, "slug", slug)
+	}
 
 	err = s.articleRepo.DeleteBySlug(slug)
 	if err != nil {
@@ -217,16 +329,56 @@ func (s articleService) Delete(authorID uint, slug string) error {
 	return nil
 }
 
-func (s articleService) Favorite(userID uint, slug string) (domain.ArticleView, error) {
-	article, err := s.articleRepo.FindBySlug(slug)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return domain.ArticleView{}, ports.ErrResourceNotFound
-	} else if err != nil {
-		s.logger.Errorw("failed to find article", "err", err)
+// Publish makes draft or unlisted article visible in lists. Publishing published article changes nothing.
+func (s articleService) Publish(actor domain.Actor, slug string) (domain.ArticleView, error) {
+	article, err := s.findReadable(actor.ID, slug)
+	if err != nil {
+		return domain.ArticleView{}, err
+	}
+
+	if !actor.Can(domain.ActionUpdateArticle, article.Author.ID) {
+		s.logger.Infow("illegal request to publish non-owned article", "user-id", actor.ID)
+		return domain.ArticleView{}, ports.ErrNonOwnedContent
+	}
+	if article.Published() {
+		return domain.NewArticleView(article, false, false), nil
+	}
+
+	article.Publish(time.Now())
+	article, err = s.articleRepo.Save(article)
+	if err != nil {
+		s.logger.Errorw("failed to publish article", "err", err)
 		return domain.ArticleView{}, ports.ErrInternal
 	}
 
-	_, err = s.articleRepo.CreateFavorite(userID, article.ID)
+	_, favoriteErr := s.articleRepo.FindFavorite(actor.ID, article.ID)
+	if favoriteErr != nil && !errors.Is(favoriteErr, gorm.ErrRecordNotFound) {
+		s.logger.Errorw("failed to find favorite", "err", favoriteErr)
+		return domain.ArticleView{}, ports.ErrInternal
+	}
+	return domain.NewArticleView(article, favoriteErr == nil, false), nil
+}
+
+// PublishScheduled publishes articles whose publishAt is due. It is run by scheduler in a transaction.
+func (s articleService) PublishScheduled(now time.Time) (int64, error) {
+	published, err := s.articleRepo.PublishDueArticles(now)
+	if err != nil {
+		s.logger.Errorw("failed to publish scheduled articles", "err", err)
+		return 0, ports.ErrInternal
+	}
+	if published > 0 {
+		s.logger.Infow("scheduled articles are published", "count", published)
+	}
+	return published, nil
+}
+
+func (s articleService) Favorite(userID uint, slug string) (domain.ArticleView, error) {
+	article, err := s.findReadable(userID, slug)
+	if err != nil {
+		return domain.ArticleView{}, err
+	}
+
+	article.FavoritesCount, err = s.articleRepo.CreateFavorite(userID, article.ID)
 	if err != nil {
 		s.logger.Errorw("failed to create favorite", "err", err)
 		return domain.ArticleView{}, ports.ErrInternal
@@ -241,15 +393,12 @@ func (s articleService) Favorite(userID uint, slug string) (domain.ArticleView,
 }
 
 func (s articleService) Unfavorite(userID uint, slug string) (domain.ArticleView, error) {
-	article, err := s.articleRepo.FindBySlug(slug)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return domain.ArticleView{}, ports.ErrResourceNotFound
-	} else if err != nil {
-		s.logger.Errorw("failed to find article", "err", err)
-		return domain.ArticleView{}, ports.ErrInternal
+	article, err := s.findReadable(userID, slug)
+	if err != nil {
+		return domain.ArticleView{}, err
 	}
 
-	err = s.articleRepo.DeleteFavorite(userID, article.ID)
+	article.FavoritesCount, err = s.articleRepo.DeleteFavorite(userID, article.ID)
 	if err != nil {
 		s.logger.Errorw("failed to delete favorite", "err", err)
 		return domain.ArticleView{}, ports.ErrInternal
@@ -263,11 +412,207 @@ func (s articleService) Unfavorite(userID uint, slug string) (domain.ArticleView
 	return domain.NewArticleView(article, false, followErr == nil), nil
 }
 
-func (s articleService) ListTags() ([]string, error) {
-	tags, err := s.articleRepo.FindTags()
+func (s articleService) ReconcileFavoritesCounts() (int64, error) {
+	reconciled, err := s.articleRepo.ReconcileFavoritesCounts()
+	if err != nil {
+		s.logger.Errorw("failed to reconcile favorites counts", "err", err)
+		return 0, ports.ErrInternal
+	}
+	if reconciled > 0 {
+		s.logger.Warnw("reconciled drifted favorites counts", "articles", reconciled)
+	}
+	return reconciled, nil
+}
+
+func (s articleService) ListTags(readerID uint) ([]string, error) {
+	tags, err := s.articleRepo.FindTags(readerID)
 	if err != nil {
 		s.logger.Errorw("failed to find tags", "err", err)
 		return nil, ports.ErrInternal
 	}
 	return tags, nil
 }
+
+// recordRevision appends revision of updated article. Article created before revisions were recorded
+// gets its previous content as the first revision, so that the update can still be rolled back.
+func (s articleService) recordRevision(previous, updated domain.Article, editorID uint, restoredFrom sql.NullInt32) error {
+	latest, err := s.articleRepo.FindLatestRevision(updated.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		latest, err = s.articleRepo.SaveRevision(domain.NewArticleRevision(previous, 1, previous.Author.ID))
+	}
+	if err != nil {
+		s.logger.Errorw("failed to record article revision", "err", err)
+		return ports.ErrInternal
+	}
+
+	revision := domain.NewArticleRevision(updated, latest.Number+1, editorID)
+	revision.RestoredFrom = restoredFrom
+	_, err = s.articleRepo.SaveRevision(revision)
+	if err != nil {
+		s.logger.Errorw("failed to record article revision", "err", err)
+		return ports.ErrInternal
+	}
+	return nil
+}
+
+func (s articleService) ListRevisions(readerID uint, slug string) ([]domain.ArticleRevision, error) {
+	article, err := s.findReadable(readerID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.articleRepo.FindRevisions(article.ID)
+	if err != nil {
+		s.logger.Errorw("failed to find article revisions", "err", err)
+		return nil, ports.ErrInternal
+	}
+	return revisions, nil
+}
+
+func (s articleService) FindRevision(readerID uint, slug string, number int) (domain.ArticleRevision, error) {
+	article, err := s.findReadable(readerID, slug)
+	if err != nil {
+		return domain.ArticleRevision{}, err
+	}
+	return s.findRevision(article.ID, number)
+}
+
+func (s articleService) findRevision(articleID uint, number int) (domain.ArticleRevision, error) {
+	revision, err := s.articleRepo.FindRevision(articleID, number)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ArticleRevision{}, ports.ErrResourceNotFound
+	} else if err != nil {
+		s.logger.Errorw("failed to find article revision", "err", err)
+		return domain.ArticleRevision{}, ports.ErrInternal
+	}
+	return revision, nil
+}
+
+func (s articleService) DiffRevisions(readerID uint, slug string, from, to int) (domain.RevisionDiff, error) {
+	article, err := s.findReadable(readerID, slug)
+	if err != nil {
+		return domain.RevisionDiff{}, err
+	}
+	fromRevision, err := s.findRevision(article.ID, from)
+	if err != nil {
+		return domain.RevisionDiff{}, err
+	}
+	toRevision, err := s.findRevision(article.ID, to)
+	if err != nil {
+		return domain.RevisionDiff{}, err
+	}
+
+	unified, err := diffRevisions(fromRevision, toRevision)
+	if err != nil {
+		s.logger.Errorw("failed to diff article revisions", "err", err)
+		return domain.RevisionDiff{}, ports.ErrInternal
+	}
+	return domain.RevisionDiff{From: from, To: to, Unified: unified}, nil
+}
+
+// diffRevisions writes unified diff of each changed field, like diff of multiple files.
+func diffRevisions(from, to domain.ArticleRevision) (string, error) {
+	fields := []struct {
+		name     string
+		from, to string
+	}{
+		{"title", from.Title, to.Title},
+		{"description", from.Description, to.Description},
+		{"body", from.Body, to.Body},
+	}
+
+	var sb strings.Builder
+	for _, field := range fields {
+		err := difflib.WriteUnifiedDiff(&sb, difflib.UnifiedDiff{
+			A:        difflib.SplitLines(field.from),
+			B:        difflib.SplitLines(field.to),
+			FromFile: fmt.Sprintf("revisions/%d/%s", from.Number, field.name),
+			ToFile:   fmt.Sprintf("revisions/%d/%s", to.Number, field.name),
+			Context:  3,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return sb.String(), nil
+}
+
+// RestoreRevision overwrites article content with old revision. Restoring is recorded as a new revision.
+func (s articleService) RestoreRevision(actor domain.Actor, slug string, number int) (domain.ArticleView, error) {
+	article, err := s.findReadable(actor.ID, slug)
+	if err != nil {
+		return domain.ArticleView{}, err
+	}
+	if !actor.Can(domain.ActionUpdateArticle, article.Author.ID) {
+		s.logger.Infow("illegal request to restore non-owned article", "user-id", actor.ID)
+		return domain.ArticleView{}, ports.ErrNonOwnedContent
+	}
+
+	revision, err := s.findRevision(article.ID, number)
+	if err != nil {
+		return domain.ArticleView{}, err
+	}
+	// only content is restored, slug is kept even if title differs
+	fields := ports.ArticleUpdateFields{
+		Title:       &revision.Title,
+		Description: &revision.Description,
+		Body:        &revision.Body,
+		Slug:        &article.Slug,
+	}
+
+	restored, err := s.articleRepo.Save(updateArticleFields(article, fields))
+	if err != nil {
+		s.logger.Errorw("failed to restore article", "err", err)
+		return domain.ArticleView{}, ports.ErrInternal
+	}
+	err = s.recordRevision(article, restored, actor.ID, sql.NullInt32{Int32: int32(number), Valid: true})
+	if err != nil {
+		return domain.ArticleView{}, err
+	}
+
+	_, favoriteErr := s.articleRepo.FindFavorite(actor.ID, article.ID)
+	if favoriteErr != nil && !errors.Is(favoriteErr, gorm.ErrRecordNotFound) {
+		s.logger.Errorw("failed to find favorite", "err", favoriteErr)
+		return domain.ArticleView{}, ports.ErrInternal
+	}
+	return domain.NewArticleView(restored, favoriteErr == nil, false), nil
+}
+
+// checkSlug checks custom slug is not used by other article, neither as current slug nor as old slug.
+func (s articleService) checkSlug(articleID uint, slug string) error {
+	if !slugutil.Valid(slug) {
+		return ports.ErrInvalidSlug
+	}
+	for _, find := range []func(string) (domain.Article, error){
+		s.articleRepo.FindBySlug,
+		s.articleRepo.FindByOldSlug,
+	} {
+		article, err := find(slug)
+		if err == nil && article.ID != articleID {
+			return ports.ErrDuplicatedSlug
+		} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.Errorw("failed to find article", "err", err)
+			return ports.ErrInternal
+		}
+	}
+	return nil
+}
+
+// recordSlugChange keeps previous slug in history. New slug might be an old slug of the article taken back,
+// which is removed from history.
+func (s articleService) recordSlugChange(previous, updated domain.Article) error {
+	if previous.Slug == updated.Slug {
+		return nil
+	}
+	err := s.articleRepo.DeleteSlugHistory(updated.Slug)
+	if err != nil {
+		s.logger.Errorw("failed to delete slug history", "err", err)
+		return ports.ErrInternal
+	}
+	_, err = s.articleRepo.SaveSlugHistory(domain.SlugHistory{Slug: previous.Slug, ArticleID: previous.ID})
+	if err != nil {
+		s.logger.Errorw("failed to save slug history", "err", err)
+		return ports.ErrInternal
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"testing"
+	"time"
 )
 
 func Test_articleService_Update(t *testing.T) {
@@ -24,24 +25,45 @@ func Test_articleService_Update(t *testing.T) {
 			Author: domain.Author{ID: 1},
 		}, nil).
 		AnyTimes()
+	ar.EXPECT().
+		FindBySlug(gomock.Eq("draft-slug")).
+		Return(domain.Article{
+			Model:  gorm.Model{ID: 2},
+			Slug:   "draft-slug",
+			Status: domain.StatusDraft,
+			Author: domain.Author{ID: 1},
+		}, nil).
+		AnyTimes()
 	ar.EXPECT().
 		Save(gomock.Any()).
-		Return(domain.Article{}, nil)
+		DoAndReturn(func(article domain.Article) (domain.Article, error) {
+			return article, nil
+		})
 	ar.EXPECT().
 		FindFavorite(gomock.Any(), gomock.Eq(uint(1))).
 		Return(domain.Favorite{}, nil)
 
 	s := NewArticleService(ar, ur, zap.NewNop())
 	t.Run("글 수정 성공", func(t *testing.T) {
-		_, err := s.Update(1, "test-slug", ports.ArticleUpdateFields{})
+		_, err := s.Update(domain.Actor{ID: 1, Role: domain.RoleUser}, "test-slug", ports.ArticleUpdateFields{})
 
 		assert.NoError(t, err)
 	})
 	t.Run("다른 유저의 글 수정", func(t *testing.T) {
-		_, err := s.Update(2, "test-slug", ports.ArticleUpdateFields{})
+		_, err := s.Update(domain.Actor{ID: 2, Role: domain.RoleUser}, "test-slug", ports.ArticleUpdateFields{})
+
+		assert.ErrorIs(t, err, ports.ErrNonOwnedContent)
+	})
+	t.Run("관리자도 다른 유저의 글은 수정 불가", func(t *testing.T) {
+		_, err := s.Update(domain.Actor{ID: 2, Role: domain.RoleAdmin}, "test-slug", ports.ArticleUpdateFields{})
 
 		assert.ErrorIs(t, err, ports.ErrNonOwnedContent)
 	})
+	t.Run("다른 유저의 초안은 없는 글", func(t *testing.T) {
+		_, err := s.Update(domain.Actor{ID: 2, Role: domain.RoleUser}, "draft-slug", ports.ArticleUpdateFields{})
+
+		assert.ErrorIs(t, err, ports.ErrResourceNotFound)
+	})
 }
 
 func Test_articleService_Delete(t *testing.T) {
@@ -57,20 +79,490 @@ func Test_articleService_Delete(t *testing.T) {
 			Author: domain.Author{ID: 1},
 		}, nil).
 		AnyTimes()
+	ar.EXPECT().
+		FindBySlug(gomock.Eq("draft-slug")).
+		Return(domain.Article{
+			Model:  gorm.Model{ID: 2},
+			Slug:   "draft-slug",
+			Status: domain.StatusDraft,
+			Author: domain.Author{ID: 1},
+		}, nil).
+		AnyTimes()
 	ar.EXPECT().
 		DeleteBySlug("test-slug").
 		Return(nil).
 		AnyTimes()
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(2))).
+		Return(domain.User{Model: gorm.Model{ID: 2}, Role: domain.RoleModerator}, nil).
+		AnyTimes()
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(3))).
+		Return(domain.User{Model: gorm.Model{ID: 3}, Role: domain.RoleUser}, nil).
+		AnyTimes()
 
 	s := NewArticleService(ar, ur, zap.NewNop())
 	t.Run("글 삭제 성공", func(t *testing.T) {
-		err := s.Delete(1, "test-slug")
+		err := s.Delete(domain.Actor{ID: 1, Role: domain.RoleUser}, "test-slug")
 
 		assert.NoError(t, err)
 	})
 	t.Run("다른 유저의 글 삭제", func(t *testing.T) {
-		err := s.Delete(2, "test-slug")
+		err := s.Delete(domain.Actor{ID: 3, Role: domain.RoleUser}, "test-slug")
+
+		assert.ErrorIs(t, err, ports.ErrNonOwnedContent)
+	})
+	t.Run("모더레이터의 글 삭제", func(t *testing.T) {
+		err := s.Delete(domain.Actor{ID: 2, Role: domain.RoleModerator}, "test-slug")
+
+		assert.NoError(t, err)
+	})
+	t.Run("토큰의 역할보다 저장된 역할 우선", func(t *testing.T) {
+		err := s.Delete(domain.Actor{ID: 3, Role: domain.RoleModerator}, "test-slug")
 
 		assert.ErrorIs(t, err, ports.ErrNonOwnedContent)
 	})
+	t.Run("다른 유저의 초안은 없는 글", func(t *testing.T) {
+		err := s.Delete(domain.Actor{ID: 3, Role: domain.RoleUser}, "draft-slug")
+
+		assert.ErrorIs(t, err, ports.ErrResourceNotFound)
+	})
+}
+
+func Test_articleService_Publish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ar := mock_ports.NewMockArticleRepository(ctrl)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+
+	ar.EXPECT().
+		FindBySlug(gomock.Eq("draft")).
+		Return(domain.Article{
+			Model:  gorm.Model{ID: 1},
+			Slug:   "draft",
+			Status: domain.StatusDraft,
+			Author: domain.Author{ID: 1},
+		}, nil).
+		AnyTimes()
+	ar.EXPECT().
+		FindBySlug(gomock.Eq("unlisted")).
+		Return(domain.Article{
+			Model:  gorm.Model{ID: 2},
+			Slug:   "unlisted",
+			Status: domain.StatusUnlisted,
+			Author: domain.Author{ID: 1},
+		}, nil).
+		AnyTimes()
+	ar.EXPECT().
+		Save(gomock.Any()).
+		DoAndReturn(func(article domain.Article) (domain.Article, error) {
+			assert.Equal(t, domain.StatusPublished, article.Status)
+			assert.True(t, article.PublishedAt.Valid)
+			return article, nil
+		})
+	ar.EXPECT().
+		FindFavorite(gomock.Eq(uint(1)), gomock.Eq(uint(1))).
+		Return(domain.Favorite{}, gorm.ErrRecordNotFound)
+
+	s := NewArticleService(ar, ur, zap.NewNop())
+	t.Run("초안 발행 성공", func(t *testing.T) {
+		article, err := s.Publish(domain.Actor{ID: 1}, "draft")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusPublished, article.Status)
+	})
+	t.Run("다른 유저의 초안은 보이지 않음", func(t *testing.T) {
+		_, err := s.Publish(domain.Actor{ID: 2}, "draft")
+
+		assert.ErrorIs(t, err, ports.ErrResourceNotFound)
+	})
+	t.Run("다른 유저의 미등록 글 발행", func(t *testing.T) {
+		_, err := s.Publish(domain.Actor{ID: 2}, "unlisted")
+
+		assert.ErrorIs(t, err, ports.ErrNonOwnedContent)
+	})
+	t.Run("다른 유저의 초안 조회", func(t *testing.T) {
+		_, err := s.Find(2, "draft")
+
+		assert.ErrorIs(t, err, ports.ErrResourceNotFound)
+	})
+}
+
+func Test_articleService_PublishScheduled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ar := mock_ports.NewMockArticleRepository(ctrl)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+	now := time.Date(2023, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	ar.EXPECT().
+		PublishDueArticles(gomock.Eq(now)).
+		Return(int64(3), nil)
+
+	s := NewArticleService(ar, ur, zap.NewNop())
+	t.Run("예약된 글 발행", func(t *testing.T) {
+		published, err := s.PublishScheduled(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), published)
+	})
+	t.Run("발행 상태로 예약 글 작성", func(t *testing.T) {
+		publishAt := now.Add(time.Hour)
+		_, err := s.Create(1, "title", "description", "body", nil, domain.StatusPublished, &publishAt, nil)
+
+		assert.ErrorIs(t, err, ports.ErrInvalidPublishAt)
+	})
+}
+
+func Test_articleService_Favorite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ar := mock_ports.NewMockArticleRepository(ctrl)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+
+	ar.EXPECT().
+		FindBySlug(gomock.Eq("test-slug")).
+		Return(domain.Article{Model: gorm.Model{ID: 1}, Slug: "test-slug", FavoritesCount: 2, Author: domain.Author{ID: 2}}, nil).
+		AnyTimes()
+	ar.EXPECT().
+		FindBySlug(gomock.Eq("draft-slug")).
+		Return(domain.Article{Model: gorm.Model{ID: 2}, Slug: "draft-slug", Status: domain.StatusDraft, Author: domain.Author{ID: 2}}, nil).
+		AnyTimes()
+	ur.EXPECT().
+		FindFollow(gomock.Any(), gomock.Any()).
+		Return(domain.Follow{}, gorm.ErrRecordNotFound).
+		AnyTimes()
+
+	s := NewArticleService(ar, ur, zap.NewNop())
+	t.Run("좋아요 수는 저장소에서 갱신된 값", func(t *testing.T) {
+		ar.EXPECT().
+			CreateFavorite(gomock.Eq(uint(1)), gomock.Eq(uint(1))).
+			Return(3, nil)
+
+		article, err := s.Favorite(1, "test-slug")
+
+		assert.NoError(t, err)
+		assert.True(t, article.Favorited)
+		assert.Equal(t, 3, article.FavoritesCount)
+	})
+	t.Run("좋아요 취소", func(t *testing.T) {
+		ar.EXPECT().
+			DeleteFavorite(gomock.Eq(uint(1)), gomock.Eq(uint(1))).
+			Return(1, nil)
+
+		article, err := s.Unfavorite(1, "test-slug")
+
+		assert.NoError(t, err)
+		assert.False(t, article.Favorited)
+		assert.Equal(t, 1, article.FavoritesCount)
+	})
+	t.Run("다른 유저의 초안은 좋아요 취소 불가", func(t *testing.T) {
+		_, err := s.Unfavorite(1, "draft-slug")
+
+		assert.ErrorIs(t, err, ports.ErrResourceNotFound)
+	})
+	t.Run("좋아요 수 보정", func(t *testing.T) {
+		ar.EXPECT().
+			ReconcileFavoritesCounts().
+			Return(int64(2), nil)
+
+		reconciled, err := s.ReconcileFavoritesCounts()
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), reconciled)
+	})
+}
+
+func Test_articleService_Revisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ar := mock_ports.NewMockArticleRepository(ctrl)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+
+	ar.EXPECT().
+		FindBySlug(gomock.Eq("test-slug")).
+		Return(domain.Article{
+			Model:  gorm.Model{ID: 1},
+			Slug:   "test-slug",
+			Title:  "title",
+			Body:   "second line",
+			Author: domain.Author{ID: 1},
+		}, nil).
+		AnyTimes()
+	ar.EXPECT().
+		Save(gomock.Any()).
+		DoAndReturn(func(article domain.Article) (domain.Article, error) {
+			return article, nil
+		}).
+		AnyTimes()
+	ar.EXPECT().
+		FindFavorite(gomock.Any(), gomock.Eq(uint(1))).
+		Return(domain.Favorite{}, gorm.ErrRecordNotFound).
+		AnyTimes()
+	ar.EXPECT().
+		FindRevision(gomock.Eq(uint(1)), gomock.Eq(1)).
+		Return(domain.ArticleRevision{ArticleID: 1, Number: 1, Title: "title", Body: "first line"}, nil).
+		AnyTimes()
+	ar.EXPECT().
+		FindRevision(gomock.Eq(uint(1)), gomock.Eq(2)).
+		Return(domain.ArticleRevision{ArticleID: 1, Number: 2, Title: "title", Body: "second line"}, nil).
+		AnyTimes()
+	ar.EXPECT().
+		FindRevision(gomock.Eq(uint(1)), gomock.Eq(3)).
+		Return(domain.ArticleRevision{ArticleID: 1, Number: 3, Title: "old title", Body: "old line"}, nil).
+		AnyTimes()
+	ar.EXPECT().
+		FindRevision(gomock.Eq(uint(1)), gomock.Any()).
+		Return(domain.ArticleRevision{}, gorm.ErrRecordNotFound).
+		AnyTimes()
+
+	s := NewArticleService(ar, ur, zap.NewNop())
+	t.Run("글 수정시 리비전 기록", func(t *testing.T) {
+		ar.EXPECT().
+			FindLatestRevision(gomock.Eq(uint(1))).
+			Return(domain.ArticleRevision{Number: 2}, nil)
+		ar.EXPECT().
+			SaveRevision(gomock.Any()).
+			DoAndReturn(func(revision domain.ArticleRevision) (domain.ArticleRevision, error) {
+				assert.Equal(t, 3, revision.Number)
+				assert.Equal(t, "third line", revision.Body)
+				assert.Equal(t, uint(1), revision.EditorID)
+				return revision, nil
+			})
+
+		body := "third line"
+		article, err := s.Update(domain.Actor{ID: 1}, "test-slug", ports.ArticleUpdateFields{Body: &body})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "third line", article.Body)
+	})
+	t.Run("리비전이 없던 글은 이전 내용을 첫 리비전으로 기록", func(t *testing.T) {
+		ar.EXPECT().
+			FindLatestRevision(gomock.Eq(uint(1))).
+			Return(domain.ArticleRevision{}, gorm.ErrRecordNotFound)
+		first := ar.EXPECT().
+			SaveRevision(gomock.Any()).
+			DoAndReturn(func(revision domain.ArticleRevision) (domain.ArticleRevision, error) {
+				assert.Equal(t, 1, revision.Number)
+				assert.Equal(t, "second line", revision.Body)
+				return revision, nil
+			})
+		ar.EXPECT().
+			SaveRevision(gomock.Any()).
+			DoAndReturn(func(revision domain.ArticleRevision) (domain.ArticleRevision, error) {
+				assert.Equal(t, 2, revision.Number)
+				assert.Equal(t, "third line", revision.Body)
+				return revision, nil
+			}).
+			After(first)
+
+		body := "third line"
+		_, err := s.Update(domain.Actor{ID: 1}, "test-slug", ports.ArticleUpdateFields{Body: &body})
+
+		assert.NoError(t, err)
+	})
+	t.Run("리비전 복원", func(t *testing.T) {
+		ar.EXPECT().
+			FindLatestRevision(gomock.Eq(uint(1))).
+			Return(domain.ArticleRevision{Number: 2}, nil)
+		ar.EXPECT().
+			SaveRevision(gomock.Any()).
+			DoAndReturn(func(revision domain.ArticleRevision) (domain.ArticleRevision, error) {
+				assert.Equal(t, 3, revision.Number)
+				assert.Equal(t, "first line", revision.Body)
+				assert.Equal(t, int32(1), revision.RestoredFrom.Int32)
+				return revision, nil
+			})
+
+		article, err := s.RestoreRevision(domain.Actor{ID: 1}, "test-slug", 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "first line", article.Body)
+		assert.Equal(t, "test-slug", article.Slug)
+	})
+	t.Run("제목이 다른 리비전 복원시 slug 유지", func(t *testing.T) {
+		ar.EXPECT().
+			FindLatestRevision(gomock.Eq(uint(1))).
+			Return(domain.ArticleRevision{Number: 3}, nil)
+		ar.EXPECT().
+			SaveRevision(gomock.Any()).
+			Return(domain.ArticleRevision{}, nil)
+
+		article, err := s.RestoreRevision(domain.Actor{ID: 1}, "test-slug", 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "old title", article.Title)
+		assert.Equal(t, "test-slug", article.Slug)
+	})
+	t.Run("다른 유저의 리비전 복원", func(t *testing.T) {
+		_, err := s.RestoreRevision(domain.Actor{ID: 2, Role: domain.RoleAdmin}, "test-slug", 1)
+
+		assert.ErrorIs(t, err, ports.ErrNonOwnedContent)
+	})
+	t.Run("없는 리비전 복원", func(t *testing.T) {
+		_, err := s.RestoreRevision(domain.Actor{ID: 1}, "test-slug", 5)
+
+		assert.ErrorIs(t, err, ports.ErrResourceNotFound)
+	})
+	t.Run("리비전 비교", func(t *testing.T) {
+		diff, err := s.DiffRevisions(2, "test-slug", 1, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "--- revisions/1/body\n+++ revisions/2/body\n@@ -1 +1 @@\n-first line\n+second line\n", diff.Unified)
+	})
+}
+
+func Test_articleService_Slug(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ar := mock_ports.NewMockArticleRepository(ctrl)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+
+	ar.EXPECT().
+		FindBySlug(gomock.Eq("test-slug")).
+		Return(domain.Article{
+			Model:  gorm.Model{ID: 1},
+			Slug:   "test-slug",
+			Title:  "title",
+			Author: domain.Author{ID: 1},
+		}, nil).
+		AnyTimes()
+	ar.EXPECT().
+		FindBySlug(gomock.Eq("other-slug")).
+		Return(domain.Article{Model: gorm.Model{ID: 2}, Slug: "other-slug"}, nil).
+		AnyTimes()
+	ar.EXPECT().
+		FindBySlug(gomock.Any()).
+		Return(domain.Article{}, gorm.ErrRecordNotFound).
+		AnyTimes()
+	ar.EXPECT().
+		FindByOldSlug(gomock.Eq("old-slug")).
+		Return(domain.Article{
+			Model:  gorm.Model{ID: 1},
+			Slug:   "test-slug",
+			Author: domain.Author{ID: 1},
+		}, nil).
+		AnyTimes()
+	ar.EXPECT().
+		FindByOldSlug(gomock.Any()).
+		Return(domain.Article{}, gorm.ErrRecordNotFound).
+		AnyTimes()
+	ar.EXPECT().
+		Save(gomock.Any()).
+		DoAndReturn(func(article domain.Article) (domain.Article, error) {
+			// slug is taken by concurrent request after it is checked
+			if article.Slug == "racing-slug" {
+				return domain.Article{}, gorm.ErrDuplicatedKey
+			}
+			return article, nil
+		}).
+		AnyTimes()
+	ar.EXPECT().
+		FindFavorite(gomock.Any(), gomock.Any()).
+		Return(domain.Favorite{}, gorm.ErrRecordNotFound).
+		AnyTimes()
+	ur.EXPECT().
+		FindFollow(gomock.Any(), gomock.Any()).
+		Return(domain.Follow{}, gorm.ErrRecordNotFound).
+		AnyTimes()
+
+	s := NewArticleService(ar, ur, zap.NewNop())
+	t.Run("이전 slug로 글 조회", func(t *testing.T) {
+		article, err := s.Find(2, "old-slug")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "test-slug", article.Slug)
+	})
+	t.Run("slug 변경시 이전 slug 기록", func(t *testing.T) {
+		ar.EXPECT().
+			DeleteSlugHistory(gomock.Eq("new-slug")).
+			Return(nil)
+		ar.EXPECT().
+			SaveSlugHistory(gomock.Eq(domain.SlugHistory{Slug: "test-slug", ArticleID: 1})).
+			Return(domain.SlugHistory{}, nil)
+
+		slug := "new-slug"
+		article, err := s.Update(domain.Actor{ID: 1}, "test-slug", ports.ArticleUpdateFields{Slug: &slug})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "new-slug", article.Slug)
+	})
+	t.Run("제목이 같으면 slug 유지", func(t *testing.T) {
+		title := "title"
+		article, err := s.Update(domain.Actor{ID: 1}, "test-slug", ports.ArticleUpdateFields{Title: &title})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "test-slug", article.Slug)
+	})
+	t.Run("다른 글이 사용하는 slug", func(t *testing.T) {
+		slug := "other-slug"
+		_, err := s.Update(domain.Actor{ID: 1}, "test-slug", ports.ArticleUpdateFields{Slug: &slug})
+
+		assert.ErrorIs(t, err, ports.ErrDuplicatedSlug)
+	})
+	t.Run("다른 글의 이전 slug로 글 작성", func(t *testing.T) {
+		slug := "old-slug"
+		_, err := s.Create(2, "title", "description", "body", nil, domain.StatusPublished, nil, &slug)
+
+		assert.ErrorIs(t, err, ports.ErrDuplicatedSlug)
+	})
+	t.Run("동시에 같은 slug로 글 작성", func(t *testing.T) {
+		ur.EXPECT().
+			FindByID(gomock.Eq(uint(2))).
+			Return(domain.User{Model: gorm.Model{ID: 2}}, nil)
+
+		slug := "racing-slug"
+		_, err := s.Create(2, "title", "description", "body", nil, domain.StatusPublished, nil, &slug)
+
+		assert.ErrorIs(t, err, ports.ErrDuplicatedSlug)
+	})
+	t.Run("동시에 같은 slug로 변경", func(t *testing.T) {
+		slug := "racing-slug"
+		_, err := s.Update(domain.Actor{ID: 1}, "test-slug", ports.ArticleUpdateFields{Slug: &slug})
+
+		assert.ErrorIs(t, err, ports.ErrDuplicatedSlug)
+	})
+	t.Run("잘못된 형식의 slug", func(t *testing.T) {
+		slug := "Not A Slug"
+		_, err := s.Create(2, "title", "description", "body", nil, domain.StatusPublished, nil, &slug)
+
+		assert.ErrorIs(t, err, ports.ErrInvalidSlug)
+	})
+}
+
+func Test_articleService_ListByConditions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ar := mock_ports.NewMockArticleRepository(ctrl)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+	query := "dragons"
+
+	ar.EXPECT().
+		FindBySearchConditions(gomock.Eq(ports.ArticleSearchConditions{Query: &query, ReaderID: 1})).
+		Return([]domain.Article{
+			{Model: gorm.Model{ID: 2}, Author: domain.Author{ID: 3}},
+			{Model: gorm.Model{ID: 1}, Author: domain.Author{ID: 3}},
+		}, ports.Page{Total: 12}, nil)
+	ar.EXPECT().
+		FindFavorites(gomock.Eq(uint(1)), gomock.Eq([]uint{2, 1})).
+		Return(nil, nil)
+	ur.EXPECT().
+		FindFollows(gomock.Eq(uint(1)), gomock.Any()).
+		Return(nil, nil)
+	ar.EXPECT().
+		FindSnippets(gomock.Eq(query), gomock.Eq([]uint{2, 1})).
+		Return(map[uint]string{1: "<mark>dragons</mark>"}, nil)
+
+	s := NewArticleService(ar, ur, zap.NewNop())
+	t.Run("검색 결과에 snippet 포함", func(t *testing.T) {
+		articles, page, err := s.ListByConditions(1, ports.ArticleSearchConditions{Query: &query})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12), page.Total)
+		assert.Equal(t, uint(2), articles[0].ID)
+		assert.Equal(t, "", articles[0].Snippet)
+		assert.Equal(t, "<mark>dragons</mark>", articles[1].Snippet)
+	})
+	t.Run("검색 결과는 cursor로 조회 불가", func(t *testing.T) {
+		_, _, err := s.ListByConditions(1, ports.ArticleSearchConditions{
+			Query:    &query,
+			Pageable: ports.Pageable{Cursor: &ports.Cursor{ID: 1}},
+		})
+
+		assert.ErrorIs(t, err, ports.ErrUnsupportedCursor)
+	})
 }
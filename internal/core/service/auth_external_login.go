@@ -0,0 +1,246 @@
+package service
+
+import (
+	"errors"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/pkg/crypto"
+	"github.com/KumKeeHyun/gin-realworld/pkg/types"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+	"sort"
+	"strings"
+)
+
+const maxUsernameAttempts = 5
+
+func (s authService) ExternalProviders() []string {
+	names := lo.Keys(s.providers)
+	sort.Strings(names)
+	return names
+}
+
+// BeginExternalLogin returns the authorization url of provider user agent should be redirected to.
+func (s authService) BeginExternalLogin(provider string) (string, error) {
+	p, exists := s.providers[provider]
+	if !exists {
+		return "", ports.ErrUnknownProvider
+	}
+
+	state, err := domain.NewLoginState(provider)
+	if err != nil {
+		s.logger.Errorw("failed to generate login state", "err", err)
+		return "", ports.ErrInternal
+	}
+	_, err = s.identityRepo.SaveLoginState(state)
+	if err != nil {
+		s.logger.Errorw("failed to save login state", "err", err)
+		return "", ports.ErrInternal
+	}
+
+	authURL, err := p.AuthCodeURL(state.State, state.Nonce, state.CodeVerifier)
+	if err != nil {
+		s.logger.Errorw("failed to build authorization url", "provider", provider, "err", err)
+		return "", ports.ErrInternal
+	}
+	return authURL, nil
+}
+
+// CompleteExternalLogin redeems the authorization response and issues tokens of the linked user.
+func (s authService) CompleteExternalLogin(provider, state, code string) (domain.User, error) {
+	p, exists := s.providers[provider]
+	if !exists {
+		return domain.User{}, ports.ErrUnknownProvider
+	}
+
+	loginState, err := s.identityRepo.FindLoginState(crypto.HashToken(state))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, ports.ErrInvalidLoginState
+	} else if err != nil {
+		s.logger.Errorw("failed to find login state", "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	if loginState.Provider != provider || !loginState.Usable() {
+		return domain.User{}, ports.ErrInvalidLoginState
+	}
+
+	err = s.identityRepo.UseLoginState(loginState.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, ports.ErrInvalidLoginState
+	} else if err != nil {
+		s.logger.Errorw("failed to use login state", "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+
+	external, err := p.Exchange(code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		s.logger.Infow("failed to exchange authorization code", "provider", provider, "err", err)
+		return domain.User{}, ports.ErrExternalLogin
+	}
+
+	user, err := s.linkIdentity(external)
+	if err != nil {
+		return domain.User{}, err
+	}
+	return s.completeLogin(user)
+}
+
+// linkIdentity finds the user linked to external identity.
+// If not linked yet, it links the user having the same verified email or registers new user.
+// User who has not verified the email yet is claimed by the identity, see claimUnverifiedUser.
+func (s authService) linkIdentity(external domain.ExternalIdentity) (domain.User, error) {
+	identity, err := s.identityRepo.FindIdentity(external.Provider, external.Subject)
+	if err == nil {
+		user, err := s.userRepo.FindByID(identity.UserID)
+		if err != nil {
+			s.logger.Errorw("failed to find user by id", "id", identity.UserID, "err", err)
+			return domain.User{}, ports.ErrInternal
+		}
+		return user, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Errorw("failed to find identity", "provider", external.Provider, "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+
+	if external.Email == "" {
+		s.logger.Infow("identity without email can not be linked", "provider", external.Provider)
+		return domain.User{}, ports.ErrExternalLogin
+	}
+	if domain.ReservedEmail(external.Email) {
+		s.logger.Infow("identity with reserved email can not be linked", "provider", external.Provider)
+		return domain.User{}, ports.ErrExternalLogin
+	}
+
+	user, err := s.userRepo.FindByEmail(external.Email)
+	if err == nil {
+		// unverified email of provider could be anyone's address, so it must not take over the account
+		if !external.EmailVerified {
+			return domain.User{}, ports.ErrDuplicatedEmailOrUsername
+		}
+		if !user.Verified() {
+			if user, err = s.claimUnverifiedUser(user); err != nil {
+				return domain.User{}, err
+			}
+		}
+	} else if errors.Is(err, gorm.ErrRecordNotFound) {
+		if user, err = s.registerExternalUser(external); err != nil {
+			return domain.User{}, err
+		}
+	} else {
+		s.logger.Errorw("failed to find user by email", "email", external.Email, "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+
+	_, err = s.identityRepo.SaveIdentity(domain.Identity{
+		UserID:   user.ID,
+		Provider: external.Provider,
+		Subject:  external.Subject,
+		Email:    external.Email,
+	})
+	if err != nil {
+		s.logger.Errorw("failed to save identity", "id", user.ID, "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	return user, nil
+}
+
+// claimUnverifiedUser hands over the account registered with unverified email to the owner of the email.
+// Anyone could have registered it, so the password and every credential set up before are discarded.
+func (s authService) claimUnverifiedUser(user domain.User) (domain.User, error) {
+	// nobody knows the password, owner can set it through password reset
+	password, err := crypto.GenerateToken()
+	if err != nil {
+		s.logger.Errorw("failed to generate password", "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	user.UpdatePassword(password)
+	user.Verify()
+	saved, err := s.userRepo.Save(user)
+	if err != nil {
+		s.logger.Errorw("failed to save user", "id", user.ID, "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+
+	if err := s.tokenRepo.RevokeRefreshTokens(user.ID); err != nil {
+		s.logger.Errorw("failed to revoke refresh tokens", "id", user.ID, "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	if err := s.tokenRepo.DeletePersonalAccessTokens(user.ID); err != nil {
+		s.logger.Errorw("failed to delete personal access tokens", "id", user.ID, "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	// identities linked with unverified email of other providers are not trusted either
+	if err := s.identityRepo.DeleteIdentities(user.ID); err != nil {
+		s.logger.Errorw("failed to delete identities", "id", user.ID, "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	if err := s.mfaRepo.DeleteTwoFactor(user.ID); err != nil {
+		s.logger.Errorw("failed to delete two factor", "id", user.ID, "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	s.logger.Warnw("unverified account is claimed by verified identity", "id", user.ID)
+	return saved, nil
+}
+
+func (s authService) registerExternalUser(external domain.ExternalIdentity) (domain.User, error) {
+	username, err := s.availableUsername(external)
+	if err != nil {
+		return domain.User{}, err
+	}
+	// nobody knows the password, user can set it through password reset
+	password, err := crypto.GenerateToken()
+	if err != nil {
+		s.logger.Errorw("failed to generate password", "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+
+	user := domain.User{
+		Email:    external.Email,
+		Username: username,
+		Password: types.Password{String: password},
+	}
+	if external.EmailVerified {
+		user.Verify()
+	}
+	saved, err := s.userRepo.Save(user)
+	if err != nil {
+		s.logger.Errorw("failed to save user", "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+
+	if !saved.Verified() {
+		if err := s.sendVerification(saved); err != nil {
+			s.logger.Errorw("failed to send verification mail", "id", saved.ID, "err", err)
+		}
+	}
+	return saved, nil
+}
+
+func (s authService) availableUsername(external domain.ExternalIdentity) (string, error) {
+	base := strings.TrimSpace(external.PreferredUsername)
+	if base == "" {
+		base, _, _ = strings.Cut(external.Email, "@")
+	}
+	if domain.ReservedUsername(base) {
+		base = "user-" + base
+	}
+
+	username := base
+	for i := 0; i < maxUsernameAttempts; i++ {
+		_, err := s.userRepo.FindByUsername(username)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return username, nil
+		} else if err != nil {
+			s.logger.Errorw("failed to find user by username", "username", username, "err", err)
+			return "", ports.ErrInternal
+		}
+
+		suffix, err := crypto.GenerateToken()
+		if err != nil {
+			s.logger.Errorw("failed to generate username suffix", "err", err)
+			return "", ports.ErrInternal
+		}
+		username = base + "-" + suffix[:6]
+	}
+	return "", ports.ErrDuplicatedEmailOrUsername
+}
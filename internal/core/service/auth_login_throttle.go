@@ -0,0 +1,77 @@
+package service
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/pkg/crypto"
+	"sync"
+	"time"
+)
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+// dummyPasswordHash is compared against when email is unknown, it is generated once on first use.
+func dummyPasswordHash() string {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = crypto.HashPassword("dummy-password")
+	})
+	return dummyHash
+}
+
+// checkLoginThrottles rejects login while account or client address is locked.
+// Stale failures are forgotten here, so that rare typos do not add up to a lockout.
+func (s authService) checkLoginThrottles(keys []string) error {
+	throttles, err := s.throttleRepo.FindLoginThrottles(keys)
+	if err != nil {
+		s.logger.Errorw("failed to find login throttles", "err", err)
+		return ports.ErrInternal
+	}
+
+	now := time.Now()
+	for _, throttle := range throttles {
+		if throttle.Locked(now) {
+			return ports.ErrTooManyLoginAttempts
+		}
+		if throttle.Stale(now) {
+			if err := s.throttleRepo.DeleteLoginThrottle(throttle.Key); err != nil {
+				s.logger.Errorw("failed to reset login throttle", "key", throttle.Key, "err", err)
+				return ports.ErrInternal
+			}
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure counts failure of each key and locks the keys over threshold.
+// It only logs errors, because the login has failed anyway.
+func (s authService) recordLoginFailure(keys []string) {
+	now := time.Now()
+	for _, key := range keys {
+		throttle, err := s.throttleRepo.RecordLoginFailure(key, now)
+		if err != nil {
+			s.logger.Errorw("failed to record login failure", "key", key, "err", err)
+			continue
+		}
+
+		backoff := throttle.Backoff(domain.LoginThreshold(key))
+		if backoff == 0 {
+			continue
+		}
+		if err := s.throttleRepo.LockLogin(key, now.Add(backoff)); err != nil {
+			s.logger.Errorw("failed to lock login", "key", key, "err", err)
+			continue
+		}
+		s.logger.Infow("login is locked", "key", key, "failures", throttle.Failures, "backoff", backoff)
+	}
+}
+
+// resetLoginThrottle forgets failures of account once the whole login succeeded.
+// Failures of address are kept, otherwise attacker could reset them with own account.
+func (s authService) resetLoginThrottle(accountKey string, userID uint) {
+	if err := s.throttleRepo.DeleteLoginThrottle(accountKey); err != nil {
+		s.logger.Errorw("failed to reset login throttle", "id", userID, "err", err)
+	}
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"errors"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/pkg/crypto"
+	"github.com/KumKeeHyun/gin-realworld/pkg/totp"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// completeLogin issues tokens, or mfa challenge instead if user enabled two factor authentication.
+func (s authService) completeLogin(user domain.User) (domain.User, error) {
+	twoFactor, err := s.mfaRepo.FindTwoFactor(user.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !twoFactor.Enabled()) {
+		return s.issueTokens(user)
+	} else if err != nil {
+		s.logger.Errorw("failed to find two factor", "id", user.ID, "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+
+	challenge, err := domain.NewOneTimeToken(user.ID, domain.PurposeMFAChallenge, domain.MFAChallengeTTL)
+	if err != nil {
+		s.logger.Errorw("failed to generate mfa challenge", "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	_, err = s.tokenRepo.SaveOneTimeToken(challenge)
+	if err != nil {
+		s.logger.Errorw("failed to save mfa challenge", "id", user.ID, "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	return domain.User{MFAChallenge: challenge.Token}, nil
+}
+
+// LoginMFA exchanges challenge and code for tokens.
+// Challenge is used up even if code is wrong, so that codes can not be guessed with one password check.
+// Wrong codes are counted against the account as failed logins, otherwise codes could be guessed
+// by starting a new challenge after each miss.
+func (s authService) LoginMFA(challenge, code string) (domain.User, error) {
+	token, err := s.tokenRepo.FindOneTimeToken(domain.PurposeMFAChallenge, crypto.HashToken(challenge))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, ports.ErrInvalidMFAChallenge
+	} else if err != nil {
+		s.logger.Errorw("failed to find mfa challenge", "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	if !token.Usable() {
+		return domain.User{}, ports.ErrInvalidMFAChallenge
+	}
+
+	user, err := s.userRepo.FindByID(token.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, ports.ErrInvalidMFAChallenge
+	} else if err != nil {
+		s.logger.Errorw("failed to find user by id", "id", token.UserID, "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	keys := []string{domain.AccountLoginKey(user.Email)}
+	if err := s.checkLoginThrottles(keys); err != nil {
+		return domain.User{}, err
+	}
+
+	err = s.tokenRepo.UseOneTimeToken(token.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, ports.ErrInvalidMFAChallenge
+	} else if err != nil {
+		s.logger.Errorw("failed to use mfa challenge", "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+
+	twoFactor, err := s.enabledTwoFactor(token.UserID)
+	if errors.Is(err, ports.ErrMFANotEnabled) {
+		return domain.User{}, ports.ErrInvalidMFAChallenge
+	} else if err != nil {
+		return domain.User{}, err
+	}
+	if err := s.verifySecondFactor(twoFactor, code); err != nil {
+		if errors.Is(err, ports.ErrInvalidMFACode) {
+			s.recordLoginFailure(keys)
+		}
+		return domain.User{}, err
+	}
+
+	s.resetLoginThrottle(keys[0], user.ID)
+	return s.issueTokens(user)
+}
+
+// EnrollTOTP generates new secret. It takes effect after ConfirmTOTP.
+func (s authService) EnrollTOTP(userID uint) (domain.TOTPEnrollment, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.TOTPEnrollment{}, ports.ErrResourceNotFound
+	} else if err != nil {
+		s.logger.Errorw("failed to find user by id", "id", userID, "err", err)
+		return domain.TOTPEnrollment{}, ports.ErrInternal
+	}
+
+	twoFactor, err := s.mfaRepo.FindTwoFactor(userID)
+	if err == nil && twoFactor.Enabled() {
+		return domain.TOTPEnrollment{}, ports.ErrMFAAlreadyEnabled
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Errorw("failed to find two factor", "id", userID, "err", err)
+		return domain.TOTPEnrollment{}, ports.ErrInternal
+	}
+
+	// unconfirmed enrollment is replaced by new secret
+	enrolled, err := domain.NewTwoFactor(userID)
+	if err != nil {
+		s.logger.Errorw("failed to generate totp secret", "err", err)
+		return domain.TOTPEnrollment{}, ports.ErrInternal
+	}
+	enrolled.ID = twoFactor.ID
+	enrolled, err = s.mfaRepo.SaveTwoFactor(enrolled)
+	if err != nil {
+		s.logger.Errorw("failed to save two factor", "id", userID, "err", err)
+		return domain.TOTPEnrollment{}, ports.ErrInternal
+	}
+	return domain.TOTPEnrollment{
+		Secret: enrolled.Secret,
+		URI:    enrolled.URI(user.Email),
+	}, nil
+}
+
+// ConfirmTOTP enables two factor authentication and returns recovery codes.
+func (s authService) ConfirmTOTP(userID uint, code string) ([]string, error) {
+	twoFactor, err := s.mfaRepo.FindTwoFactor(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ports.ErrMFANotEnabled
+	} else if err != nil {
+		s.logger.Errorw("failed to find two factor", "id", userID, "err", err)
+		return nil, ports.ErrInternal
+	}
+	if twoFactor.Enabled() {
+		return nil, ports.ErrMFAAlreadyEnabled
+	}
+
+	step, ok := twoFactor.Validate(strings.TrimSpace(code))
+	if !ok {
+		return nil, ports.ErrInvalidMFACode
+	}
+	twoFactor.Enable()
+	twoFactor.LastUsedStep = step
+	_, err = s.mfaRepo.SaveTwoFactor(twoFactor)
+	if err != nil {
+		s.logger.Errorw("failed to save two factor", "id", userID, "err", err)
+		return nil, ports.ErrInternal
+	}
+	return s.replaceRecoveryCodes(userID)
+}
+
+func (s authService) DisableTOTP(userID uint, code string) error {
+	twoFactor, err := s.enabledTwoFactor(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.verifySecondFactor(twoFactor, code); err != nil {
+		return err
+	}
+
+	err = s.mfaRepo.DeleteTwoFactor(userID)
+	if err != nil {
+		s.logger.Errorw("failed to delete two factor", "id", userID, "err", err)
+		return ports.ErrInternal
+	}
+	return nil
+}
+
+func (s authService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	twoFactor, err := s.enabledTwoFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifySecondFactor(twoFactor, code); err != nil {
+		return nil, err
+	}
+	return s.replaceRecoveryCodes(userID)
+}
+
+func (s authService) enabledTwoFactor(userID uint) (domain.TwoFactor, error) {
+	twoFactor, err := s.mfaRepo.FindTwoFactor(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.TwoFactor{}, ports.ErrMFANotEnabled
+	} else if err != nil {
+		s.logger.Errorw("failed to find two factor", "id", userID, "err", err)
+		return domain.TwoFactor{}, ports.ErrInternal
+	}
+	if !twoFactor.Enabled() {
+		return domain.TwoFactor{}, ports.ErrMFANotEnabled
+	}
+	return twoFactor, nil
+}
+
+// verifySecondFactor accepts either TOTP code or recovery code, each of them only once.
+func (s authService) verifySecondFactor(twoFactor domain.TwoFactor, code string) error {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		step, ok := twoFactor.Validate(code)
+		if !ok {
+			return ports.ErrInvalidMFACode
+		}
+		err := s.mfaRepo.UseTwoFactorStep(twoFactor.ID, step)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ports.ErrInvalidMFACode
+		} else if err != nil {
+			s.logger.Errorw("failed to use totp step", "id", twoFactor.UserID, "err", err)
+			return ports.ErrInternal
+		}
+		return nil
+	}
+
+	err := s.mfaRepo.UseRecoveryCode(twoFactor.UserID, domain.HashRecoveryCode(code))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ports.ErrInvalidMFACode
+	} else if err != nil {
+		s.logger.Errorw("failed to use recovery code", "id", twoFactor.UserID, "err", err)
+		return ports.ErrInternal
+	}
+	s.logger.Infow("recovery code is used", "id", twoFactor.UserID)
+	return nil
+}
+
+func (s authService) replaceRecoveryCodes(userID uint) ([]string, error) {
+	codes, recoveryCodes, err := domain.NewRecoveryCodes(userID)
+	if err != nil {
+		s.logger.Errorw("failed to generate recovery codes", "err", err)
+		return nil, ports.ErrInternal
+	}
+	err = s.mfaRepo.ReplaceRecoveryCodes(userID, recoveryCodes)
+	if err != nil {
+		s.logger.Errorw("failed to save recovery codes", "id", userID, "err", err)
+		return nil, ports.ErrInternal
+	}
+	return codes, nil
+}
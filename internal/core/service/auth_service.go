@@ -5,39 +5,69 @@ import (
 	"errors"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/pkg/crypto"
 	"github.com/KumKeeHyun/gin-realworld/pkg/jwtutil"
 	"github.com/KumKeeHyun/gin-realworld/pkg/types"
+	"github.com/samber/lo"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"time"
 )
 
 type authService struct {
-	userRepo    ports.UserRepository
-	articleRepo ports.ArticleRepository
-	jwtUtil     *jwtutil.JwtUtil
-	logger      *zap.SugaredLogger
+	userRepo     ports.UserRepository
+	authorRepo   ports.AuthorRepository
+	tokenRepo    ports.TokenRepository
+	identityRepo ports.IdentityRepository
+	mfaRepo      ports.MFARepository
+	throttleRepo ports.LoginThrottleRepository
+	mailer       ports.Mailer
+	providers    map[string]ports.IdentityProvider
+	jwtUtil      *jwtutil.JwtUtil
+	logger       *zap.SugaredLogger
 }
 
 func NewAuthService(
 	userRepo ports.UserRepository,
-	articleRepo ports.ArticleRepository,
+	authorRepo ports.AuthorRepository,
+	tokenRepo ports.TokenRepository,
+	identityRepo ports.IdentityRepository,
+	mfaRepo ports.MFARepository,
+	throttleRepo ports.LoginThrottleRepository,
+	mailer ports.Mailer,
+	providers []ports.IdentityProvider,
 	jwtUtil *jwtutil.JwtUtil,
 	logger *zap.Logger) ports.AuthService {
 	return authService{
-		userRepo:    userRepo,
-		articleRepo: articleRepo,
-		jwtUtil:     jwtUtil,
-		logger:      logger.Sugar().Named("authService"),
+		userRepo:     userRepo,
+		authorRepo:   authorRepo,
+		tokenRepo:    tokenRepo,
+		identityRepo: identityRepo,
+		mfaRepo:      mfaRepo,
+		throttleRepo: throttleRepo,
+		mailer:       mailer,
+		providers: lo.SliceToMap(providers, func(p ports.IdentityProvider) (string, ports.IdentityProvider) {
+			return p.Name(), p
+		}),
+		jwtUtil: jwtUtil,
+		logger:  logger.Sugar().Named("authService"),
 	}
 }
 
 func (s authService) WithTx(tx *gorm.DB) ports.AuthService {
 	s.userRepo = s.userRepo.WithTx(tx)
-	s.articleRepo = s.articleRepo.WithTx(tx)
+	s.authorRepo = s.authorRepo.WithTx(tx)
+	s.tokenRepo = s.tokenRepo.WithTx(tx)
+	s.identityRepo = s.identityRepo.WithTx(tx)
+	s.mfaRepo = s.mfaRepo.WithTx(tx)
+	s.throttleRepo = s.throttleRepo.WithTx(tx)
 	return s
 }
 
 func (s authService) Register(email, username, password string) (domain.User, error) {
+	if domain.ReservedEmail(email) || domain.ReservedUsername(username) {
+		return domain.User{}, ports.ErrReservedEmailOrUsername
+	}
 	_, err := s.userRepo.FindByEmailOrUsername(email, username)
 	if err == nil {
 		s.logger.Infow("failed to register user due to duplicated identifier", "email", email, "username", username)
@@ -57,35 +87,312 @@ func (s authService) Register(email, username, password string) (domain.User, er
 		return domain.User{}, ports.ErrInternal
 	}
 
-	saved.Token, err = s.jwtUtil.SignClaims(saved.AccessClaim())
+	// user can ask to resend verification mail, so registration does not fail here
+	if err := s.sendVerification(saved); err != nil {
+		s.logger.Errorw("failed to send verification mail", "id", saved.ID, "err", err)
+	}
+	return s.issueTokens(saved)
+}
+
+func (s authService) sendVerification(user domain.User) error {
+	token, err := domain.NewOneTimeToken(user.ID, domain.PurposeEmailVerification, domain.EmailVerificationTokenTTL)
 	if err != nil {
-		s.logger.Errorw("failed to generate jwt token", "err", err)
-		return domain.User{}, ports.ErrInternal
+		return err
 	}
-	return saved, nil
+	_, err = s.tokenRepo.SaveOneTimeToken(token)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ports.Mail{
+		To:      user.Email,
+		Subject: "Verify your Conduit email",
+		Body: "Use the token below to verify your email. It expires in 24 hours.\n\n" +
+			token.Token + "\n\nIf you did not sign up for Conduit, ignore this mail.",
+	})
 }
 
-func (s authService) Login(email, password string) (domain.User, error) {
+// Login returns the same error for unknown email and wrong password, so that it does not reveal registered emails.
+func (s authService) Login(email, password, clientIP string) (domain.User, error) {
+	keys := []string{domain.AccountLoginKey(email)}
+	if clientIP != "" {
+		keys = append(keys, domain.IPLoginKey(clientIP))
+	}
+	if err := s.checkLoginThrottles(keys); err != nil {
+		return domain.User{}, err
+	}
+
 	user, err := s.userRepo.FindByEmail(email)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return domain.User{}, ports.ErrResourceNotFound
+		// spend the same time as checking password of existing user
+		crypto.CheckHashAndPassword(dummyPasswordHash(), password)
+		s.recordLoginFailure(keys)
+		return domain.User{}, ports.ErrInvalidCredentials
 	} else if err != nil {
 		s.logger.Errorw("failed to find user by email", "email", email, "err", err)
 		return domain.User{}, ports.ErrInternal
 	}
 
 	if !user.ValidPassword(password) {
-		return domain.User{}, ports.ErrInvalidPassword
+		s.recordLoginFailure(keys)
+		return domain.User{}, ports.ErrInvalidCredentials
 	}
 
+	if user.PasswordOutdated() {
+		user = s.rehashPassword(user, password)
+	}
+	completed, err := s.completeLogin(user)
+	if err != nil {
+		return domain.User{}, err
+	}
+	// account stays throttled until second factor is verified in LoginMFA
+	if completed.MFAChallenge == "" {
+		s.resetLoginThrottle(keys[0], user.ID)
+	}
+	return completed, nil
+}
+
+// rehashPassword upgrades hash while plain password is known. Login goes on even if it fails.
+func (s authService) rehashPassword(user domain.User, password string) domain.User {
+	user.UpdatePassword(password)
+	saved, err := s.userRepo.Save(user)
+	if err != nil {
+		s.logger.Errorw("failed to rehash password", "id", user.ID, "err", err)
+		return user
+	}
+	s.logger.Infow("password is rehashed", "id", user.ID)
+	return saved
+}
+
+func (s authService) issueTokens(user domain.User) (domain.User, error) {
+	var err error
 	user.Token, err = s.jwtUtil.SignClaims(user.AccessClaim())
 	if err != nil {
 		s.logger.Errorw("failed to generate jwt token", "err", err)
 		return domain.User{}, ports.ErrInternal
 	}
+
+	refreshToken, err := domain.NewRefreshToken(user.ID)
+	if err != nil {
+		s.logger.Errorw("failed to generate refresh token", "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	_, err = s.tokenRepo.SaveRefreshToken(refreshToken)
+	if err != nil {
+		s.logger.Errorw("failed to save refresh token", "id", user.ID, "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	user.RefreshToken = refreshToken.Token
 	return user, nil
 }
 
+func (s authService) Refresh(refreshToken string) (domain.User, error) {
+	token, err := s.tokenRepo.FindRefreshToken(crypto.HashToken(refreshToken))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, ports.ErrInvalidRefreshToken
+	} else if err != nil {
+		s.logger.Errorw("failed to find refresh token", "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+
+	if token.Revoked() {
+		// reuse of rotated token means it may be stolen, so revoke every session of the user
+		s.logger.Warnw("reuse of revoked refresh token", "user-id", token.UserID)
+		if err := s.tokenRepo.RevokeRefreshTokens(token.UserID); err != nil {
+			s.logger.Errorw("failed to revoke refresh tokens", "user-id", token.UserID, "err", err)
+		}
+		return domain.User{}, ports.ErrInvalidRefreshToken
+	}
+	if token.Expired() {
+		return domain.User{}, ports.ErrInvalidRefreshToken
+	}
+
+	err = s.tokenRepo.RevokeRefreshToken(token.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, ports.ErrInvalidRefreshToken
+	} else if err != nil {
+		s.logger.Errorw("failed to revoke refresh token", "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+
+	user, err := s.userRepo.FindByID(token.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, ports.ErrInvalidRefreshToken
+	} else if err != nil {
+		s.logger.Errorw("failed to find user by id", "id", token.UserID, "err", err)
+		return domain.User{}, ports.ErrInternal
+	}
+	return s.issueTokens(user)
+}
+
+func (s authService) Logout(claim domain.AccessClaim) error {
+	err := s.tokenRepo.RevokeRefreshTokens(claim.UID)
+	if err != nil {
+		s.logger.Errorw("failed to revoke refresh tokens", "user-id", claim.UID, "err", err)
+		return ports.ErrInternal
+	}
+
+	if claim.ID == "" || claim.ExpiresAt == nil {
+		return nil
+	}
+	_, err = s.tokenRepo.CreateDeniedToken(claim.ID, claim.ExpiresAt.Time)
+	if err != nil {
+		s.logger.Errorw("failed to deny access token", "user-id", claim.UID, "err", err)
+		return ports.ErrInternal
+	}
+	return nil
+}
+
+// RequestPasswordReset does not tell whether email exists, so unknown email is not an error.
+// PurgeDeniedTokens deletes denied access tokens which expired by now. It is run by scheduler in a transaction.
+func (s authService) PurgeDeniedTokens(now time.Time) (int64, error) {
+	purged, err := s.tokenRepo.DeleteExpiredDeniedTokens(now)
+	if err != nil {
+		s.logger.Errorw("failed to delete expired denied tokens", "err", err)
+		return 0, ports.ErrInternal
+	}
+	if purged > 0 {
+		s.logger.Infow("expired denied tokens are purged", "count", purged)
+	}
+	return purged, nil
+}
+
+func (s authService) RequestPasswordReset(email string) error {
+	user, err := s.userRepo.FindByEmail(email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Infow("password reset requested for unknown email", "email", email)
+		return nil
+	} else if err != nil {
+		s.logger.Errorw("failed to find user by email", "email", email, "err", err)
+		return ports.ErrInternal
+	}
+
+	token, err := domain.NewOneTimeToken(user.ID, domain.PurposePasswordReset, domain.PasswordResetTokenTTL)
+	if err != nil {
+		s.logger.Errorw("failed to generate reset token", "err", err)
+		return ports.ErrInternal
+	}
+	_, err = s.tokenRepo.SaveOneTimeToken(token)
+	if err != nil {
+		s.logger.Errorw("failed to save reset token", "id", user.ID, "err", err)
+		return ports.ErrInternal
+	}
+
+	err = s.mailer.Send(ports.Mail{
+		To:      user.Email,
+		Subject: "Reset your Conduit password",
+		Body: "Use the token below to reset your password. It expires in one hour.\n\n" +
+			token.Token + "\n\nIf you did not request a password reset, ignore this mail.",
+	})
+	if err != nil {
+		s.logger.Errorw("failed to send reset mail", "id", user.ID, "err", err)
+		return ports.ErrInternal
+	}
+	return nil
+}
+
+func (s authService) ResetPassword(token, password string) error {
+	resetToken, err := s.tokenRepo.FindOneTimeToken(domain.PurposePasswordReset, crypto.HashToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ports.ErrInvalidResetToken
+	} else if err != nil {
+		s.logger.Errorw("failed to find reset token", "err", err)
+		return ports.ErrInternal
+	}
+	if !resetToken.Usable() {
+		return ports.ErrInvalidResetToken
+	}
+
+	err = s.tokenRepo.UseOneTimeToken(resetToken.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ports.ErrInvalidResetToken
+	} else if err != nil {
+		s.logger.Errorw("failed to use reset token", "err", err)
+		return ports.ErrInternal
+	}
+
+	user, err := s.userRepo.FindByID(resetToken.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ports.ErrInvalidResetToken
+	} else if err != nil {
+		s.logger.Errorw("failed to find user by id", "id", resetToken.UserID, "err", err)
+		return ports.ErrInternal
+	}
+
+	user.UpdatePassword(password)
+	_, err = s.userRepo.Save(user)
+	if err != nil {
+		s.logger.Errorw("failed to save user", "id", user.ID, "err", err)
+		return ports.ErrInternal
+	}
+
+	// sessions opened with old password should not survive the reset
+	err = s.tokenRepo.RevokeRefreshTokens(user.ID)
+	if err != nil {
+		s.logger.Errorw("failed to revoke refresh tokens", "id", user.ID, "err", err)
+		return ports.ErrInternal
+	}
+	return nil
+}
+
+func (s authService) Verify(token string) error {
+	verificationToken, err := s.tokenRepo.FindOneTimeToken(domain.PurposeEmailVerification, crypto.HashToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ports.ErrInvalidVerificationToken
+	} else if err != nil {
+		s.logger.Errorw("failed to find verification token", "err", err)
+		return ports.ErrInternal
+	}
+	if !verificationToken.Usable() {
+		return ports.ErrInvalidVerificationToken
+	}
+
+	err = s.tokenRepo.UseOneTimeToken(verificationToken.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ports.ErrInvalidVerificationToken
+	} else if err != nil {
+		s.logger.Errorw("failed to use verification token", "err", err)
+		return ports.ErrInternal
+	}
+
+	user, err := s.userRepo.FindByID(verificationToken.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ports.ErrInvalidVerificationToken
+	} else if err != nil {
+		s.logger.Errorw("failed to find user by id", "id", verificationToken.UserID, "err", err)
+		return ports.ErrInternal
+	}
+	if user.Verified() {
+		return nil
+	}
+
+	user.Verify()
+	_, err = s.userRepo.Save(user)
+	if err != nil {
+		s.logger.Errorw("failed to save user", "id", user.ID, "err", err)
+		return ports.ErrInternal
+	}
+	return nil
+}
+
+func (s authService) ResendVerification(userID uint) error {
+	user, err := s.userRepo.FindByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ports.ErrResourceNotFound
+	} else if err != nil {
+		s.logger.Errorw("failed to find user by id", "id", userID, "err", err)
+		return ports.ErrInternal
+	}
+	if user.Verified() {
+		return nil
+	}
+
+	if err := s.sendVerification(user); err != nil {
+		s.logger.Errorw("failed to send verification mail", "id", userID, "err", err)
+		return ports.ErrInternal
+	}
+	return nil
+}
+
 func (s authService) Update(userID uint, fields ports.UserUpdateFields) (domain.User, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -94,6 +401,11 @@ func (s authService) Update(userID uint, fields ports.UserUpdateFields) (domain.
 		s.logger.Errorw("failed to find user by id", "id", userID, "err", err)
 		return domain.User{}, ports.ErrInternal
 	}
+	// user keeps identifier taken before it was reserved
+	if fields.Email != nil && *fields.Email != user.Email && domain.ReservedEmail(*fields.Email) ||
+		fields.Username != nil && *fields.Username != user.Username && domain.ReservedUsername(*fields.Username) {
+		return domain.User{}, ports.ErrReservedEmailOrUsername
+	}
 
 	updatedUser := updateUserFields(user, fields)
 	saved, err := s.userRepo.Save(updatedUser)
@@ -102,7 +414,14 @@ func (s authService) Update(userID uint, fields ports.UserUpdateFields) (domain.
 		return domain.User{}, ports.ErrInternal
 	}
 
-	err = s.articleRepo.UpdateAuthorInfo(saved)
+	if user.Verified() && !saved.Verified() {
+		if err := s.sendVerification(saved); err != nil {
+			s.logger.Errorw("failed to send verification mail", "id", userID, "err", err)
+		}
+	}
+
+	// author copied into articles and comments are updated in the same transaction with user
+	err = s.authorRepo.UpdateAuthorInfo(saved)
 	if err != nil {
 		s.logger.Errorw("failed to update author info", "id", userID, "err", err)
 		return domain.User{}, ports.ErrInternal
@@ -110,9 +429,37 @@ func (s authService) Update(userID uint, fields ports.UserUpdateFields) (domain.
 	return saved, nil
 }
 
+// CheckAuthors returns ids of users whose author copies are drifted from them.
+func (s authService) CheckAuthors() ([]uint, error) {
+	ids, err := s.authorRepo.FindDriftedAuthorIDs()
+	if err != nil {
+		s.logger.Errorw("failed to find drifted authors", "err", err)
+		return nil, ports.ErrInternal
+	}
+	return ids, nil
+}
+
+// RepairAuthors copies users to their drifted author copies, and returns ids of repaired users.
+func (s authService) RepairAuthors() ([]uint, error) {
+	ids, err := s.CheckAuthors()
+	if err != nil || len(ids) == 0 {
+		return ids, err
+	}
+
+	repaired, err := s.authorRepo.RepairAuthorInfo(ids)
+	if err != nil {
+		s.logger.Errorw("failed to repair author info", "ids", ids, "err", err)
+		return nil, ports.ErrInternal
+	}
+	s.logger.Warnw("repaired drifted authors", "ids", ids, "rows", repaired)
+	return ids, nil
+}
+
 func updateUserFields(user domain.User, fields ports.UserUpdateFields) domain.User {
-	if fields.Email != nil {
+	if fields.Email != nil && *fields.Email != user.Email {
 		user.Email = *fields.Email
+		// new address should be verified again
+		user.VerifiedAt = sql.NullTime{}
 	}
 	if fields.Username != nil {
 		user.Username = *fields.Username
@@ -124,7 +471,7 @@ func updateUserFields(user domain.User, fields ports.UserUpdateFields) domain.Us
 		user.Bio = *fields.Bio
 	}
 	if fields.Image != nil {
-		user.Image = sql.NullString{String: *fields.Email, Valid: true}
+		user.Image = sql.NullString{String: *fields.Image, Valid: true}
 	}
 	return user
 }
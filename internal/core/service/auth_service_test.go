@@ -1,24 +1,30 @@
 package service
 
 import (
+	"database/sql"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/ports/mock_ports"
 	"github.com/KumKeeHyun/gin-realworld/pkg/crypto"
 	"github.com/KumKeeHyun/gin-realworld/pkg/jwtutil"
+	"github.com/KumKeeHyun/gin-realworld/pkg/totp"
 	"github.com/KumKeeHyun/gin-realworld/pkg/types"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 	"testing"
+	"time"
 )
 
 func Test_authService_Register(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	ur := mock_ports.NewMockUserRepository(ctrl)
-	ar := mock_ports.NewMockArticleRepository(ctrl)
+	aur := mock_ports.NewMockAuthorRepository(ctrl)
+	tr := mock_ports.NewMockTokenRepository(ctrl)
+	m := mock_ports.NewMockMailer(ctrl)
 
 	ur.EXPECT().
 		FindByEmailOrUsername(gomock.Eq("test@example.com"), gomock.Eq("test")).
@@ -33,8 +39,20 @@ func Test_authService_Register(t *testing.T) {
 			Username: "test",
 			Password: types.Password{Encrypted: true},
 		}, nil)
+	tr.EXPECT().
+		SaveRefreshToken(gomock.Any()).
+		Return(domain.RefreshToken{}, nil)
+	tr.EXPECT().
+		SaveOneTimeToken(gomock.Any()).
+		DoAndReturn(func(token domain.OneTimeToken) (domain.OneTimeToken, error) {
+			assert.Equal(t, domain.PurposeEmailVerification, token.Purpose)
+			return token, nil
+		})
+	m.EXPECT().
+		Send(gomock.Any()).
+		Return(nil)
 
-	s := NewAuthService(ur, ar, jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), zap.NewNop())
+	s := NewAuthService(ur, aur, tr, mock_ports.NewMockIdentityRepository(ctrl), mock_ports.NewMockMFARepository(ctrl), mock_ports.NewMockLoginThrottleRepository(ctrl), m, nil, jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), zap.NewNop())
 	t.Run("회원가입 성공", func(t *testing.T) {
 		user, err := s.Register("test@example.com", "test", "test-password")
 
@@ -42,44 +60,864 @@ func Test_authService_Register(t *testing.T) {
 		assert.Equal(t, "test@example.com", user.Email)
 		assert.Equal(t, "test", user.Username)
 		assert.NotEqual(t, "", user.Token)
+		assert.NotEqual(t, "", user.RefreshToken)
+		assert.False(t, user.Verified())
 	})
 	t.Run("이메일 or 이름 중복", func(t *testing.T) {
 		_, err := s.Register("dup@example.com", "dup", "test-password")
 
 		assert.ErrorIs(t, err, ports.ErrDuplicatedEmailOrUsername)
 	})
+	t.Run("탈퇴한 사용자용으로 예약된 이메일 or 이름", func(t *testing.T) {
+		_, err := s.Register("new@example.com", "deleted-2", "test-password")
+		assert.ErrorIs(t, err, ports.ErrReservedEmailOrUsername)
+
+		_, err = s.Register("deleted-2@deleted.invalid", "new", "test-password")
+		assert.ErrorIs(t, err, ports.ErrReservedEmailOrUsername)
+	})
 }
 
 func Test_authService_Login(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	ur := mock_ports.NewMockUserRepository(ctrl)
-	ar := mock_ports.NewMockArticleRepository(ctrl)
+	aur := mock_ports.NewMockAuthorRepository(ctrl)
+	tr := mock_ports.NewMockTokenRepository(ctrl)
+	mr := mock_ports.NewMockMFARepository(ctrl)
+	lr := mock_ports.NewMockLoginThrottleRepository(ctrl)
+	m := mock_ports.NewMockMailer(ctrl)
 
 	hashPassword, _ := crypto.HashPassword("test-password")
 	ur.EXPECT().
 		FindByEmail(gomock.Eq("test@example.com")).
 		Return(domain.User{
+			Model:    gorm.Model{ID: 1},
 			Password: types.Password{String: hashPassword, Encrypted: true},
 		}, nil).
 		AnyTimes()
+	ur.EXPECT().
+		FindByEmail(gomock.Eq("mfa@example.com")).
+		Return(domain.User{
+			Model:    gorm.Model{ID: 2},
+			Password: types.Password{String: hashPassword, Encrypted: true},
+		}, nil)
 	ur.EXPECT().
 		FindByEmail(gomock.Eq("null@example.com")).
 		Return(domain.User{}, gorm.ErrRecordNotFound)
+	mr.EXPECT().
+		FindTwoFactor(gomock.Eq(uint(1))).
+		Return(domain.TwoFactor{}, gorm.ErrRecordNotFound)
+	mr.EXPECT().
+		FindTwoFactor(gomock.Eq(uint(2))).
+		Return(domain.TwoFactor{UserID: 2, EnabledAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil)
+	tr.EXPECT().
+		SaveRefreshToken(gomock.Any()).
+		Return(domain.RefreshToken{}, nil)
+	lr.EXPECT().
+		FindLoginThrottles(gomock.Any()).
+		DoAndReturn(func(keys []string) ([]domain.LoginThrottle, error) {
+			if keys[0] == domain.AccountLoginKey("locked@example.com") {
+				return []domain.LoginThrottle{{Key: keys[0], Failures: 6, LockedUntil: time.Now().Add(time.Minute)}}, nil
+			}
+			return nil, nil
+		}).
+		AnyTimes()
+	lr.EXPECT().
+		DeleteLoginThrottle(gomock.Any()).
+		DoAndReturn(func(key string) error {
+			// failures are kept until second factor is verified
+			assert.NotEqual(t, domain.AccountLoginKey("mfa@example.com"), key)
+			return nil
+		}).
+		AnyTimes()
+	tr.EXPECT().
+		SaveOneTimeToken(gomock.Any()).
+		DoAndReturn(func(token domain.OneTimeToken) (domain.OneTimeToken, error) {
+			assert.Equal(t, domain.PurposeMFAChallenge, token.Purpose)
+			assert.Equal(t, uint(2), token.UserID)
+			return token, nil
+		})
 
-	s := NewAuthService(ur, ar, jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), zap.NewNop())
+	s := NewAuthService(ur, aur, tr, mock_ports.NewMockIdentityRepository(ctrl), mr, lr, m, nil, jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), zap.NewNop())
 	t.Run("로그인 성공", func(t *testing.T) {
-		_, err := s.Login("test@example.com", "test-password")
+		user, err := s.Login("test@example.com", "test-password", "127.0.0.1")
 
 		assert.NoError(t, err)
+		assert.NotEqual(t, "", user.Token)
+		assert.NotEqual(t, "", user.RefreshToken)
+		assert.Equal(t, "", user.MFAChallenge)
+	})
+	t.Run("2단계 인증 사용자는 challenge 발급", func(t *testing.T) {
+		user, err := s.Login("mfa@example.com", "test-password", "127.0.0.1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", user.Token)
+		assert.Equal(t, "", user.RefreshToken)
+		assert.NotEqual(t, "", user.MFAChallenge)
 	})
 	t.Run("틀린 비밀번호", func(t *testing.T) {
-		_, err := s.Login("test@example.com", "invalid-password")
+		lr.EXPECT().
+			RecordLoginFailure(gomock.Eq(domain.AccountLoginKey("test@example.com")), gomock.Any()).
+			Return(domain.LoginThrottle{Failures: 1}, nil)
+		lr.EXPECT().
+			RecordLoginFailure(gomock.Eq(domain.IPLoginKey("127.0.0.1")), gomock.Any()).
+			Return(domain.LoginThrottle{Failures: 1}, nil)
+
+		_, err := s.Login("test@example.com", "invalid-password", "127.0.0.1")
+
+		assert.ErrorIs(t, err, ports.ErrInvalidCredentials)
+	})
+	t.Run("없는 유저도 같은 에러", func(t *testing.T) {
+		lr.EXPECT().
+			RecordLoginFailure(gomock.Eq(domain.AccountLoginKey("null@example.com")), gomock.Any()).
+			Return(domain.LoginThrottle{Failures: 1}, nil)
+		lr.EXPECT().
+			RecordLoginFailure(gomock.Eq(domain.IPLoginKey("127.0.0.1")), gomock.Any()).
+			Return(domain.LoginThrottle{Failures: 1}, nil)
+
+		_, err := s.Login("null@example.com", "test-password", "127.0.0.1")
+
+		assert.ErrorIs(t, err, ports.ErrInvalidCredentials)
+	})
+	t.Run("실패 횟수 초과시 잠금", func(t *testing.T) {
+		lr.EXPECT().
+			RecordLoginFailure(gomock.Eq(domain.AccountLoginKey("test@example.com")), gomock.Any()).
+			Return(domain.LoginThrottle{Failures: domain.AccountLoginThreshold + 2}, nil)
+		lr.EXPECT().
+			RecordLoginFailure(gomock.Eq(domain.IPLoginKey("127.0.0.1")), gomock.Any()).
+			Return(domain.LoginThrottle{Failures: domain.AccountLoginThreshold + 2}, nil)
+		lr.EXPECT().
+			LockLogin(gomock.Eq(domain.AccountLoginKey("test@example.com")), gomock.Any()).
+			DoAndReturn(func(_ string, until time.Time) error {
+				assert.WithinDuration(t, time.Now().Add(4*domain.LoginBackoffBase), until, time.Second)
+				return nil
+			})
+
+		_, err := s.Login("test@example.com", "invalid-password", "127.0.0.1")
+
+		assert.ErrorIs(t, err, ports.ErrInvalidCredentials)
+	})
+	t.Run("잠긴 계정", func(t *testing.T) {
+		_, err := s.Login("locked@example.com", "test-password", "127.0.0.1")
+
+		assert.ErrorIs(t, err, ports.ErrTooManyLoginAttempts)
+	})
+	t.Run("bcrypt 비밀번호는 로그인시 재해싱", func(t *testing.T) {
+		bcryptHash, _ := bcrypt.GenerateFromPassword([]byte("test-password"), bcrypt.DefaultCost)
+		ur.EXPECT().
+			FindByEmail(gomock.Eq("legacy@example.com")).
+			Return(domain.User{
+				Model:    gorm.Model{ID: 3},
+				Password: types.Password{String: string(bcryptHash), Encrypted: true},
+			}, nil)
+		ur.EXPECT().
+			Save(gomock.Any()).
+			DoAndReturn(func(user domain.User) (domain.User, error) {
+				assert.Equal(t, types.Password{String: "test-password", Encrypted: false}, user.Password)
+				return user, nil
+			})
+		mr.EXPECT().
+			FindTwoFactor(gomock.Eq(uint(3))).
+			Return(domain.TwoFactor{}, gorm.ErrRecordNotFound)
+		tr.EXPECT().
+			SaveRefreshToken(gomock.Any()).
+			Return(domain.RefreshToken{}, nil)
+
+		user, err := s.Login("legacy@example.com", "test-password", "127.0.0.1")
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, "", user.Token)
+	})
+}
+
+func Test_authService_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+	aur := mock_ports.NewMockAuthorRepository(ctrl)
+	tr := mock_ports.NewMockTokenRepository(ctrl)
+	m := mock_ports.NewMockMailer(ctrl)
+
+	tr.EXPECT().
+		FindRefreshToken(gomock.Eq(crypto.HashToken("valid-token"))).
+		Return(domain.RefreshToken{
+			Model:     gorm.Model{ID: 1},
+			UserID:    1,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+	tr.EXPECT().
+		FindRefreshToken(gomock.Eq(crypto.HashToken("revoked-token"))).
+		Return(domain.RefreshToken{
+			Model:     gorm.Model{ID: 2},
+			UserID:    1,
+			ExpiresAt: time.Now().Add(time.Hour),
+			RevokedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}, nil)
+	tr.EXPECT().
+		FindRefreshToken(gomock.Eq(crypto.HashToken("expired-token"))).
+		Return(domain.RefreshToken{
+			Model:     gorm.Model{ID: 3},
+			UserID:    1,
+			ExpiresAt: time.Now().Add(-time.Hour),
+		}, nil)
+	tr.EXPECT().
+		FindRefreshToken(gomock.Eq(crypto.HashToken("null"))).
+		Return(domain.RefreshToken{}, gorm.ErrRecordNotFound)
+	tr.EXPECT().
+		RevokeRefreshToken(gomock.Eq(uint(1))).
+		Return(nil)
+	tr.EXPECT().
+		RevokeRefreshTokens(gomock.Eq(uint(1))).
+		Return(nil)
+	tr.EXPECT().
+		SaveRefreshToken(gomock.Any()).
+		Return(domain.RefreshToken{}, nil)
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(1))).
+		Return(domain.User{
+			Model:    gorm.Model{ID: 1},
+			Email:    "test@example.com",
+			Username: "test",
+		}, nil)
+
+	s := NewAuthService(ur, aur, tr, mock_ports.NewMockIdentityRepository(ctrl), mock_ports.NewMockMFARepository(ctrl), mock_ports.NewMockLoginThrottleRepository(ctrl), m, nil, jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), zap.NewNop())
+	t.Run("토큰 갱신 성공", func(t *testing.T) {
+		user, err := s.Refresh("valid-token")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "test", user.Username)
+		assert.NotEqual(t, "", user.Token)
+		assert.NotEqual(t, "", user.RefreshToken)
+		assert.NotEqual(t, "valid-token", user.RefreshToken)
+	})
+	t.Run("폐기된 토큰 재사용", func(t *testing.T) {
+		_, err := s.Refresh("revoked-token")
+
+		assert.ErrorIs(t, err, ports.ErrInvalidRefreshToken)
+	})
+	t.Run("만료된 토큰", func(t *testing.T) {
+		_, err := s.Refresh("expired-token")
+
+		assert.ErrorIs(t, err, ports.ErrInvalidRefreshToken)
+	})
+	t.Run("없는 토큰", func(t *testing.T) {
+		_, err := s.Refresh("null")
+
+		assert.ErrorIs(t, err, ports.ErrInvalidRefreshToken)
+	})
+}
+
+func Test_authService_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+	aur := mock_ports.NewMockAuthorRepository(ctrl)
+	tr := mock_ports.NewMockTokenRepository(ctrl)
+	m := mock_ports.NewMockMailer(ctrl)
+
+	expiresAt := time.Now().Add(time.Hour)
+	tr.EXPECT().
+		RevokeRefreshTokens(gomock.Eq(uint(1))).
+		Return(nil)
+	tr.EXPECT().
+		CreateDeniedToken(gomock.Eq("test-jti"), gomock.Any()).
+		Return(domain.DeniedToken{JTI: "test-jti", ExpiresAt: expiresAt}, nil)
+
+	s := NewAuthService(ur, aur, tr, mock_ports.NewMockIdentityRepository(ctrl), mock_ports.NewMockMFARepository(ctrl), mock_ports.NewMockLoginThrottleRepository(ctrl), m, nil, jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), zap.NewNop())
+	t.Run("로그아웃 성공", func(t *testing.T) {
+		err := s.Logout(domain.AccessClaim{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        "test-jti",
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+			},
+			UID: 1,
+		})
+
+		assert.NoError(t, err)
+	})
+}
+
+func Test_authService_RequestPasswordReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+	aur := mock_ports.NewMockAuthorRepository(ctrl)
+	tr := mock_ports.NewMockTokenRepository(ctrl)
+	m := mock_ports.NewMockMailer(ctrl)
+
+	ur.EXPECT().
+		FindByEmail(gomock.Eq("test@example.com")).
+		Return(domain.User{
+			Model: gorm.Model{ID: 1},
+			Email: "test@example.com",
+		}, nil)
+	ur.EXPECT().
+		FindByEmail(gomock.Eq("null@example.com")).
+		Return(domain.User{}, gorm.ErrRecordNotFound)
+
+	var saved domain.OneTimeToken
+	tr.EXPECT().
+		SaveOneTimeToken(gomock.Any()).
+		DoAndReturn(func(token domain.OneTimeToken) (domain.OneTimeToken, error) {
+			saved = token
+			return token, nil
+		})
+	m.EXPECT().
+		Send(gomock.Any()).
+		DoAndReturn(func(mail ports.Mail) error {
+			assert.Equal(t, "test@example.com", mail.To)
+			assert.Contains(t, mail.Body, saved.Token)
+			assert.NotContains(t, mail.Body, saved.TokenHash)
+			return nil
+		})
+
+	s := NewAuthService(ur, aur, tr, mock_ports.NewMockIdentityRepository(ctrl), mock_ports.NewMockMFARepository(ctrl), mock_ports.NewMockLoginThrottleRepository(ctrl), m, nil, jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), zap.NewNop())
+	t.Run("비밀번호 재설정 메일 발송", func(t *testing.T) {
+		err := s.RequestPasswordReset("test@example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PurposePasswordReset, saved.Purpose)
+		assert.Equal(t, crypto.HashToken(saved.Token), saved.TokenHash)
+	})
+	t.Run("없는 이메일", func(t *testing.T) {
+		err := s.RequestPasswordReset("null@example.com")
+
+		assert.NoError(t, err)
+	})
+}
+
+func Test_authService_ResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+	aur := mock_ports.NewMockAuthorRepository(ctrl)
+	tr := mock_ports.NewMockTokenRepository(ctrl)
+	m := mock_ports.NewMockMailer(ctrl)
+
+	tr.EXPECT().
+		FindOneTimeToken(gomock.Eq(domain.PurposePasswordReset), gomock.Eq(crypto.HashToken("valid-token"))).
+		Return(domain.OneTimeToken{
+			Model:     gorm.Model{ID: 1},
+			UserID:    1,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+	tr.EXPECT().
+		FindOneTimeToken(gomock.Eq(domain.PurposePasswordReset), gomock.Eq(crypto.HashToken("used-token"))).
+		Return(domain.OneTimeToken{
+			Model:     gorm.Model{ID: 2},
+			UserID:    1,
+			ExpiresAt: time.Now().Add(time.Hour),
+			UsedAt:    sql.NullTime{Time: time.Now(), Valid: true},
+		}, nil)
+	tr.EXPECT().
+		FindOneTimeToken(gomock.Eq(domain.PurposePasswordReset), gomock.Eq(crypto.HashToken("expired-token"))).
+		Return(domain.OneTimeToken{
+			Model:     gorm.Model{ID: 3},
+			UserID:    1,
+			ExpiresAt: time.Now().Add(-time.Hour),
+		}, nil)
+	tr.EXPECT().
+		UseOneTimeToken(gomock.Eq(uint(1))).
+		Return(nil)
+	tr.EXPECT().
+		RevokeRefreshTokens(gomock.Eq(uint(1))).
+		Return(nil)
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(1))).
+		Return(domain.User{Model: gorm.Model{ID: 1}}, nil)
+	ur.EXPECT().
+		Save(gomock.Any()).
+		DoAndReturn(func(user domain.User) (domain.User, error) {
+			assert.Equal(t, "new-password", user.Password.String)
+			assert.False(t, user.Password.Encrypted)
+			return user, nil
+		})
+
+	s := NewAuthService(ur, aur, tr, mock_ports.NewMockIdentityRepository(ctrl), mock_ports.NewMockMFARepository(ctrl), mock_ports.NewMockLoginThrottleRepository(ctrl), m, nil, jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), zap.NewNop())
+	t.Run("비밀번호 재설정 성공", func(t *testing.T) {
+		err := s.ResetPassword("valid-token", "new-password")
+
+		assert.NoError(t, err)
+	})
+	t.Run("사용된 토큰", func(t *testing.T) {
+		err := s.ResetPassword("used-token", "new-password")
+
+		assert.ErrorIs(t, err, ports.ErrInvalidResetToken)
+	})
+	t.Run("만료된 토큰", func(t *testing.T) {
+		err := s.ResetPassword("expired-token", "new-password")
+
+		assert.ErrorIs(t, err, ports.ErrInvalidResetToken)
+	})
+}
+
+func Test_authService_Verify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+	aur := mock_ports.NewMockAuthorRepository(ctrl)
+	tr := mock_ports.NewMockTokenRepository(ctrl)
+	m := mock_ports.NewMockMailer(ctrl)
+
+	tr.EXPECT().
+		FindOneTimeToken(gomock.Eq(domain.PurposeEmailVerification), gomock.Eq(crypto.HashToken("valid-token"))).
+		Return(domain.OneTimeToken{
+			Model:     gorm.Model{ID: 1},
+			UserID:    1,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+	tr.EXPECT().
+		FindOneTimeToken(gomock.Eq(domain.PurposeEmailVerification), gomock.Eq(crypto.HashToken("null"))).
+		Return(domain.OneTimeToken{}, gorm.ErrRecordNotFound)
+	tr.EXPECT().
+		UseOneTimeToken(gomock.Eq(uint(1))).
+		Return(nil)
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(1))).
+		Return(domain.User{Model: gorm.Model{ID: 1}}, nil)
+	ur.EXPECT().
+		Save(gomock.Any()).
+		DoAndReturn(func(user domain.User) (domain.User, error) {
+			assert.True(t, user.Verified())
+			return user, nil
+		})
+
+	s := NewAuthService(ur, aur, tr, mock_ports.NewMockIdentityRepository(ctrl), mock_ports.NewMockMFARepository(ctrl), mock_ports.NewMockLoginThrottleRepository(ctrl), m, nil, jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), zap.NewNop())
+	t.Run("이메일 인증 성공", func(t *testing.T) {
+		err := s.Verify("valid-token")
+
+		assert.NoError(t, err)
+	})
+	t.Run("없는 토큰", func(t *testing.T) {
+		err := s.Verify("null")
+
+		assert.ErrorIs(t, err, ports.ErrInvalidVerificationToken)
+	})
+}
+
+func Test_authService_CompleteExternalLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+	aur := mock_ports.NewMockAuthorRepository(ctrl)
+	tr := mock_ports.NewMockTokenRepository(ctrl)
+	ir := mock_ports.NewMockIdentityRepository(ctrl)
+	mr := mock_ports.NewMockMFARepository(ctrl)
+	m := mock_ports.NewMockMailer(ctrl)
+	p := mock_ports.NewMockIdentityProvider(ctrl)
+
+	p.EXPECT().Name().Return("test").AnyTimes()
+	ir.EXPECT().
+		FindLoginState(gomock.Any()).
+		DoAndReturn(func(stateHash string) (domain.LoginState, error) {
+			switch stateHash {
+			case crypto.HashToken("used"):
+				return domain.LoginState{}, gorm.ErrRecordNotFound
+			case crypto.HashToken("expired"):
+				return domain.LoginState{Provider: "test", ExpiresAt: time.Now().Add(-time.Minute)}, nil
+			default:
+				return domain.LoginState{
+					Model:        gorm.Model{ID: 1},
+					Provider:     "test",
+					Nonce:        "nonce",
+					CodeVerifier: "verifier",
+					ExpiresAt:    time.Now().Add(time.Minute),
+				}, nil
+			}
+		}).
+		AnyTimes()
+	ir.EXPECT().UseLoginState(gomock.Any()).Return(nil).AnyTimes()
+	tr.EXPECT().SaveRefreshToken(gomock.Any()).Return(domain.RefreshToken{}, nil).AnyTimes()
+	mr.EXPECT().FindTwoFactor(gomock.Any()).Return(domain.TwoFactor{}, gorm.ErrRecordNotFound).AnyTimes()
+
+	s := NewAuthService(ur, aur, tr, ir, mr, mock_ports.NewMockLoginThrottleRepository(ctrl), m, []ports.IdentityProvider{p}, jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), zap.NewNop())
+	t.Run("연결된 계정으로 로그인", func(t *testing.T) {
+		p.EXPECT().
+			Exchange(gomock.Eq("linked"), gomock.Eq("verifier"), gomock.Eq("nonce")).
+			Return(domain.ExternalIdentity{Provider: "test", Subject: "sub-1", Email: "test@example.com"}, nil)
+		ir.EXPECT().
+			FindIdentity(gomock.Eq("test"), gomock.Eq("sub-1")).
+			Return(domain.Identity{UserID: 1}, nil)
+		ur.EXPECT().
+			FindByID(gomock.Eq(uint(1))).
+			Return(domain.User{Model: gorm.Model{ID: 1}, Username: "test"}, nil)
+
+		user, err := s.CompleteExternalLogin("test", "state", "linked")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "test", user.Username)
+		assert.NotEqual(t, "", user.Token)
+		assert.NotEqual(t, "", user.RefreshToken)
+	})
+	t.Run("인증된 이메일로 기존 계정 연결", func(t *testing.T) {
+		p.EXPECT().
+			Exchange(gomock.Eq("same-email"), gomock.Any(), gomock.Any()).
+			Return(domain.ExternalIdentity{Provider: "test", Subject: "sub-2", Email: "test@example.com", EmailVerified: true}, nil)
+		ir.EXPECT().
+			FindIdentity(gomock.Eq("test"), gomock.Eq("sub-2")).
+			Return(domain.Identity{}, gorm.ErrRecordNotFound)
+		ur.EXPECT().
+			FindByEmail(gomock.Eq("test@example.com")).
+			Return(domain.User{Model: gorm.Model{ID: 1}, VerifiedAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil)
+		ir.EXPECT().
+			SaveIdentity(gomock.Any()).
+			DoAndReturn(func(identity domain.Identity) (domain.Identity, error) {
+				assert.Equal(t, uint(1), identity.UserID)
+				assert.Equal(t, "sub-2", identity.Subject)
+				return identity, nil
+			})
+
+		_, err := s.CompleteExternalLogin("test", "state", "same-email")
+
+		assert.NoError(t, err)
+	})
+	t.Run("인증되지 않은 기존 계정은 자격 증명을 초기화하고 연결", func(t *testing.T) {
+		p.EXPECT().
+			Exchange(gomock.Eq("unverified-user"), gomock.Any(), gomock.Any()).
+			Return(domain.ExternalIdentity{Provider: "test", Subject: "sub-5", Email: "victim@example.com", EmailVerified: true}, nil)
+		ir.EXPECT().
+			FindIdentity(gomock.Eq("test"), gomock.Eq("sub-5")).
+			Return(domain.Identity{}, gorm.ErrRecordNotFound)
+		ur.EXPECT().
+			FindByEmail(gomock.Eq("victim@example.com")).
+			Return(domain.User{
+				Model:    gorm.Model{ID: 3},
+				Email:    "victim@example.com",
+				Password: types.Password{String: "attacker-password"},
+			}, nil)
+		ur.EXPECT().
+			Save(gomock.Any()).
+			DoAndReturn(func(user domain.User) (domain.User, error) {
+				assert.True(t, user.Verified())
+				assert.False(t, user.Password.Encrypted)
+				assert.NotEqual(t, "attacker-password", user.Password.String)
+				return user, nil
+			})
+		tr.EXPECT().RevokeRefreshTokens(gomock.Eq(uint(3))).Return(nil)
+		tr.EXPECT().DeletePersonalAccessTokens(gomock.Eq(uint(3))).Return(nil)
+		ir.EXPECT().DeleteIdentities(gomock.Eq(uint(3))).Return(nil)
+		mr.EXPECT().DeleteTwoFactor(gomock.Eq(uint(3))).Return(nil)
+		ir.EXPECT().
+			SaveIdentity(gomock.Any()).
+			DoAndReturn(func(identity domain.Identity) (domain.Identity, error) {
+				assert.Equal(t, uint(3), identity.UserID)
+				return identity, nil
+			})
+
+		user, err := s.CompleteExternalLogin("test", "state", "unverified-user")
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), user.ID)
+	})
+	t.Run("인증되지 않은 이메일은 기존 계정에 연결하지 않음", func(t *testing.T) {
+		p.EXPECT().
+			Exchange(gomock.Eq("unverified-email"), gomock.Any(), gomock.Any()).
+			Return(domain.ExternalIdentity{Provider: "test", Subject: "sub-3", Email: "test@example.com"}, nil)
+		ir.EXPECT().
+			FindIdentity(gomock.Eq("test"), gomock.Eq("sub-3")).
+			Return(domain.Identity{}, gorm.ErrRecordNotFound)
+		ur.EXPECT().
+			FindByEmail(gomock.Eq("test@example.com")).
+			Return(domain.User{Model: gorm.Model{ID: 1}}, nil)
+
+		_, err := s.CompleteExternalLogin("test", "state", "unverified-email")
+
+		assert.ErrorIs(t, err, ports.ErrDuplicatedEmailOrUsername)
+	})
+	t.Run("신규 계정 가입", func(t *testing.T) {
+		p.EXPECT().
+			Exchange(gomock.Eq("new"), gomock.Any(), gomock.Any()).
+			Return(domain.ExternalIdentity{Provider: "test", Subject: "sub-4", Email: "new@example.com", EmailVerified: true}, nil)
+		ir.EXPECT().
+			FindIdentity(gomock.Eq("test"), gomock.Eq("sub-4")).
+			Return(domain.Identity{}, gorm.ErrRecordNotFound)
+		ur.EXPECT().
+			FindByEmail(gomock.Eq("new@example.com")).
+			Return(domain.User{}, gorm.ErrRecordNotFound)
+		ur.EXPECT().
+			FindByUsername(gomock.Eq("new")).
+			Return(domain.User{}, nil)
+		ur.EXPECT().
+			FindByUsername(gomock.Any()).
+			Return(domain.User{}, gorm.ErrRecordNotFound)
+		ur.EXPECT().
+			Save(gomock.Any()).
+			DoAndReturn(func(user domain.User) (domain.User, error) {
+				assert.Equal(t, "new@example.com", user.Email)
+				assert.NotEqual(t, "new", user.Username)
+				assert.True(t, user.Verified())
+				user.ID = 2
+				return user, nil
+			})
+		ir.EXPECT().
+			SaveIdentity(gomock.Any()).
+			Return(domain.Identity{}, nil)
+
+		user, err := s.CompleteExternalLogin("test", "state", "new")
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), user.ID)
+	})
+	t.Run("사용된 state", func(t *testing.T) {
+		_, err := s.CompleteExternalLogin("test", "used", "code")
+
+		assert.ErrorIs(t, err, ports.ErrInvalidLoginState)
+	})
+	t.Run("만료된 state", func(t *testing.T) {
+		_, err := s.CompleteExternalLogin("test", "expired", "code")
+
+		assert.ErrorIs(t, err, ports.ErrInvalidLoginState)
+	})
+	t.Run("없는 provider", func(t *testing.T) {
+		_, err := s.CompleteExternalLogin("null", "state", "code")
 
-		assert.ErrorIs(t, err, ports.ErrInvalidPassword)
+		assert.ErrorIs(t, err, ports.ErrUnknownProvider)
 	})
-	t.Run("없는 유저", func(t *testing.T) {
-		_, err := s.Login("null@example.com", "test-password")
+}
 
-		assert.ErrorIs(t, err, ports.ErrResourceNotFound)
+func Test_authService_LoginMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+	aur := mock_ports.NewMockAuthorRepository(ctrl)
+	tr := mock_ports.NewMockTokenRepository(ctrl)
+	mr := mock_ports.NewMockMFARepository(ctrl)
+	lr := mock_ports.NewMockLoginThrottleRepository(ctrl)
+	m := mock_ports.NewMockMailer(ctrl)
+
+	secret, _ := totp.GenerateSecret()
+	tr.EXPECT().
+		FindOneTimeToken(gomock.Eq(domain.PurposeMFAChallenge), gomock.Any()).
+		DoAndReturn(func(_ domain.TokenPurpose, tokenHash string) (domain.OneTimeToken, error) {
+			switch tokenHash {
+			case crypto.HashToken("used"):
+				return domain.OneTimeToken{UserID: 1, UsedAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil
+			case crypto.HashToken("expired"):
+				return domain.OneTimeToken{UserID: 1, ExpiresAt: time.Now().Add(-time.Minute)}, nil
+			default:
+				return domain.OneTimeToken{Model: gorm.Model{ID: 1}, UserID: 1, ExpiresAt: time.Now().Add(time.Minute)}, nil
+			}
+		}).
+		AnyTimes()
+	tr.EXPECT().UseOneTimeToken(gomock.Eq(uint(1))).Return(nil).AnyTimes()
+	tr.EXPECT().SaveRefreshToken(gomock.Any()).Return(domain.RefreshToken{}, nil).AnyTimes()
+	mr.EXPECT().
+		FindTwoFactor(gomock.Eq(uint(1))).
+		Return(domain.TwoFactor{
+			Model:     gorm.Model{ID: 1},
+			UserID:    1,
+			Secret:    secret,
+			EnabledAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}, nil).
+		AnyTimes()
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(1))).
+		Return(domain.User{Model: gorm.Model{ID: 1}, Email: "test@example.com", Username: "test"}, nil).
+		AnyTimes()
+	accountKey := domain.AccountLoginKey("test@example.com")
+	locked := false
+	lr.EXPECT().
+		FindLoginThrottles(gomock.Eq([]string{accountKey})).
+		DoAndReturn(func(keys []string) ([]domain.LoginThrottle, error) {
+			if locked {
+				return []domain.LoginThrottle{{Key: accountKey, Failures: 6, LockedUntil: time.Now().Add(time.Minute)}}, nil
+			}
+			return nil, nil
+		}).
+		AnyTimes()
+
+	s := NewAuthService(ur, aur, tr, mock_ports.NewMockIdentityRepository(ctrl), mr, lr, m, nil, jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), zap.NewNop())
+	t.Run("TOTP 코드로 로그인", func(t *testing.T) {
+		code, _ := totp.Code(secret, totp.Step(time.Now()))
+		mr.EXPECT().UseTwoFactorStep(gomock.Eq(uint(1)), gomock.Any()).Return(nil)
+		lr.EXPECT().DeleteLoginThrottle(gomock.Eq(accountKey)).Return(nil)
+
+		user, err := s.LoginMFA("challenge", code)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "test", user.Username)
+		assert.NotEqual(t, "", user.Token)
+		assert.NotEqual(t, "", user.RefreshToken)
+	})
+	t.Run("이미 사용된 TOTP 코드", func(t *testing.T) {
+		code, _ := totp.Code(secret, totp.Step(time.Now()))
+		mr.EXPECT().UseTwoFactorStep(gomock.Eq(uint(1)), gomock.Any()).Return(gorm.ErrRecordNotFound)
+		lr.EXPECT().RecordLoginFailure(gomock.Eq(accountKey), gomock.Any()).Return(domain.LoginThrottle{Failures: 1}, nil)
+
+		_, err := s.LoginMFA("challenge", code)
+
+		assert.ErrorIs(t, err, ports.ErrInvalidMFACode)
+	})
+	t.Run("복구 코드로 로그인", func(t *testing.T) {
+		mr.EXPECT().
+			UseRecoveryCode(gomock.Eq(uint(1)), gomock.Eq(domain.HashRecoveryCode("abcde12345"))).
+			Return(nil)
+		lr.EXPECT().DeleteLoginThrottle(gomock.Eq(accountKey)).Return(nil)
+
+		user, err := s.LoginMFA("challenge", "ABCDE-12345")
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, "", user.Token)
+	})
+	t.Run("틀린 복구 코드", func(t *testing.T) {
+		mr.EXPECT().UseRecoveryCode(gomock.Eq(uint(1)), gomock.Any()).Return(gorm.ErrRecordNotFound)
+		lr.EXPECT().
+			RecordLoginFailure(gomock.Eq(accountKey), gomock.Any()).
+			Return(domain.LoginThrottle{Failures: domain.AccountLoginThreshold + 1}, nil)
+		lr.EXPECT().LockLogin(gomock.Eq(accountKey), gomock.Any()).Return(nil)
+
+		_, err := s.LoginMFA("challenge", "wrong-code")
+
+		assert.ErrorIs(t, err, ports.ErrInvalidMFACode)
+	})
+	t.Run("잠긴 계정은 새 challenge로도 코드 확인 불가", func(t *testing.T) {
+		locked = true
+		defer func() { locked = false }()
+
+		_, err := s.LoginMFA("challenge", "ABCDE-12345")
+
+		assert.ErrorIs(t, err, ports.ErrTooManyLoginAttempts)
+	})
+	t.Run("사용된 challenge", func(t *testing.T) {
+		_, err := s.LoginMFA("used", "123456")
+
+		assert.ErrorIs(t, err, ports.ErrInvalidMFAChallenge)
+	})
+	t.Run("만료된 challenge", func(t *testing.T) {
+		_, err := s.LoginMFA("expired", "123456")
+
+		assert.ErrorIs(t, err, ports.ErrInvalidMFAChallenge)
+	})
+}
+
+func Test_authService_ConfirmTOTP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+	aur := mock_ports.NewMockAuthorRepository(ctrl)
+	tr := mock_ports.NewMockTokenRepository(ctrl)
+	mr := mock_ports.NewMockMFARepository(ctrl)
+	m := mock_ports.NewMockMailer(ctrl)
+
+	secret, _ := totp.GenerateSecret()
+	mr.EXPECT().
+		FindTwoFactor(gomock.Eq(uint(1))).
+		Return(domain.TwoFactor{Model: gorm.Model{ID: 1}, UserID: 1, Secret: secret}, nil).
+		AnyTimes()
+	mr.EXPECT().
+		FindTwoFactor(gomock.Eq(uint(2))).
+		Return(domain.TwoFactor{UserID: 2, Secret: secret, EnabledAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil)
+	mr.EXPECT().
+		SaveTwoFactor(gomock.Any()).
+		DoAndReturn(func(twoFactor domain.TwoFactor) (domain.TwoFactor, error) {
+			assert.True(t, twoFactor.Enabled())
+			assert.NotZero(t, twoFactor.LastUsedStep)
+			return twoFactor, nil
+		})
+	mr.EXPECT().
+		ReplaceRecoveryCodes(gomock.Eq(uint(1)), gomock.Len(domain.RecoveryCodeCount)).
+		Return(nil)
+
+	s := NewAuthService(ur, aur, tr, mock_ports.NewMockIdentityRepository(ctrl), mr, mock_ports.NewMockLoginThrottleRepository(ctrl), m, nil, jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), zap.NewNop())
+	t.Run("2단계 인증 활성화", func(t *testing.T) {
+		code, _ := totp.Code(secret, totp.Step(time.Now()))
+
+		codes, err := s.ConfirmTOTP(1, code)
+
+		assert.NoError(t, err)
+		assert.Len(t, codes, domain.RecoveryCodeCount)
+	})
+	t.Run("틀린 코드", func(t *testing.T) {
+		code, _ := totp.Code(secret, totp.Step(time.Now())-10)
+
+		_, err := s.ConfirmTOTP(1, code)
+
+		assert.ErrorIs(t, err, ports.ErrInvalidMFACode)
+	})
+	t.Run("이미 활성화됨", func(t *testing.T) {
+		code, _ := totp.Code(secret, totp.Step(time.Now()))
+
+		_, err := s.ConfirmTOTP(2, code)
+
+		assert.ErrorIs(t, err, ports.ErrMFAAlreadyEnabled)
+	})
+}
+
+func Test_authService_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+	aur := mock_ports.NewMockAuthorRepository(ctrl)
+
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(1))).
+		Return(domain.User{Model: gorm.Model{ID: 1}, Email: "test@test.com", Username: "test"}, nil)
+	ur.EXPECT().
+		Save(gomock.Any()).
+		DoAndReturn(func(user domain.User) (domain.User, error) {
+			return user, nil
+		})
+	aur.EXPECT().
+		UpdateAuthorInfo(gomock.Any()).
+		DoAndReturn(func(user domain.User) error {
+			assert.Equal(t, "renamed", user.Username)
+			assert.Equal(t, sql.NullString{String: "https://example.com/1.png", Valid: true}, user.Image)
+			return nil
+		})
+
+	s := NewAuthService(ur, aur, mock_ports.NewMockTokenRepository(ctrl), mock_ports.NewMockIdentityRepository(ctrl), mock_ports.NewMockMFARepository(ctrl), mock_ports.NewMockLoginThrottleRepository(ctrl), mock_ports.NewMockMailer(ctrl), nil, jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), zap.NewNop())
+	t.Run("프로필 변경 시 작성자 정보 동기화", func(t *testing.T) {
+		username, image := "renamed", "https://example.com/1.png"
+		user, err := s.Update(1, ports.UserUpdateFields{Username: &username, Image: &image})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "test@test.com", user.Email)
+		assert.Equal(t, image, user.Image.String)
+	})
+	t.Run("이메일과 이미지를 함께 변경", func(t *testing.T) {
+		ur.EXPECT().
+			FindByID(gomock.Eq(uint(1))).
+			Return(domain.User{Model: gorm.Model{ID: 1}, Email: "test@test.com", Username: "test"}, nil)
+		ur.EXPECT().
+			Save(gomock.Any()).
+			DoAndReturn(func(user domain.User) (domain.User, error) {
+				return user, nil
+			})
+		aur.EXPECT().
+			UpdateAuthorInfo(gomock.Any()).
+			Return(nil)
+
+		// image was once set to the new email
+		email, image := "new@test.com", "https://example.com/2.png"
+		user, err := s.Update(1, ports.UserUpdateFields{Email: &email, Image: &image})
+
+		assert.NoError(t, err)
+		assert.Equal(t, email, user.Email)
+		assert.Equal(t, sql.NullString{String: image, Valid: true}, user.Image)
+	})
+	t.Run("탈퇴한 사용자용으로 예약된 이름으로 변경", func(t *testing.T) {
+		ur.EXPECT().
+			FindByID(gomock.Eq(uint(1))).
+			Return(domain.User{Model: gorm.Model{ID: 1}, Email: "test@test.com", Username: "test"}, nil)
+
+		username := "Deleted-2"
+		_, err := s.Update(1, ports.UserUpdateFields{Username: &username})
+
+		assert.ErrorIs(t, err, ports.ErrReservedEmailOrUsername)
+	})
+}
+
+func Test_authService_RepairAuthors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	aur := mock_ports.NewMockAuthorRepository(ctrl)
+
+	gomock.InOrder(
+		aur.EXPECT().FindDriftedAuthorIDs().Return([]uint{2, 3}, nil),
+		aur.EXPECT().RepairAuthorInfo(gomock.Eq([]uint{2, 3})).Return(int64(4), nil),
+		aur.EXPECT().FindDriftedAuthorIDs().Return(nil, nil),
+	)
+
+	s := NewAuthService(mock_ports.NewMockUserRepository(ctrl), aur, mock_ports.NewMockTokenRepository(ctrl), mock_ports.NewMockIdentityRepository(ctrl), mock_ports.NewMockMFARepository(ctrl), mock_ports.NewMockLoginThrottleRepository(ctrl), mock_ports.NewMockMailer(ctrl), nil, jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), zap.NewNop())
+	t.Run("어긋난 작성자 정보 복구", func(t *testing.T) {
+		repaired, err := s.RepairAuthors()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []uint{2, 3}, repaired)
+	})
+	t.Run("복구할 작성자 정보 없음", func(t *testing.T) {
+		repaired, err := s.RepairAuthors()
+
+		assert.NoError(t, err)
+		assert.Empty(t, repaired)
 	})
 }
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"errors"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+// authorize checks actor against the policy with role stored in database instead of the claim,
+// so that demoted user can not use the old role with token issued before.
+// Owner is allowed without reading role, since ownership does not depend on it.
+func authorize(userRepo ports.UserRepository, actor domain.Actor, action domain.Action, ownerID uint) (bool, error) {
+	if (domain.Actor{ID: actor.ID}).Can(action, ownerID) {
+		return true, nil
+	}
+
+	user, err := userRepo.FindByID(actor.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return user.Actor().Can(action, ownerID), nil
+}
@@ -7,12 +7,21 @@ import (
 	"github.com/samber/lo"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"time"
 )
 
+type CommentOptions struct {
+	// MaxDepth is the maximum depth of replies, 0 disallows replies
+	MaxDepth int
+	// MaxTreeReplies bounds replies nested in one page of tree layout, in addition to top level comments
+	MaxTreeReplies int
+}
+
 type commentService struct {
 	commentRepo ports.CommentRepository
 	articleRepo ports.ArticleRepository
 	userRepo    ports.UserRepository
+	options     CommentOptions
 	logger      *zap.SugaredLogger
 }
 
@@ -20,22 +29,25 @@ func NewCommentService(
 	commentRepo ports.CommentRepository,
 	articleRepo ports.ArticleRepository,
 	userRepo ports.UserRepository,
+	options CommentOptions,
 	logger *zap.Logger) ports.CommentService {
 	return &commentService{
 		commentRepo: commentRepo,
 		articleRepo: articleRepo,
 		userRepo:    userRepo,
+		options:     options,
 		logger:      logger.Sugar().Named("commentService"),
 	}
 }
 
 func (s commentService) WithTx(tx *gorm.DB) ports.CommentService {
 	s.commentRepo = s.commentRepo.WithTx(tx)
+	s.articleRepo = s.articleRepo.WithTx(tx)
 	s.userRepo = s.userRepo.WithTx(tx)
 	return s
 }
 
-func (s commentService) Create(authorID uint, slug string, body string) (domain.CommentView, error) {
+func (s commentService) Create(authorID uint, slug string, body string, parentID *uint) (domain.CommentView, error) {
 	author, err := s.userRepo.FindByID(authorID)
 	if err != nil {
 		s.logger.Errorw("failed to find user", "err", err)
@@ -48,8 +60,11 @@ func (s commentService) Create(authorID uint, slug string, body string) (domain.
 		s.logger.Errorw("failed to find article", "err", err)
 		return domain.CommentView{}, ports.ErrInternal
 	}
+	if !article.ReadableBy(authorID) {
+		return domain.CommentView{}, ports.ErrResourceNotFound
+	}
 
-	saved, err := s.commentRepo.Save(domain.Comment{
+	comment := domain.Comment{
 		Body:      body,
 		ArticleID: article.ID,
 		Author: domain.Author{
@@ -58,7 +73,28 @@ func (s commentService) Create(authorID uint, slug string, body string) (domain.
 			Bio:      author.Bio,
 			Image:    author.Image,
 		},
-	})
+	}
+	if parentID != nil {
+		// parent is locked, so that it is not deleted before the reply is saved
+		parent, err := s.commentRepo.LockByID(*parentID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.CommentView{}, ports.ErrInvalidParentComment
+		} else if err != nil {
+			s.logger.Errorw("failed to find parent comment", "err", err)
+			return domain.CommentView{}, ports.ErrInternal
+		}
+		// tombstone keeps existing replies, but can not be replied anymore
+		if parent.ArticleID != article.ID || parent.Tombstoned {
+			return domain.CommentView{}, ports.ErrInvalidParentComment
+		}
+		if parent.Depth >= s.options.MaxDepth {
+			return domain.CommentView{}, ports.ErrCommentTooDeep
+		}
+		comment.ParentID = &parent.ID
+		comment.Depth = parent.Depth + 1
+	}
+
+	saved, err := s.commentRepo.Save(comment)
 	if err != nil {
 		s.logger.Errorw("failed to create comment", "err", err)
 		return domain.CommentView{}, ports.ErrInternal
@@ -66,29 +102,105 @@ func (s commentService) Create(authorID uint, slug string, body string) (domain.
 	return domain.NewCommentView(saved, false), nil
 }
 
-func (s commentService) GetFromArticle(readerID uint, slug string) ([]domain.CommentView, error) {
-	_, err := s.articleRepo.FindBySlug(slug)
+func (s commentService) GetFromArticle(readerID uint, slug string, options ports.CommentListOptions, pageable ports.Pageable) ([]domain.CommentView, ports.Page, error) {
+	if pageable.Cursor != nil && !options.Sort.OrderedByCreation() {
+		return nil, ports.Page{}, ports.ErrUnsupportedCursor
+	}
+
+	article, err := s.articleRepo.FindBySlug(slug)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, ports.ErrResourceNotFound
+		return nil, ports.Page{}, ports.ErrResourceNotFound
 	} else if err != nil {
 		s.logger.Errorw("failed to find article", "err", err)
-		return nil, ports.ErrInternal
+		return nil, ports.Page{}, ports.ErrInternal
+	}
+	if !article.ReadableBy(readerID) {
+		return nil, ports.Page{}, ports.ErrResourceNotFound
 	}
 
-	comments, err := s.commentRepo.FindFromArticle(slug)
+	tree := options.Layout == ports.LayoutTree
+	comments, page, err := s.commentRepo.FindFromArticle(ports.CommentSearchConditions{
+		Slug:     slug,
+		TopLevel: tree && options.ParentID == nil,
+		ParentID: options.ParentID,
+		Sort:     options.Sort,
+		Pageable: pageable,
+	})
 	if err != nil {
 		s.logger.Errorw("failed to find comments", "err", err)
-		return nil, ports.ErrInternal
+		return nil, ports.Page{}, ports.ErrInternal
+	}
+	threads := comments
+	var repliesCounts map[uint]int
+	if tree {
+		var replies []domain.Comment
+		replies, repliesCounts, err = s.findReplies(comments)
+		if err != nil {
+			s.logger.Errorw("failed to find replies", "err", err)
+			return nil, ports.Page{}, ports.ErrInternal
+		}
+		threads = append(threads, replies...)
 	}
 
-	authorIDs := lo.Map(comments, func(comment domain.Comment, index int) uint { return comment.Author.ID })
+	authorIDs := lo.Map(threads, func(comment domain.Comment, index int) uint { return comment.Author.ID })
 	follows, err := s.userRepo.FindFollows(readerID, authorIDs)
 	if err != nil {
 		s.logger.Errorw("failed to find follows", "err", err)
-		return nil, ports.ErrInternal
+		return nil, ports.Page{}, ports.ErrInternal
 	}
 
-	return zipToCommentView(comments, follows), nil
+	views := zipToCommentView(threads, follows)
+	if tree {
+		return nestReplies(views[:len(comments)], views[len(comments):], repliesCounts), page, nil
+	}
+	return views, page, nil
+}
+
+// findReplies finds descendants of comments level by level from the oldest, at most MaxTreeReplies in total.
+// Counts of direct replies are found with them, so that client can tell which thread is cut
+// and continue it by listing replies of the comment.
+func (s commentService) findReplies(comments []domain.Comment) ([]domain.Comment, map[uint]int, error) {
+	var replies []domain.Comment
+	counts := make(map[uint]int)
+	parents := comments
+	for len(parents) > 0 {
+		parentIDs := lo.Map(parents, func(comment domain.Comment, index int) uint { return comment.ID })
+		levelCounts, err := s.commentRepo.CountReplies(parentIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+		for id, count := range levelCounts {
+			counts[id] = count
+		}
+
+		budget := s.options.MaxTreeReplies - len(replies)
+		if budget <= 0 || len(levelCounts) == 0 {
+			break
+		}
+		children, err := s.commentRepo.FindReplies(parentIDs, budget)
+		if err != nil {
+			return nil, nil, err
+		}
+		replies = append(replies, children...)
+		parents = children
+	}
+	return replies, counts, nil
+}
+
+func nestReplies(roots []domain.CommentView, replies []domain.CommentView, counts map[uint]int) []domain.CommentView {
+	children := lo.GroupBy(replies, func(reply domain.CommentView) uint { return *reply.ParentID })
+
+	var nest func(comment domain.CommentView) domain.CommentView
+	nest = func(comment domain.CommentView) domain.CommentView {
+		comment.Replies = lo.Map(children[comment.ID], func(reply domain.CommentView, index int) domain.CommentView {
+			return nest(reply)
+		})
+		comment.RepliesCount = counts[comment.ID]
+		return comment
+	}
+	return lo.Map(roots, func(root domain.CommentView, index int) domain.CommentView {
+		return nest(root)
+	})
 }
 
 func zipToCommentView(comments []domain.Comment, follows []domain.Follow) []domain.CommentView {
@@ -100,12 +212,107 @@ func zipToCommentView(comments []domain.Comment, follows []domain.Follow) []doma
 	})
 }
 
-func (s commentService) Delete(authorID, commentID uint) error {
-	err := s.commentRepo.Delete(commentID, authorID)
+func (s commentService) Update(actor domain.Actor, slug string, commentID uint, body string) (domain.CommentView, error) {
+	comment, err := s.findInArticle(actor.ID, slug, commentID)
+	if err != nil {
+		return domain.CommentView{}, err
+	}
+	if !actor.Can(domain.ActionUpdateComment, comment.Author.ID) {
+		s.logger.Infow("illegal request to update non-owned comment", "user-id", actor.ID)
+		return domain.CommentView{}, ports.ErrNonOwnedContent
+	}
+	if comment.Body == body {
+		return domain.NewCommentView(comment, false), nil
+	}
+
+	revision := comment.Edit(body, actor.ID, time.Now())
+	if _, err := s.commentRepo.SaveRevision(revision); err != nil {
+		s.logger.Errorw("failed to save comment revision", "err", err)
+		return domain.CommentView{}, ports.ErrInternal
+	}
+	saved, err := s.commentRepo.Save(comment)
+	if err != nil {
+		s.logger.Errorw("failed to update comment", "err", err)
+		return domain.CommentView{}, ports.ErrInternal
+	}
+	return domain.NewCommentView(saved, false), nil
+}
+
+func (s commentService) ListRevisions(actor domain.Actor, slug string, commentID uint) ([]domain.CommentRevision, error) {
+	allowed, err := authorize(s.userRepo, actor, domain.ActionViewCommentHistory, 0)
+	if err != nil {
+		s.logger.Errorw("failed to find user by id", "id", actor.ID, "err", err)
+		return nil, ports.ErrInternal
+	}
+	if !allowed {
+		return nil, ports.ErrPermissionDenied
+	}
+	comment, err := s.findInArticle(actor.ID, slug, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.commentRepo.FindRevisions(comment.ID)
+	if err != nil {
+		s.logger.Errorw("failed to find comment revisions", "err", err)
+		return nil, ports.ErrInternal
+	}
+	return revisions, nil
+}
+
+// findInArticle finds comment of article readable by reader. Tombstone is not found, as it has no content.
+func (s commentService) findInArticle(readerID uint, slug string, commentID uint) (domain.Comment, error) {
+	article, err := s.articleRepo.FindBySlug(slug)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		s.logger.Infow("illegal request to delete non-owned comment", "user-id", authorID, "err", err)
-		return ports.ErrNonOwnedContent
+		return domain.Comment{}, ports.ErrResourceNotFound
 	} else if err != nil {
+		s.logger.Errorw("failed to find article", "err", err)
+		return domain.Comment{}, ports.ErrInternal
+	}
+	if !article.ReadableBy(readerID) {
+		return domain.Comment{}, ports.ErrResourceNotFound
+	}
+
+	comment, err := s.commentRepo.FindByID(commentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Comment{}, ports.ErrResourceNotFound
+	} else if err != nil {
+		s.logger.Errorw("failed to find comment", "err", err)
+		return domain.Comment{}, ports.ErrInternal
+	}
+	if comment.ArticleID != article.ID || comment.Tombstoned {
+		return domain.Comment{}, ports.ErrResourceNotFound
+	}
+	return comment, nil
+}
+
+func (s commentService) Delete(actor domain.Actor, commentID uint) error {
+	comment, err := s.commentRepo.FindByID(commentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ports.ErrResourceNotFound
+	} else if err != nil {
+		s.logger.Errorw("failed to find comment", "err", err)
+		return ports.ErrInternal
+	}
+	if comment.Tombstoned {
+		return ports.ErrResourceNotFound
+	}
+
+	allowed, err := authorize(s.userRepo, actor, domain.ActionDeleteComment, comment.Author.ID)
+	if err != nil {
+		s.logger.Errorw("failed to find user by id", "id", actor.ID, "err", err)
+		return ports.ErrInternal
+	}
+	if !allowed {
+		s.logger.Infow("illegal request to delete non-owned comment", "user-id", actor.ID)
+		return ports.ErrNonOwnedContent
+	}
+	if actor.ID != comment.Author.ID {
+		s.logger.Infow("comment is deleted by moderator", "user-id", actor.ID, "comment-id", commentID)
+	}
+
+	err = s.commentRepo.Delete(commentID)
+	if err != nil {
 		s.logger.Errorw("failed to delete comment", "err", err)
 		return ports.ErrInternal
 	}
@@ -4,6 +4,7 @@ import (
 	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/ports/mock_ports"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
@@ -27,30 +28,70 @@ func Test_commentService_Create(t *testing.T) {
 		AnyTimes()
 	ar.EXPECT().
 		FindBySlug(gomock.Eq("test-slug")).
-		Return(domain.Article{Model: gorm.Model{ID: 1}}, nil)
+		Return(domain.Article{Model: gorm.Model{ID: 1}}, nil).
+		AnyTimes()
 	ar.EXPECT().
 		FindBySlug("null").
 		Return(domain.Article{}, gorm.ErrRecordNotFound)
-	cr.EXPECT().Save(gomock.Any()).Return(domain.Comment{
-		Model:     gorm.Model{ID: 1},
-		Body:      "test-body",
-		ArticleID: 1,
-		Author:    domain.Author{ID: 1, Username: "test"},
-	}, nil)
-
-	s := NewCommentService(cr, ar, ur, zap.NewNop())
+	cr.EXPECT().
+		LockByID(gomock.Eq(uint(1))).
+		Return(domain.Comment{Model: gorm.Model{ID: 1}, ArticleID: 1}, nil).
+		AnyTimes()
+	cr.EXPECT().
+		LockByID(gomock.Eq(uint(2))).
+		Return(domain.Comment{Model: gorm.Model{ID: 2}, ArticleID: 1, ParentID: lo.ToPtr(uint(1)), Depth: 1}, nil).
+		AnyTimes()
+	cr.EXPECT().
+		LockByID(gomock.Eq(uint(3))).
+		Return(domain.Comment{Model: gorm.Model{ID: 3}, ArticleID: 2}, nil).
+		AnyTimes()
+	cr.EXPECT().
+		LockByID(gomock.Eq(uint(4))).
+		Return(domain.Comment{Model: gorm.Model{ID: 4}, ArticleID: 1, Tombstoned: true}, nil).
+		AnyTimes()
+	cr.EXPECT().
+		Save(gomock.Any()).
+		DoAndReturn(func(comment domain.Comment) (domain.Comment, error) {
+			comment.ID = 10
+			return comment, nil
+		}).
+		AnyTimes()
+
+	s := NewCommentService(cr, ar, ur, CommentOptions{MaxDepth: 1}, zap.NewNop())
 	t.Run("댓글 생성 성공", func(t *testing.T) {
-		comment, err := s.Create(1, "test-slug", "test-body")
+		comment, err := s.Create(1, "test-slug", "test-body", nil)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "test-body", comment.Body)
 		assert.Equal(t, "test", comment.AuthorUsername)
+		assert.Nil(t, comment.ParentID)
 	})
 	t.Run("없는 글에 댓글 생성", func(t *testing.T) {
-		_, err := s.Create(1, "null", "test-body")
+		_, err := s.Create(1, "null", "test-body", nil)
 
 		assert.ErrorIs(t, err, ports.ErrResourceNotFound)
 	})
+	t.Run("답글 생성 성공", func(t *testing.T) {
+		comment, err := s.Create(1, "test-slug", "test-body", lo.ToPtr(uint(1)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, lo.ToPtr(uint(1)), comment.ParentID)
+	})
+	t.Run("최대 깊이를 넘는 답글", func(t *testing.T) {
+		_, err := s.Create(1, "test-slug", "test-body", lo.ToPtr(uint(2)))
+
+		assert.ErrorIs(t, err, ports.ErrCommentTooDeep)
+	})
+	t.Run("다른 글의 댓글에 답글", func(t *testing.T) {
+		_, err := s.Create(1, "test-slug", "test-body", lo.ToPtr(uint(3)))
+
+		assert.ErrorIs(t, err, ports.ErrInvalidParentComment)
+	})
+	t.Run("삭제된 댓글에 답글", func(t *testing.T) {
+		_, err := s.Create(1, "test-slug", "test-body", lo.ToPtr(uint(4)))
+
+		assert.ErrorIs(t, err, ports.ErrInvalidParentComment)
+	})
 }
 
 func Test_commentService_GetFromArticle(t *testing.T) {
@@ -66,7 +107,7 @@ func Test_commentService_GetFromArticle(t *testing.T) {
 		FindBySlug("null").
 		Return(domain.Article{}, gorm.ErrRecordNotFound)
 	cr.EXPECT().
-		FindFromArticle(gomock.Any()).
+		FindFromArticle(gomock.Eq(ports.CommentSearchConditions{Slug: "test-slug"})).
 		Return([]domain.Comment{
 			{
 				Model:     gorm.Model{ID: 1},
@@ -80,7 +121,7 @@ func Test_commentService_GetFromArticle(t *testing.T) {
 				ArticleID: 1,
 				Author:    domain.Author{ID: 2, Username: "test2"},
 			},
-		}, nil)
+		}, ports.Page{Total: 2}, nil)
 	ur.EXPECT().
 		FindFollows(gomock.Any(), gomock.Any()).
 		Return([]domain.Follow{
@@ -91,19 +132,298 @@ func Test_commentService_GetFromArticle(t *testing.T) {
 			},
 		}, nil)
 
-	s := NewCommentService(cr, ar, ur, zap.NewNop())
+	s := NewCommentService(cr, ar, ur, CommentOptions{MaxDepth: 1}, zap.NewNop())
 	t.Run("댓글 조회 성공", func(t *testing.T) {
-		comments, err := s.GetFromArticle(1, "test-slug")
+		comments, page, err := s.GetFromArticle(1, "test-slug", ports.CommentListOptions{Layout: ports.LayoutFlat}, ports.Pageable{})
 
 		assert.NoError(t, err)
 		assert.Len(t, comments, 2)
+		assert.Equal(t, int64(2), page.Total)
 		assert.Equal(t, "test1", comments[0].AuthorUsername)
 		assert.Equal(t, false, comments[0].AuthorFollowing)
 		assert.Equal(t, "test2", comments[1].AuthorUsername)
 		assert.Equal(t, true, comments[1].AuthorFollowing)
 	})
 	t.Run("없는 글의 댓글 조회", func(t *testing.T) {
-		_, err := s.GetFromArticle(1, "null")
+		_, _, err := s.GetFromArticle(1, "null", ports.CommentListOptions{Layout: ports.LayoutFlat}, ports.Pageable{})
+
+		assert.ErrorIs(t, err, ports.ErrResourceNotFound)
+	})
+	t.Run("답글 순 정렬에 커서 사용", func(t *testing.T) {
+		_, _, err := s.GetFromArticle(1, "test-slug", ports.CommentListOptions{Sort: ports.CommentSortTop}, ports.Pageable{Cursor: &ports.Cursor{ID: 1}})
+
+		assert.ErrorIs(t, err, ports.ErrUnsupportedCursor)
+	})
+}
+
+func Test_commentService_GetFromArticle_Tree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock_ports.NewMockCommentRepository(ctrl)
+	ar := mock_ports.NewMockArticleRepository(ctrl)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+
+	ar.EXPECT().
+		FindBySlug(gomock.Eq("test-slug")).
+		Return(domain.Article{Model: gorm.Model{ID: 1}}, nil).
+		AnyTimes()
+	cr.EXPECT().
+		FindFromArticle(gomock.Eq(ports.CommentSearchConditions{Slug: "test-slug", TopLevel: true})).
+		Return([]domain.Comment{
+			{Model: gorm.Model{ID: 1}, Body: "root-1", Tombstoned: true, Author: domain.Author{ID: 1, Username: "test1"}},
+			{Model: gorm.Model{ID: 2}, Body: "root-2"},
+		}, ports.Page{Total: 2}, nil).
+		Times(2)
+	ur.EXPECT().
+		FindFollows(gomock.Any(), gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+
+	t.Run("답글을 트리로 조회", func(t *testing.T) {
+		gomock.InOrder(
+			cr.EXPECT().
+				CountReplies(gomock.Eq([]uint{1, 2})).
+				Return(map[uint]int{1: 2}, nil),
+			cr.EXPECT().
+				FindReplies(gomock.Eq([]uint{1, 2}), gomock.Eq(10)).
+				Return([]domain.Comment{
+					{Model: gorm.Model{ID: 3}, Body: "reply-1", ParentID: lo.ToPtr(uint(1)), Depth: 1},
+					{Model: gorm.Model{ID: 4}, Body: "reply-2", ParentID: lo.ToPtr(uint(1)), Depth: 1},
+				}, nil),
+			cr.EXPECT().
+				CountReplies(gomock.Eq([]uint{3, 4})).
+				Return(map[uint]int{4: 1}, nil),
+			cr.EXPECT().
+				FindReplies(gomock.Eq([]uint{3, 4}), gomock.Eq(8)).
+				Return([]domain.Comment{
+					{Model: gorm.Model{ID: 5}, Body: "reply-3", ParentID: lo.ToPtr(uint(4)), Depth: 2},
+				}, nil),
+			cr.EXPECT().
+				CountReplies(gomock.Eq([]uint{5})).
+				Return(map[uint]int{}, nil),
+		)
+
+		s := NewCommentService(cr, ar, ur, CommentOptions{MaxDepth: 2, MaxTreeReplies: 10}, zap.NewNop())
+		comments, page, err := s.GetFromArticle(1, "test-slug", ports.CommentListOptions{Layout: ports.LayoutTree}, ports.Pageable{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), page.Total)
+		assert.Len(t, comments, 2)
+		assert.Equal(t, domain.DeletedCommentBody, comments[0].Body)
+		assert.Equal(t, domain.DeletedCommentBody, comments[0].AuthorUsername)
+		assert.Len(t, comments[0].Replies, 2)
+		assert.Equal(t, 2, comments[0].RepliesCount)
+		assert.Equal(t, "reply-1", comments[0].Replies[0].Body)
+		assert.Len(t, comments[0].Replies[1].Replies, 1)
+		assert.Equal(t, 1, comments[0].Replies[1].RepliesCount)
+		assert.Equal(t, "reply-3", comments[0].Replies[1].Replies[0].Body)
+		assert.Empty(t, comments[1].Replies)
+	})
+	t.Run("답글 수 제한을 넘으면 스레드를 자름", func(t *testing.T) {
+		gomock.InOrder(
+			cr.EXPECT().
+				CountReplies(gomock.Eq([]uint{1, 2})).
+				Return(map[uint]int{1: 2, 2: 1}, nil),
+			cr.EXPECT().
+				FindReplies(gomock.Eq([]uint{1, 2}), gomock.Eq(2)).
+				Return([]domain.Comment{
+					{Model: gorm.Model{ID: 3}, Body: "reply-1", ParentID: lo.ToPtr(uint(1)), Depth: 1},
+					{Model: gorm.Model{ID: 4}, Body: "reply-2", ParentID: lo.ToPtr(uint(1)), Depth: 1},
+				}, nil),
+			cr.EXPECT().
+				CountReplies(gomock.Eq([]uint{3, 4})).
+				Return(map[uint]int{4: 1}, nil),
+		)
+
+		s := NewCommentService(cr, ar, ur, CommentOptions{MaxDepth: 2, MaxTreeReplies: 2}, zap.NewNop())
+		comments, _, err := s.GetFromArticle(1, "test-slug", ports.CommentListOptions{Layout: ports.LayoutTree}, ports.Pageable{})
+
+		assert.NoError(t, err)
+		assert.Len(t, comments[0].Replies, 2)
+		assert.Empty(t, comments[0].Replies[1].Replies)
+		assert.Equal(t, 1, comments[0].Replies[1].RepliesCount)
+		assert.Empty(t, comments[1].Replies)
+		assert.Equal(t, 1, comments[1].RepliesCount)
+	})
+	t.Run("답글 이어서 조회", func(t *testing.T) {
+		cr.EXPECT().
+			FindFromArticle(gomock.Eq(ports.CommentSearchConditions{Slug: "test-slug", ParentID: lo.ToPtr(uint(2))})).
+			Return([]domain.Comment{
+				{Model: gorm.Model{ID: 6}, Body: "reply-4", ParentID: lo.ToPtr(uint(2)), Depth: 1},
+			}, ports.Page{Total: 1}, nil)
+		cr.EXPECT().
+			CountReplies(gomock.Eq([]uint{6})).
+			Return(map[uint]int{}, nil)
+
+		s := NewCommentService(cr, ar, ur, CommentOptions{MaxDepth: 2, MaxTreeReplies: 2}, zap.NewNop())
+		comments, _, err := s.GetFromArticle(1, "test-slug", ports.CommentListOptions{Layout: ports.LayoutTree, ParentID: lo.ToPtr(uint(2))}, ports.Pageable{})
+
+		assert.NoError(t, err)
+		assert.Len(t, comments, 1)
+		assert.Equal(t, "reply-4", comments[0].Body)
+	})
+}
+
+func Test_commentService_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock_ports.NewMockCommentRepository(ctrl)
+	ar := mock_ports.NewMockArticleRepository(ctrl)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+
+	ar.EXPECT().
+		FindBySlug(gomock.Eq("test-slug")).
+		Return(domain.Article{Model: gorm.Model{ID: 1}}, nil).
+		AnyTimes()
+	cr.EXPECT().
+		FindByID(gomock.Eq(uint(1))).
+		Return(domain.Comment{Model: gorm.Model{ID: 1}, Body: "old body", ArticleID: 1, Author: domain.Author{ID: 1}}, nil).
+		AnyTimes()
+	cr.EXPECT().
+		FindByID(gomock.Eq(uint(2))).
+		Return(domain.Comment{Model: gorm.Model{ID: 2}, Body: "other article", ArticleID: 2, Author: domain.Author{ID: 1}}, nil)
+	cr.EXPECT().
+		SaveRevision(gomock.Eq(domain.CommentRevision{CommentID: 1, Body: "old body", EditorID: 1})).
+		DoAndReturn(func(revision domain.CommentRevision) (domain.CommentRevision, error) {
+			return revision, nil
+		})
+	cr.EXPECT().
+		Save(gomock.Any()).
+		DoAndReturn(func(comment domain.Comment) (domain.Comment, error) {
+			return comment, nil
+		})
+
+	s := NewCommentService(cr, ar, ur, CommentOptions{}, zap.NewNop())
+	t.Run("댓글 수정 성공", func(t *testing.T) {
+		comment, err := s.Update(domain.Actor{ID: 1, Role: domain.RoleUser}, "test-slug", 1, "new body")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "new body", comment.Body)
+		assert.True(t, comment.EditedAt.Valid)
+	})
+	t.Run("같은 내용으로 수정", func(t *testing.T) {
+		comment, err := s.Update(domain.Actor{ID: 1, Role: domain.RoleUser}, "test-slug", 1, "old body")
+
+		assert.NoError(t, err)
+		assert.False(t, comment.EditedAt.Valid)
+	})
+	t.Run("모더레이터의 댓글 수정", func(t *testing.T) {
+		_, err := s.Update(domain.Actor{ID: 2, Role: domain.RoleModerator}, "test-slug", 1, "new body")
+
+		assert.ErrorIs(t, err, ports.ErrNonOwnedContent)
+	})
+	t.Run("다른 글의 댓글 수정", func(t *testing.T) {
+		_, err := s.Update(domain.Actor{ID: 1, Role: domain.RoleUser}, "test-slug", 2, "new body")
+
+		assert.ErrorIs(t, err, ports.ErrResourceNotFound)
+	})
+}
+
+func Test_commentService_ListRevisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock_ports.NewMockCommentRepository(ctrl)
+	ar := mock_ports.NewMockArticleRepository(ctrl)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+
+	ar.EXPECT().
+		FindBySlug(gomock.Eq("test-slug")).
+		Return(domain.Article{Model: gorm.Model{ID: 1}}, nil)
+	cr.EXPECT().
+		FindByID(gomock.Eq(uint(1))).
+		Return(domain.Comment{Model: gorm.Model{ID: 1}, ArticleID: 1, Author: domain.Author{ID: 1}}, nil)
+	cr.EXPECT().
+		FindRevisions(gomock.Eq(uint(1))).
+		Return([]domain.CommentRevision{{CommentID: 1, Body: "old body"}}, nil)
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(1))).
+		Return(domain.User{Model: gorm.Model{ID: 1}, Role: domain.RoleUser}, nil).
+		AnyTimes()
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(2))).
+		Return(domain.User{Model: gorm.Model{ID: 2}, Role: domain.RoleModerator}, nil).
+		AnyTimes()
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(3))).
+		Return(domain.User{Model: gorm.Model{ID: 3}, Role: domain.RoleUser}, nil).
+		AnyTimes()
+
+	s := NewCommentService(cr, ar, ur, CommentOptions{}, zap.NewNop())
+	t.Run("모더레이터의 수정 이력 조회", func(t *testing.T) {
+		revisions, err := s.ListRevisions(domain.Actor{ID: 2, Role: domain.RoleModerator}, "test-slug", 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, revisions, 1)
+	})
+	t.Run("작성자의 수정 이력 조회", func(t *testing.T) {
+		_, err := s.ListRevisions(domain.Actor{ID: 1, Role: domain.RoleUser}, "test-slug", 1)
+
+		assert.ErrorIs(t, err, ports.ErrPermissionDenied)
+	})
+	t.Run("강등된 모더레이터의 수정 이력 조회", func(t *testing.T) {
+		_, err := s.ListRevisions(domain.Actor{ID: 3, Role: domain.RoleModerator}, "test-slug", 1)
+
+		assert.ErrorIs(t, err, ports.ErrPermissionDenied)
+	})
+}
+
+func Test_commentService_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock_ports.NewMockCommentRepository(ctrl)
+	ar := mock_ports.NewMockArticleRepository(ctrl)
+	ur := mock_ports.NewMockUserRepository(ctrl)
+
+	cr.EXPECT().
+		FindByID(gomock.Eq(uint(1))).
+		Return(domain.Comment{
+			Model:  gorm.Model{ID: 1},
+			Author: domain.Author{ID: 1},
+		}, nil).
+		AnyTimes()
+	cr.EXPECT().
+		FindByID(gomock.Eq(uint(2))).
+		Return(domain.Comment{}, gorm.ErrRecordNotFound)
+	cr.EXPECT().
+		FindByID(gomock.Eq(uint(3))).
+		Return(domain.Comment{Model: gorm.Model{ID: 3}, Tombstoned: true}, nil)
+	cr.EXPECT().
+		Delete(gomock.Eq(uint(1))).
+		Return(nil).
+		AnyTimes()
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(2))).
+		Return(domain.User{Model: gorm.Model{ID: 2}, Role: domain.RoleModerator}, nil).
+		AnyTimes()
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(3))).
+		Return(domain.User{Model: gorm.Model{ID: 3}, Role: domain.RoleUser}, nil).
+		AnyTimes()
+
+	s := NewCommentService(cr, ar, ur, CommentOptions{MaxDepth: 1}, zap.NewNop())
+	t.Run("댓글 삭제 성공", func(t *testing.T) {
+		err := s.Delete(domain.Actor{ID: 1, Role: domain.RoleUser}, 1)
+
+		assert.NoError(t, err)
+	})
+	t.Run("다른 유저의 댓글 삭제", func(t *testing.T) {
+		err := s.Delete(domain.Actor{ID: 3, Role: domain.RoleUser}, 1)
+
+		assert.ErrorIs(t, err, ports.ErrNonOwnedContent)
+	})
+	t.Run("모더레이터의 댓글 삭제", func(t *testing.T) {
+		err := s.Delete(domain.Actor{ID: 2, Role: domain.RoleModerator}, 1)
+
+		assert.NoError(t, err)
+	})
+	t.Run("강등된 모더레이터의 댓글 삭제", func(t *testing.T) {
+		err := s.Delete(domain.Actor{ID: 3, Role: domain.RoleModerator}, 1)
+
+		assert.ErrorIs(t, err, ports.ErrNonOwnedContent)
+	})
+	t.Run("없는 댓글", func(t *testing.T) {
+		err := s.Delete(domain.Actor{ID: 1, Role: domain.RoleUser}, 2)
+
+		assert.ErrorIs(t, err, ports.ErrResourceNotFound)
+	})
+	t.Run("이미 삭제된 댓글", func(t *testing.T) {
+		err := s.Delete(domain.Actor{ID: 1, Role: domain.RoleModerator}, 3)
 
 		assert.ErrorIs(t, err, ports.ErrResourceNotFound)
 	})
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"errors"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"time"
+)
+
+type tokenService struct {
+	tokenRepo ports.TokenRepository
+	logger    *zap.SugaredLogger
+}
+
+func NewTokenService(tokenRepo ports.TokenRepository, logger *zap.Logger) ports.TokenService {
+	return tokenService{
+		tokenRepo: tokenRepo,
+		logger:    logger.Sugar().Named("tokenService"),
+	}
+}
+
+func (s tokenService) WithTx(tx *gorm.DB) ports.TokenService {
+	s.tokenRepo = s.tokenRepo.WithTx(tx)
+	return s
+}
+
+func (s tokenService) CreatePersonalAccessToken(userID uint, name string, scopes []string, expiresAt *time.Time) (domain.PersonalAccessToken, error) {
+	if invalid, _ := lo.Difference(scopes, domain.Scopes); len(invalid) != 0 {
+		return domain.PersonalAccessToken{}, ports.ErrInvalidScope
+	}
+
+	token, err := domain.NewPersonalAccessToken(userID, name, scopes, expiresAt)
+	if err != nil {
+		s.logger.Errorw("failed to generate personal access token", "err", err)
+		return domain.PersonalAccessToken{}, ports.ErrInternal
+	}
+	saved, err := s.tokenRepo.SavePersonalAccessToken(token)
+	if err != nil {
+		s.logger.Errorw("failed to save personal access token", "user-id", userID, "err", err)
+		return domain.PersonalAccessToken{}, ports.ErrInternal
+	}
+	return saved, nil
+}
+
+func (s tokenService) ListPersonalAccessTokens(userID uint) ([]domain.PersonalAccessToken, error) {
+	tokens, err := s.tokenRepo.FindPersonalAccessTokens(userID)
+	if err != nil {
+		s.logger.Errorw("failed to find personal access tokens", "user-id", userID, "err", err)
+		return nil, ports.ErrInternal
+	}
+	return tokens, nil
+}
+
+func (s tokenService) RevokePersonalAccessToken(userID, id uint) error {
+	err := s.tokenRepo.DeletePersonalAccessToken(userID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ports.ErrResourceNotFound
+	} else if err != nil {
+		s.logger.Errorw("failed to delete personal access token", "user-id", userID, "id", id, "err", err)
+		return ports.ErrInternal
+	}
+	return nil
+}
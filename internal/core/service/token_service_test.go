@@ -0,0 +1,66 @@
+package service
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports/mock_ports"
+	"github.com/KumKeeHyun/gin-realworld/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strings"
+	"testing"
+)
+
+func Test_tokenService_CreatePersonalAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tr := mock_ports.NewMockTokenRepository(ctrl)
+
+	tr.EXPECT().
+		SavePersonalAccessToken(gomock.Any()).
+		DoAndReturn(func(token domain.PersonalAccessToken) (domain.PersonalAccessToken, error) {
+			assert.Equal(t, crypto.HashToken(token.Token), token.TokenHash)
+			token.ID = 1
+			return token, nil
+		})
+
+	s := NewTokenService(tr, zap.NewNop())
+	t.Run("토큰 생성 성공", func(t *testing.T) {
+		token, err := s.CreatePersonalAccessToken(1, "deploy", []string{domain.ScopeArticlesWrite, domain.ScopeArticlesWrite}, nil)
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(token.Token, domain.PersonalAccessTokenPrefix))
+		assert.Equal(t, []string{domain.ScopeArticlesWrite}, []string(token.Scopes))
+		assert.False(t, token.ExpiresAt.Valid)
+	})
+	t.Run("잘못된 scope", func(t *testing.T) {
+		_, err := s.CreatePersonalAccessToken(1, "deploy", []string{"admin"}, nil)
+
+		assert.ErrorIs(t, err, ports.ErrInvalidScope)
+	})
+}
+
+func Test_tokenService_RevokePersonalAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tr := mock_ports.NewMockTokenRepository(ctrl)
+
+	tr.EXPECT().
+		DeletePersonalAccessToken(gomock.Eq(uint(1)), gomock.Eq(uint(1))).
+		Return(nil)
+	tr.EXPECT().
+		DeletePersonalAccessToken(gomock.Eq(uint(2)), gomock.Eq(uint(1))).
+		Return(gorm.ErrRecordNotFound)
+
+	s := NewTokenService(tr, zap.NewNop())
+	t.Run("토큰 폐기 성공", func(t *testing.T) {
+		err := s.RevokePersonalAccessToken(1, 1)
+
+		assert.NoError(t, err)
+	})
+	t.Run("다른 사용자의 토큰", func(t *testing.T) {
+		err := s.RevokePersonalAccessToken(2, 1)
+
+		assert.ErrorIs(t, err, ports.ErrResourceNotFound)
+	})
+}
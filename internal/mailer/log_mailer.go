@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"encoding/json"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"go.uber.org/zap"
+	"os"
+	"sync"
+	"time"
+)
+
+// logMailer does not send mail, but appends it to file or logger for local and offline test.
+type logMailer struct {
+	path   string
+	mu     *sync.Mutex
+	logger *zap.SugaredLogger
+}
+
+func NewLogMailer(path string, logger *zap.Logger) ports.Mailer {
+	return logMailer{
+		path:   path,
+		mu:     &sync.Mutex{},
+		logger: logger.Sugar().Named("logMailer"),
+	}
+}
+
+type mailRecord struct {
+	SentAt  time.Time `json:"sentAt"`
+	To      string    `json:"to"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body"`
+}
+
+func (m logMailer) Send(mail ports.Mail) error {
+	if m.path == "" {
+		m.logger.Infow("send mail", "to", mail.To, "subject", mail.Subject, "body", mail.Body)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(mailRecord{
+		SentAt:  time.Now(),
+		To:      mail.To,
+		Subject: mail.Subject,
+		Body:    mail.Body,
+	})
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_logMailer_Send(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.log")
+	m := NewLogMailer(path, zap.NewNop())
+
+	err := m.Send(ports.Mail{To: "test1@example.com", Subject: "test1", Body: "test1 body"})
+	assert.NoError(t, err)
+	err = m.Send(ports.Mail{To: "test2@example.com", Subject: "test2", Body: "test2 body"})
+	assert.NoError(t, err)
+
+	f, err := os.Open(path)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	var records []mailRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record mailRecord
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	assert.Len(t, records, 2)
+	assert.Equal(t, "test1@example.com", records[0].To)
+	assert.Equal(t, "test2 body", records[1].Body)
+}
@@ -0,0 +1,46 @@
+package mailer
+
+import (
+	"fmt"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+type SmtpConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type smtpMailer struct {
+	cfg SmtpConfig
+}
+
+func NewSmtpMailer(cfg SmtpConfig) ports.Mailer {
+	return smtpMailer{cfg: cfg}
+}
+
+func (m smtpMailer) Send(mail ports.Mail) error {
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+	addr := net.JoinHostPort(m.cfg.Host, m.cfg.Port)
+	return smtp.SendMail(addr, auth, m.cfg.From, []string{mail.To}, message(m.cfg.From, mail))
+}
+
+func message(from string, mail ports.Mail) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mail.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
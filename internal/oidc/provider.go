@@ -0,0 +1,276 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/pkg/jwtutil"
+	"github.com/golang-jwt/jwt/v5"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrDiscovery      = errors.New("failed to discover provider configuration")
+	ErrTokenEndpoint  = errors.New("token endpoint returned error")
+	ErrInvalidIDToken = errors.New("invalid id token")
+)
+
+var validMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+type Config struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	Error       string `json:"error"`
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string `json:"nonce"`
+	AuthorizedParty   string `json:"azp"`
+	Email             string `json:"email"`
+	EmailVerified     any    `json:"email_verified"`
+	PreferredUsername string `json:"preferred_username"`
+}
+
+// provider is an OpenID Connect relying party of single issuer.
+// Issuer metadata and keys are fetched lazily, so that server can start while issuer is unavailable.
+type provider struct {
+	config Config
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *metadata
+	jwks     jwtutil.JWKSet
+}
+
+func NewProvider(config Config, client *http.Client) ports.IdentityProvider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	return &provider{
+		config: config,
+		client: client,
+	}
+}
+
+func (p *provider) Name() string {
+	return p.config.Name
+}
+
+func (p *provider) AuthCodeURL(state, nonce, codeVerifier string) (string, error) {
+	m, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(p.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {CodeChallenge(codeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+	sep := "?"
+	if strings.Contains(m.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return m.AuthorizationEndpoint + sep + query.Encode(), nil
+}
+
+func (p *provider) Exchange(code, codeVerifier, nonce string) (domain.ExternalIdentity, error) {
+	m, err := p.discover()
+	if err != nil {
+		return domain.ExternalIdentity{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"client_id":     {p.config.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequest(http.MethodPost, m.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return domain.ExternalIdentity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	var token tokenResponse
+	status, err := p.doJSON(req, &token)
+	if err != nil {
+		return domain.ExternalIdentity{}, err
+	}
+	if status != http.StatusOK || token.Error != "" {
+		return domain.ExternalIdentity{}, fmt.Errorf("%w: status %d, %s", ErrTokenEndpoint, status, token.Error)
+	}
+	if token.IDToken == "" {
+		return domain.ExternalIdentity{}, fmt.Errorf("%w: id_token is missing", ErrInvalidIDToken)
+	}
+
+	claims, err := p.verifyIDToken(m, token.IDToken)
+	if err != nil {
+		return domain.ExternalIdentity{}, err
+	}
+	if claims.Nonce != nonce {
+		return domain.ExternalIdentity{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	return domain.ExternalIdentity{
+		Provider:          p.config.Name,
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		EmailVerified:     emailVerified(claims.EmailVerified),
+		PreferredUsername: claims.PreferredUsername,
+	}, nil
+}
+
+func (p *provider) verifyIDToken(m metadata, raw string) (*idTokenClaims, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.publicKey(m, kid)
+	},
+		jwt.WithValidMethods(validMethods),
+		jwt.WithIssuer(m.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	if claims.Subject == "" || claims.ExpiresAt == nil {
+		return nil, fmt.Errorf("%w: sub and exp are required", ErrInvalidIDToken)
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID {
+		return nil, fmt.Errorf("%w: azp mismatch", ErrInvalidIDToken)
+	}
+	return claims, nil
+}
+
+// publicKey finds key by kid. Key set is fetched again once when kid is unknown, for key rotation of issuer.
+func (p *provider) publicKey(m metadata, kid string) (any, error) {
+	p.mu.Lock()
+	jwks := p.jwks
+	p.mu.Unlock()
+
+	jwk, found := findKey(jwks, kid)
+	if !found {
+		req, err := http.NewRequest(http.MethodGet, m.JwksURI, nil)
+		if err != nil {
+			return nil, err
+		}
+		if status, err := p.doJSON(req, &jwks); err != nil {
+			return nil, err
+		} else if status != http.StatusOK {
+			return nil, fmt.Errorf("%w: jwks status %d", ErrDiscovery, status)
+		}
+		p.mu.Lock()
+		p.jwks = jwks
+		p.mu.Unlock()
+
+		if jwk, found = findKey(jwks, kid); !found {
+			return nil, fmt.Errorf("%w: %s", jwtutil.ErrUnknownKeyID, kid)
+		}
+	}
+	return jwk.PublicKey()
+}
+
+// findKey accepts token without kid only if issuer has single key.
+func findKey(jwks jwtutil.JWKSet, kid string) (jwtutil.JWK, bool) {
+	if kid == "" && len(jwks.Keys) == 1 {
+		return jwks.Keys[0], true
+	}
+	return jwks.Find(kid)
+}
+
+func (p *provider) discover() (metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return *p.metadata, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(p.config.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return metadata{}, err
+	}
+	var m metadata
+	if status, err := p.doJSON(req, &m); err != nil {
+		return metadata{}, fmt.Errorf("%w: %v", ErrDiscovery, err)
+	} else if status != http.StatusOK {
+		return metadata{}, fmt.Errorf("%w: status %d", ErrDiscovery, status)
+	}
+	if m.Issuer != p.config.Issuer {
+		return metadata{}, fmt.Errorf("%w: issuer mismatch %s", ErrDiscovery, m.Issuer)
+	}
+	if m.AuthorizationEndpoint == "" || m.TokenEndpoint == "" || m.JwksURI == "" {
+		return metadata{}, fmt.Errorf("%w: missing endpoint", ErrDiscovery)
+	}
+	p.metadata = &m
+	return m, nil
+}
+
+func (p *provider) doJSON(req *http.Request, v any) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if err := json.Unmarshal(body, v); err != nil && resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, err
+	}
+	return resp.StatusCode, nil
+}
+
+// CodeChallenge returns S256 code challenge of PKCE.
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// emailVerified accepts both boolean and string, some issuers send "true".
+func emailVerified(v any) bool {
+	switch verified := v.(type) {
+	case bool:
+		return verified
+	case string:
+		return verified == "true"
+	default:
+		return false
+	}
+}
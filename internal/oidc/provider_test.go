@@ -0,0 +1,100 @@
+package oidc_test
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/oidc"
+	"github.com/KumKeeHyun/gin-realworld/internal/oidc/oidctest"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestProvider_Exchange(t *testing.T) {
+	srv := oidctest.NewServer("conduit", "conduit-secret")
+	defer srv.Close()
+
+	p := oidc.NewProvider(oidc.Config{
+		Name:         "test",
+		Issuer:       srv.Issuer(),
+		ClientID:     "conduit",
+		ClientSecret: "conduit-secret",
+		RedirectURL:  "http://localhost:8080/api/users/oauth/test/callback",
+	}, http.DefaultClient)
+
+	t.Run("login success", func(t *testing.T) {
+		srv.SetUser(oidctest.User{Subject: "sub-1", Email: "test@example.com", EmailVerified: true, PreferredUsername: "tester"})
+		authURL, err := p.AuthCodeURL("state-1", "nonce-1", "verifier-1-0123456789012345678901234567890123")
+		assert.NoError(t, err)
+		u, _ := url.Parse(authURL)
+		assert.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+		assert.Equal(t, "openid email profile", u.Query().Get("scope"))
+
+		code, state, err := srv.Authorize(authURL)
+		assert.NoError(t, err)
+		assert.Equal(t, "state-1", state)
+
+		identity, err := p.Exchange(code, "verifier-1-0123456789012345678901234567890123", "nonce-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "test", identity.Provider)
+		assert.Equal(t, "sub-1", identity.Subject)
+		assert.Equal(t, "test@example.com", identity.Email)
+		assert.True(t, identity.EmailVerified)
+		assert.Equal(t, "tester", identity.PreferredUsername)
+	})
+	t.Run("wrong code verifier", func(t *testing.T) {
+		authURL, _ := p.AuthCodeURL("state-2", "nonce-2", "verifier-2-0123456789012345678901234567890123")
+		code, _, err := srv.Authorize(authURL)
+		assert.NoError(t, err)
+
+		_, err = p.Exchange(code, "another-verifier-0123456789012345678901234567", "nonce-2")
+		assert.ErrorIs(t, err, oidc.ErrTokenEndpoint)
+	})
+	t.Run("code replay", func(t *testing.T) {
+		authURL, _ := p.AuthCodeURL("state-3", "nonce-3", "verifier-3-0123456789012345678901234567890123")
+		code, _, _ := srv.Authorize(authURL)
+
+		_, err := p.Exchange(code, "verifier-3-0123456789012345678901234567890123", "nonce-3")
+		assert.NoError(t, err)
+		_, err = p.Exchange(code, "verifier-3-0123456789012345678901234567890123", "nonce-3")
+		assert.ErrorIs(t, err, oidc.ErrTokenEndpoint)
+	})
+	t.Run("nonce mismatch", func(t *testing.T) {
+		authURL, _ := p.AuthCodeURL("state-4", "nonce-4", "verifier-4-0123456789012345678901234567890123")
+		code, _, _ := srv.Authorize(authURL)
+
+		_, err := p.Exchange(code, "verifier-4-0123456789012345678901234567890123", "another-nonce")
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+	t.Run("wrong audience", func(t *testing.T) {
+		srv.Claims = func(claims jwt.MapClaims) { claims["aud"] = "another-client" }
+		defer func() { srv.Claims = nil }()
+		authURL, _ := p.AuthCodeURL("state-5", "nonce-5", "verifier-5-0123456789012345678901234567890123")
+		code, _, _ := srv.Authorize(authURL)
+
+		_, err := p.Exchange(code, "verifier-5-0123456789012345678901234567890123", "nonce-5")
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+	t.Run("expired id token", func(t *testing.T) {
+		srv.Claims = func(claims jwt.MapClaims) { claims["exp"] = claims["iat"].(int64) - 60 }
+		defer func() { srv.Claims = nil }()
+		authURL, _ := p.AuthCodeURL("state-6", "nonce-6", "verifier-6-0123456789012345678901234567890123")
+		code, _, _ := srv.Authorize(authURL)
+
+		_, err := p.Exchange(code, "verifier-6-0123456789012345678901234567890123", "nonce-6")
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+}
+
+func TestProvider_IssuerMismatch(t *testing.T) {
+	srv := oidctest.NewServer("conduit", "")
+	defer srv.Close()
+
+	p := oidc.NewProvider(oidc.Config{
+		Name:     "test",
+		Issuer:   srv.Issuer() + "/another",
+		ClientID: "conduit",
+	}, http.DefaultClient)
+	_, err := p.AuthCodeURL("state", "nonce", "verifier")
+	assert.ErrorIs(t, err, oidc.ErrDiscovery)
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/lib/pq"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"sort"
+	"time"
 )
 
 type articleRepository struct {
@@ -26,7 +28,10 @@ func (r articleRepository) WithTx(tx *gorm.DB) ports.ArticleRepository {
 	return r
 }
 
+// Save creates article or updates it, updatedAt is set to now in both cases.
 func (r articleRepository) Save(article domain.Article) (domain.Article, error) {
+	// loaded article keeps its previous updatedAt, which is not replaced by gorm on upsert
+	article.UpdatedAt = time.Now()
 	err := r.db.Clauses(clause.OnConflict{
 		Columns: []clause.Column{{Name: "id"}},
 		DoUpdates: clause.AssignmentColumns([]string{
@@ -34,6 +39,10 @@ func (r articleRepository) Save(article domain.Article) (domain.Article, error)
 			"title",
 			"description",
 			"body",
+			"status",
+			"published_at",
+			"publish_at",
+			"updated_at",
 		}),
 	}).Create(&article).Error
 	return article, err
@@ -44,14 +53,39 @@ func (r articleRepository) FindBySlug(slug string) (domain.Article, error) {
 	return article, r.db.Where("slug = ?", slug).First(&article).Error
 }
 
-func (r articleRepository) FindBySearchConditions(cond ports.ArticleSearchConditions) ([]domain.Article, error) {
-	var ids []uint
-	tx := r.db.Model(&domain.Article{})
-	if cond.Tag != nil {
-		tx = tx.Where("tags @> ?", pq.Array([]string{*cond.Tag}))
+func (r articleRepository) FindByOldSlug(slug string) (domain.Article, error) {
+	var article domain.Article
+	return article, r.db.Joins("JOIN slug_histories ON slug_histories.article_id = articles.id").
+		Where("slug_histories.slug = ?", slug).
+		Where("slug_histories.deleted_at IS NULL").
+		First(&article).Error
+}
+
+func (r articleRepository) SaveSlugHistory(history domain.SlugHistory) (domain.SlugHistory, error) {
+	return history, r.db.Create(&history).Error
+}
+
+func (r articleRepository) DeleteSlugHistory(slug string) error {
+	return r.db.Unscoped().
+		Where("slug = ?", slug).
+		Delete(&domain.SlugHistory{}).Error
+}
+
+func (r articleRepository) FindBySearchConditions(cond ports.ArticleSearchConditions) ([]domain.Article, ports.Page, error) {
+	tx := r.db.Model(&domain.Article{}).
+		Where(r.db.Where("status = ?", domain.StatusPublished).Or("author_id = ?", cond.ReaderID))
+	if len(cond.Tags) > 0 {
+		if cond.TagMatch == ports.TagMatchAll {
+			tx = tx.Where("tags @> ?", pq.Array(cond.Tags))
+		} else {
+			tx = tx.Where("tags && ?", pq.Array(cond.Tags))
+		}
 	}
-	if cond.Author != nil {
-		tx = tx.Where("author_username = ?", *cond.Author)
+	if len(cond.ExcludeTags) > 0 {
+		tx = tx.Where("NOT (COALESCE(tags, '{}') && ?)", pq.Array(cond.ExcludeTags))
+	}
+	if len(cond.Authors) > 0 {
+		tx = tx.Where("author_username IN ?", cond.Authors)
 	}
 	if cond.Favorited != nil {
 		tx = tx.Where("id IN (?)", r.db.Model(&domain.Favorite{}).
@@ -60,32 +94,110 @@ func (r articleRepository) FindBySearchConditions(cond ports.ArticleSearchCondit
 				Select("id")).
 			Select("article_id"))
 	}
-	err := tx.Limit(cond.Limit).Offset(cond.Offset).Pluck("id", &ids).Error
-	if err != nil {
-		return nil, err
-	} else if len(ids) == 0 {
-		return nil, nil
+	if cond.CreatedAfter != nil {
+		tx = tx.Where("articles.created_at >= ?", *cond.CreatedAfter)
+	}
+	if cond.CreatedBefore != nil {
+		tx = tx.Where("articles.created_at < ?", *cond.CreatedBefore)
+	}
+	if cond.FollowedOnly {
+		tx = tx.Where("author_id IN (?)", r.db.Model(&domain.Follow{}).
+			Where("follower_id = ?", cond.ReaderID).
+			Select("following_id"))
+	}
+	if cond.Query != nil {
+		tx = tx.Joins("CROSS JOIN websearch_to_tsquery('english', ?) AS search_query", *cond.Query).
+			Where("search_vector @@ search_query")
+	}
+	var ids []uint
+	var page ports.Page
+	var err error
+	if cond.OrderedByCreation() {
+		ids, page, err = findPageByCreation(tx, "articles", cond.Sort != ports.SortOldest, cond.Pageable)
+	} else {
+		tx = orderBySort(tx, cond).Order("articles.created_at DESC, articles.id DESC")
+		ids, page, err = findPage(tx, "articles.id", cond.Pageable)
+	}
+	if err != nil || len(ids) == 0 {
+		return nil, page, err
 	}
 
 	var articles []domain.Article
-	return articles, r.db.Where("id in ?", ids).Find(&articles).Error
+	err = r.db.Where("id in ?", ids).Find(&articles).Error
+	return sortByIDs(articles, ids), page, err
 }
 
-func (r articleRepository) FindFeed(userID uint, pageable ports.Pageable) ([]domain.Article, error) {
-	var ids []uint
-	tx := r.db.Model(&domain.Article{})
-	tx.Where("author_id IN (?)", r.db.Model(&domain.Follow{}).
-		Where("follower_id = ?", userID).
-		Select("following_id"))
-	err := tx.Limit(pageable.Limit).Offset(pageable.Offset).Pluck("id", &ids).Error
-	if err != nil {
-		return nil, err
-	} else if len(ids) == 0 {
-		return nil, nil
+// orderBySort orders articles which are not ordered by creation.
+// Orders are plain strings with values bound in joins, since gorm drops expression order merged with others.
+func orderBySort(tx *gorm.DB, cond ports.ArticleSearchConditions) *gorm.DB {
+	favorites := "SELECT article_id, COUNT(*) AS total FROM favorites WHERE deleted_at IS NULL"
+	switch cond.Sort {
+	case ports.SortMostFavorited:
+		return tx.Joins("LEFT JOIN (" + favorites + " GROUP BY article_id) AS favorited ON favorited.article_id = articles.id").
+			Order("COALESCE(favorited.total, 0) DESC")
+	case ports.SortTrending:
+		return tx.Joins("LEFT JOIN ("+favorites+" AND created_at >= ? GROUP BY article_id) AS favorited ON favorited.article_id = articles.id",
+			cond.TrendingSince).
+			Order("COALESCE(favorited.total, 0) DESC")
+	default:
+		return tx.Order("ts_rank(search_vector, search_query) DESC")
+	}
+}
+
+// sortByIDs orders articles found by ids as ids are ordered.
+func sortByIDs(articles []domain.Article, ids []uint) []domain.Article {
+	order := make(map[uint]int, len(ids))
+	for i, id := range ids {
+		order[id] = i
+	}
+	sort.Slice(articles, func(i, j int) bool {
+		return order[articles[i].ID] < order[articles[j].ID]
+	})
+	return articles
+}
+
+func (r articleRepository) FindFeed(userID uint, pageable ports.Pageable) ([]domain.Article, ports.Page, error) {
+	tx := r.db.Model(&domain.Article{}).
+		Where("status = ?", domain.StatusPublished).
+		Where("author_id IN (?)", r.db.Model(&domain.Follow{}).
+			Where("follower_id = ?", userID).
+			Select("following_id"))
+	ids, page, err := findPageByCreation(tx, "articles", true, pageable)
+	if err != nil || len(ids) == 0 {
+		return nil, page, err
 	}
 
 	var articles []domain.Article
-	return articles, r.db.Where("id in ?", ids).Find(&articles).Error
+	err = r.db.Where("id in ?", ids).Find(&articles).Error
+	return sortByIDs(articles, ids), page, err
+}
+
+func (r articleRepository) FindDrafts(authorID uint, pageable ports.Pageable) ([]domain.Article, ports.Page, error) {
+	tx := r.db.Model(&domain.Article{}).
+		Where("author_id = ?", authorID).
+		Where("status = ?", domain.StatusDraft).
+		Order("updated_at DESC, id DESC")
+	ids, page, err := findPage(tx, "id", pageable)
+	if err != nil || len(ids) == 0 {
+		return nil, page, err
+	}
+
+	var articles []domain.Article
+	err = r.db.Where("id in ?", ids).Find(&articles).Error
+	return sortByIDs(articles, ids), page, err
+}
+
+// PublishDueArticles publishes articles whose publishAt has passed in a single statement.
+func (r articleRepository) PublishDueArticles(now time.Time) (int64, error) {
+	tx := r.db.Model(&domain.Article{}).
+		Where("status <> ?", domain.StatusPublished).
+		Where("publish_at <= ?", now).
+		Updates(map[string]any{
+			"status":       domain.StatusPublished,
+			"published_at": now,
+			"publish_at":   nil,
+		})
+	return tx.RowsAffected, tx.Error
 }
 
 func (r articleRepository) DeleteBySlug(slug string) error {
@@ -94,23 +206,26 @@ func (r articleRepository) DeleteBySlug(slug string) error {
 		Delete(&domain.Article{}).Error
 }
 
-func (r articleRepository) UpdateAuthorInfo(user domain.User) error {
-	return r.db.Model(&domain.Article{}).
-		Where("author_id = ?", user.ID).Updates(
-		map[string]any{
-			"author_username": user.Username,
-			"author_bio":      user.Bio,
-			"author_image":    user.Image,
-		},
-	).Error
-}
-
-func (r articleRepository) CreateFavorite(userID, articleID uint) (domain.Favorite, error) {
-	favorite := domain.Favorite{
-		UserID:    userID,
-		ArticleID: articleID,
-	}
-	return favorite, r.db.Create(&favorite).Error
+func (r articleRepository) CreateFavorite(userID, articleID uint) (int, error) {
+	var count int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		favorite := domain.Favorite{
+			UserID:    userID,
+			ArticleID: articleID,
+		}
+		created := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&favorite)
+		if created.Error != nil {
+			return created.Error
+		}
+		if created.RowsAffected > 0 {
+			err := addFavoritesCount(tx, articleID, 1)
+			if err != nil {
+				return err
+			}
+		}
+		return findFavoritesCount(tx, articleID, &count)
+	})
+	return count, err
 }
 
 func (r articleRepository) FindFavorite(userID uint, articleID uint) (domain.Favorite, error) {
@@ -127,19 +242,137 @@ func (r articleRepository) FindFavorites(userID uint, articleIDs []uint) ([]doma
 		Find(&favorites).Error
 }
 
-func (r articleRepository) DeleteFavorite(userID, articleID uint) error {
-	return r.db.
+func (r articleRepository) DeleteFavorite(userID, articleID uint) (int, error) {
+	var count int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		deleted := tx.Unscoped().
+			Where("user_id = ?", userID).
+			Where("article_id = ?", articleID).
+			Delete(&domain.Favorite{})
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		if deleted.RowsAffected > 0 {
+			err := addFavoritesCount(tx, articleID, -1)
+			if err != nil {
+				return err
+			}
+		}
+		return findFavoritesCount(tx, articleID, &count)
+	})
+	return count, err
+}
+
+// addFavoritesCount changes favorites count in a single statement, so that concurrent changes are not lost.
+func addFavoritesCount(tx *gorm.DB, articleID uint, delta int) error {
+	return tx.Model(&domain.Article{}).
+		Where("id = ?", articleID).
+		UpdateColumn("favorites_count", gorm.Expr("favorites_count + ?", delta)).Error
+}
+
+func findFavoritesCount(tx *gorm.DB, articleID uint, count *int) error {
+	return tx.Model(&domain.Article{}).
+		Where("id = ?", articleID).
+		Select("favorites_count").
+		Scan(count).Error
+}
+
+func (r articleRepository) FindByAuthor(authorID uint) ([]domain.Article, error) {
+	var articles []domain.Article
+	return articles, r.db.Where("author_id = ?", authorID).
+		Order("id").
+		Find(&articles).Error
+}
+
+// FindFavoritedArticles returns articles favorited by user.
+func (r articleRepository) FindFavoritedArticles(userID uint) ([]domain.Article, error) {
+	var articles []domain.Article
+	return articles, r.db.Where("id IN (?)", r.db.Model(&domain.Favorite{}).
 		Where("user_id = ?", userID).
-		Where("article_id = ?", articleID).
-		Delete(&domain.Favorite{}).Error
+		Select("article_id")).
+		Order("id").
+		Find(&articles).Error
+}
+
+// DeleteFavorites permanently deletes favorites of user, and uncounts them from favorited articles.
+func (r articleRepository) DeleteFavorites(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.Article{}).
+			Where("id IN (?)", tx.Model(&domain.Favorite{}).
+				Where("user_id = ?", userID).
+				Select("article_id")).
+			UpdateColumn("favorites_count", gorm.Expr("favorites_count - 1")).Error
+		if err != nil {
+			return err
+		}
+		return tx.Unscoped().
+			Where("user_id = ?", userID).
+			Delete(&domain.Favorite{}).Error
+	})
+}
+
+// ReconcileFavoritesCounts recomputes favorites count of all articles from favorites in a single statement.
+func (r articleRepository) ReconcileFavoritesCounts() (int64, error) {
+	favorites := "SELECT COUNT(*) FROM favorites WHERE favorites.article_id = articles.id AND favorites.deleted_at IS NULL"
+	tx := r.db.Exec("UPDATE articles SET favorites_count = (" + favorites + ") WHERE favorites_count <> (" + favorites + ")")
+	return tx.RowsAffected, tx.Error
 }
 
 // FindTags only local test purpose
-func (r articleRepository) FindTags() ([]string, error) {
+func (r articleRepository) FindTags(readerID uint) ([]string, error) {
 	var tags []string
 	//SELECT DISTINCT unnest(tags) FROM posts
 	err := r.db.Model(&domain.Article{}).
+		Where(r.db.Where("status = ?", domain.StatusPublished).Or("author_id = ?", readerID)).
 		Distinct("unnest(tags) as tag").
 		Pluck("tag", &tags).Error
 	return tags, err
 }
+
+func (r articleRepository) SaveRevision(revision domain.ArticleRevision) (domain.ArticleRevision, error) {
+	return revision, r.db.Create(&revision).Error
+}
+
+func (r articleRepository) FindRevisions(articleID uint) ([]domain.ArticleRevision, error) {
+	var revisions []domain.ArticleRevision
+	return revisions, r.db.Where("article_id = ?", articleID).
+		Order("number").
+		Find(&revisions).Error
+}
+
+func (r articleRepository) FindRevision(articleID uint, number int) (domain.ArticleRevision, error) {
+	var revision domain.ArticleRevision
+	return revision, r.db.Where("article_id = ?", articleID).
+		Where("number = ?", number).
+		First(&revision).Error
+}
+
+func (r articleRepository) FindLatestRevision(articleID uint) (domain.ArticleRevision, error) {
+	var revision domain.ArticleRevision
+	return revision, r.db.Where("article_id = ?", articleID).
+		Order("number DESC").
+		First(&revision).Error
+}
+
+func (r articleRepository) FindSnippets(query string, articleIDs []uint) (map[uint]string, error) {
+	var rows []struct {
+		ID      uint
+		Snippet string
+	}
+	err := r.db.Model(&domain.Article{}).
+		Select(`id, ts_headline('english', concat_ws(' ', title, description, body),
+			websearch_to_tsquery('english', ?),
+			'StartSel=' || ? || ', StopSel=' || ? || ', MaxWords=24, MinWords=8, MaxFragments=2, FragmentDelimiter=...') AS snippet`,
+			query, domain.SnippetStartMark, domain.SnippetStopMark).
+		Where("id IN ?", articleIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	snippets := make(map[uint]string, len(rows))
+	for _, row := range rows {
+		snippets[row.ID] = domain.HighlightSnippet(row.Snippet)
+	}
+	return snippets, nil
+}
@@ -125,17 +125,17 @@ func Test_articleRepository_FindBySearchConditions(t *testing.T) {
 				r := NewArticleRepository(tx)
 				tag1 := "tag1"
 				cond := ports.ArticleSearchConditions{
-					Tag:      &tag1,
+					Tags:     []string{tag1},
 					Pageable: ports.Pageable{Limit: 20, Offset: 0},
 				}
-				articles, err := r.FindBySearchConditions(cond)
+				articles, _, err := r.FindBySearchConditions(cond)
 				if err != nil {
 					return
 				}
 				assert.NoError(t, err)
 				assert.Len(t, articles, 2)
-				assert.Equal(t, "test1", articles[0].Slug)
-				assert.Equal(t, "test3", articles[1].Slug)
+				assert.Equal(t, "test3", articles[0].Slug)
+				assert.Equal(t, "test1", articles[1].Slug)
 			},
 		},
 	}
@@ -193,7 +193,7 @@ func Test_articleRepository_FindTags(t *testing.T) {
 			thenFn: func(t *testing.T, tx *gorm.DB) {
 				r := NewArticleRepository(tx)
 
-				tags, err := r.FindTags()
+				tags, err := r.FindTags(0)
 				assert.NoError(t, err)
 				sort.Strings(tags)
 				assert.Len(t, tags, 5)
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"gorm.io/gorm"
+	"sort"
+)
+
+// authorProjections are tables embedding domain.Author with `author_` prefix.
+// Tables of new models embedding domain.Author should be added here to be kept in sync with users.
+var authorProjections = []string{"articles", "comments"}
+
+// authorDrifted matches rows of projection whose author differs from joined users, NULL values are compared as equal.
+const authorDrifted = "(%[1]s.author_username IS DISTINCT FROM users.username OR %[1]s.author_bio IS DISTINCT FROM users.bio OR %[1]s.author_image IS DISTINCT FROM users.image)"
+
+type authorRepository struct {
+	db *gorm.DB
+}
+
+func NewAuthorRepository(db *gorm.DB) ports.AuthorRepository {
+	return authorRepository{
+		db: db,
+	}
+}
+
+func (r authorRepository) WithTx(tx *gorm.DB) ports.AuthorRepository {
+	if tx == nil {
+		return r
+	}
+	r.db = tx
+	return r
+}
+
+func (r authorRepository) UpdateAuthorInfo(user domain.User) error {
+	for _, table := range authorProjections {
+		err := r.db.Table(table).
+			Where("author_id = ?", user.ID).Updates(
+			map[string]any{
+				"author_username": user.Username,
+				"author_bio":      user.Bio,
+				"author_image":    user.Image,
+			},
+		).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindDriftedAuthorIDs compares projections with users including deleted ones, since they keep anonymized author.
+func (r authorRepository) FindDriftedAuthorIDs() ([]uint, error) {
+	drifted := make(map[uint]struct{})
+	for _, table := range authorProjections {
+		var ids []uint
+		err := r.db.Table(table).
+			Joins(fmt.Sprintf("JOIN users ON users.id = %s.author_id", table)).
+			Where(fmt.Sprintf(authorDrifted, table)).
+			Distinct().
+			Pluck(table+".author_id", &ids).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			drifted[id] = struct{}{}
+		}
+	}
+
+	ids := make([]uint, 0, len(drifted))
+	for id := range drifted {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return ids, nil
+}
+
+func (r authorRepository) RepairAuthorInfo(ids []uint) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var repaired int64
+	for _, table := range authorProjections {
+		fromUser := func(column string) any {
+			return gorm.Expr(fmt.Sprintf("(SELECT users.%s FROM users WHERE users.id = %s.author_id)", column, table))
+		}
+		tx := r.db.Table(table).
+			Where("author_id IN ?", ids).
+			Where(fmt.Sprintf("EXISTS (SELECT 1 FROM users WHERE users.id = %s.author_id)", table)).
+			Updates(map[string]any{
+				"author_username": fromUser("username"),
+				"author_bio":      fromUser("bio"),
+				"author_image":    fromUser("image"),
+			})
+		if tx.Error != nil {
+			return 0, tx.Error
+		}
+		repaired += tx.RowsAffected
+	}
+	return repaired, nil
+}
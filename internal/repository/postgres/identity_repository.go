@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"gorm.io/gorm"
+	"time"
+)
+
+type identityRepository struct {
+	db *gorm.DB
+}
+
+func NewIdentityRepository(db *gorm.DB) ports.IdentityRepository {
+	return identityRepository{
+		db: db,
+	}
+}
+
+func (r identityRepository) WithTx(tx *gorm.DB) ports.IdentityRepository {
+	if tx == nil {
+		return r
+	}
+	r.db = tx
+	return r
+}
+
+func (r identityRepository) SaveIdentity(identity domain.Identity) (domain.Identity, error) {
+	return identity, r.db.Create(&identity).Error
+}
+
+func (r identityRepository) FindIdentity(provider, subject string) (domain.Identity, error) {
+	var identity domain.Identity
+	return identity, r.db.Where("provider = ?", provider).
+		Where("subject = ?", subject).
+		First(&identity).Error
+}
+
+func (r identityRepository) SaveLoginState(state domain.LoginState) (domain.LoginState, error) {
+	return state, r.db.Create(&state).Error
+}
+
+func (r identityRepository) FindLoginState(stateHash string) (domain.LoginState, error) {
+	var state domain.LoginState
+	return state, r.db.Where("state_hash = ?", stateHash).First(&state).Error
+}
+
+// UseLoginState returns gorm.ErrRecordNotFound if state is already used,
+// so that one authorization response can not be replayed.
+func (r identityRepository) UseLoginState(id uint) error {
+	tx := r.db.Model(&domain.LoginState{}).
+		Where("id = ?", id).
+		Where("used_at IS NULL").
+		Update("used_at", time.Now())
+	if tx.Error != nil {
+		return tx.Error
+	} else if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteIdentities permanently deletes linked identities of user, so that they can be linked to another account.
+func (r identityRepository) DeleteIdentities(userID uint) error {
+	return r.db.Unscoped().
+		Where("user_id = ?", userID).
+		Delete(&domain.Identity{}).Error
+}
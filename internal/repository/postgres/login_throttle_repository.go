@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type loginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottleRepository(db *gorm.DB) ports.LoginThrottleRepository {
+	return loginThrottleRepository{
+		db: db,
+	}
+}
+
+func (r loginThrottleRepository) WithTx(tx *gorm.DB) ports.LoginThrottleRepository {
+	if tx == nil {
+		return r
+	}
+	r.db = tx
+	return r
+}
+
+func (r loginThrottleRepository) FindLoginThrottles(keys []string) ([]domain.LoginThrottle, error) {
+	var throttles []domain.LoginThrottle
+	return throttles, r.db.Where("key IN ?", keys).Find(&throttles).Error
+}
+
+// RecordLoginFailure increments failures in a single statement, so that concurrent failures are all counted.
+func (r loginThrottleRepository) RecordLoginFailure(key string, at time.Time) (domain.LoginThrottle, error) {
+	throttle := domain.LoginThrottle{Key: key, Failures: 1, LastFailureAt: at}
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures":        gorm.Expr("login_throttles.failures + 1"),
+			"last_failure_at": at,
+		}),
+	}).Create(&throttle).Error
+	if err != nil {
+		return domain.LoginThrottle{}, err
+	}
+	var recorded domain.LoginThrottle
+	return recorded, r.db.Where("key = ?", key).First(&recorded).Error
+}
+
+func (r loginThrottleRepository) LockLogin(key string, until time.Time) error {
+	return r.db.Model(&domain.LoginThrottle{}).
+		Where("key = ?", key).
+		Update("locked_until", until).Error
+}
+
+func (r loginThrottleRepository) DeleteLoginThrottle(key string) error {
+	return r.db.Where("key = ?", key).
+		Delete(&domain.LoginThrottle{}).Error
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+type pageRow struct {
+	ID    uint
+	Total int64
+}
+
+// findPage finds ids in the page and total count of rows matched by tx in one query, using window function.
+// Total is counted by another query only when the page is out of range. Zero limit finds all rows.
+func findPage(tx *gorm.DB, idColumn string, pageable ports.Pageable) ([]uint, ports.Page, error) {
+	tx = tx.Session(&gorm.Session{})
+	query := tx.Select(idColumn + " AS id, COUNT(*) OVER() AS total").Offset(pageable.Offset)
+	if pageable.Limit > 0 {
+		query = query.Limit(pageable.Limit)
+	}
+
+	var rows []pageRow
+	err := query.Scan(&rows).Error
+	if err != nil {
+		return nil, ports.Page{}, err
+	}
+	if len(rows) == 0 {
+		if pageable.Offset == 0 {
+			return nil, ports.Page{}, nil
+		}
+		var total int64
+		err := tx.Count(&total).Error
+		return nil, ports.Page{Total: total, HasPrev: total > 0}, err
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	total := rows[0].Total
+	return ids, ports.Page{
+		Total:   total,
+		HasPrev: pageable.Offset > 0,
+		HasNext: int64(pageable.Offset+len(ids)) < total,
+	}, nil
+}
+
+// findPageByCreation finds ids in the page ordered by (created_at, id) of table, the newest first if desc is set.
+// Rows are found by offset, or by keyset when pageable has cursor.
+func findPageByCreation(tx *gorm.DB, table string, desc bool, pageable ports.Pageable) ([]uint, ports.Page, error) {
+	cursor := pageable.Cursor
+	// rows before cursor are found in reverse order, and reversed back
+	backward := cursor != nil && cursor.Before
+	cmp, dir := ">", "ASC"
+	if desc != backward {
+		cmp, dir = "<", "DESC"
+	}
+	order := fmt.Sprintf("%[1]s.created_at %[2]s, %[1]s.id %[2]s", table, dir)
+	if cursor == nil {
+		return findPage(tx.Order(order), table+".id", pageable)
+	}
+
+	tx = tx.Session(&gorm.Session{})
+	var page ports.Page
+	if err := tx.Count(&page.Total).Error; err != nil {
+		return nil, page, err
+	}
+	query := tx.Where(fmt.Sprintf("(%[1]s.created_at %[2]s ? OR (%[1]s.created_at = ? AND %[1]s.id %[2]s ?))", table, cmp),
+		cursor.CreatedAt, cursor.CreatedAt, cursor.ID).
+		Order(order)
+	if pageable.Limit > 0 {
+		// one more row tells whether there is the next page
+		query = query.Limit(pageable.Limit + 1)
+	}
+
+	var ids []uint
+	if err := query.Pluck(table+".id", &ids).Error; err != nil {
+		return nil, page, err
+	}
+	more := pageable.Limit > 0 && len(ids) > pageable.Limit
+	if more {
+		ids = ids[:pageable.Limit]
+	}
+	if backward {
+		for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+			ids[i], ids[j] = ids[j], ids[i]
+		}
+		page.HasPrev, page.HasNext = more, true
+	} else {
+		page.HasPrev, page.HasNext = true, more
+	}
+	return ids, page, nil
+}
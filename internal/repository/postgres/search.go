@@ -0,0 +1,24 @@
+package postgres
+
+import "gorm.io/gorm"
+
+const searchVector = `setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+	setweight(to_tsvector('english', coalesce(description, '')), 'B') ||
+	setweight(to_tsvector('english', coalesce(body, '')), 'C')`
+
+// MigrateFullTextSearch adds generated tsvector column of articles with GIN index.
+// Tags are also indexed by GIN, which array operators of tag filters use.
+func MigrateFullTextSearch(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`ALTER TABLE articles ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (` + searchVector + `) STORED`).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING GIN (search_vector)`).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec(`CREATE INDEX IF NOT EXISTS idx_articles_tags ON articles USING GIN (tags)`).Error
+	})
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"gorm.io/gorm"
+	"time"
+)
+
+type tokenRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRepository(db *gorm.DB) ports.TokenRepository {
+	return tokenRepository{
+		db: db,
+	}
+}
+
+func (r tokenRepository) WithTx(tx *gorm.DB) ports.TokenRepository {
+	if tx == nil {
+		return r
+	}
+	r.db = tx
+	return r
+}
+
+func (r tokenRepository) SaveRefreshToken(token domain.RefreshToken) (domain.RefreshToken, error) {
+	return token, r.db.Create(&token).Error
+}
+
+func (r tokenRepository) FindRefreshToken(tokenHash string) (domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	return token, r.db.Where("token_hash = ?", tokenHash).First(&token).Error
+}
+
+// RevokeRefreshToken returns gorm.ErrRecordNotFound if token is already revoked,
+// so that only one of concurrent refresh requests can use the token.
+func (r tokenRepository) RevokeRefreshToken(id uint) error {
+	tx := r.db.Model(&domain.RefreshToken{}).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", time.Now())
+	if tx.Error != nil {
+		return tx.Error
+	} else if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r tokenRepository) RevokeRefreshTokens(userID uint) error {
+	return r.db.Model(&domain.RefreshToken{}).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r tokenRepository) CreateDeniedToken(jti string, expiresAt time.Time) (domain.DeniedToken, error) {
+	denied := domain.DeniedToken{
+		JTI:       jti,
+		ExpiresAt: expiresAt,
+	}
+	return denied, r.db.Create(&denied).Error
+}
+
+func (r tokenRepository) ExistsDeniedToken(jti string) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.DeniedToken{}).
+		Where("jti = ?", jti).
+		Count(&count).Error
+	return count != 0, err
+}
+
+// DeleteExpiredDeniedTokens deletes denied tokens permanently once they expired, since expired tokens are rejected anyway.
+func (r tokenRepository) DeleteExpiredDeniedTokens(now time.Time) (int64, error) {
+	tx := r.db.Unscoped().
+		Where("expires_at <= ?", now).
+		Delete(&domain.DeniedToken{})
+	return tx.RowsAffected, tx.Error
+}
+
+func (r tokenRepository) SaveOneTimeToken(token domain.OneTimeToken) (domain.OneTimeToken, error) {
+	return token, r.db.Create(&token).Error
+}
+
+func (r tokenRepository) FindOneTimeToken(purpose domain.TokenPurpose, tokenHash string) (domain.OneTimeToken, error) {
+	var token domain.OneTimeToken
+	return token, r.db.Where("purpose = ?", purpose).
+		Where("token_hash = ?", tokenHash).
+		First(&token).Error
+}
+
+// UseOneTimeToken returns gorm.ErrRecordNotFound if token is already used.
+func (r tokenRepository) UseOneTimeToken(id uint) error {
+	tx := r.db.Model(&domain.OneTimeToken{}).
+		Where("id = ?", id).
+		Where("used_at IS NULL").
+		Update("used_at", time.Now())
+	if tx.Error != nil {
+		return tx.Error
+	} else if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r tokenRepository) SavePersonalAccessToken(token domain.PersonalAccessToken) (domain.PersonalAccessToken, error) {
+	return token, r.db.Create(&token).Error
+}
+
+func (r tokenRepository) FindPersonalAccessToken(tokenHash string) (domain.PersonalAccessToken, error) {
+	var token domain.PersonalAccessToken
+	return token, r.db.Where("token_hash = ?", tokenHash).First(&token).Error
+}
+
+func (r tokenRepository) FindPersonalAccessTokens(userID uint) ([]domain.PersonalAccessToken, error) {
+	var tokens []domain.PersonalAccessToken
+	return tokens, r.db.Where("user_id = ?", userID).
+		Order("id").
+		Find(&tokens).Error
+}
+
+func (r tokenRepository) TouchPersonalAccessToken(id uint, usedAt time.Time) error {
+	return r.db.Model(&domain.PersonalAccessToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", usedAt).Error
+}
+
+func (r tokenRepository) DeletePersonalAccessToken(userID, id uint) error {
+	tx := r.db.Where("id = ?", id).
+		Where("user_id = ?", userID).
+		Delete(&domain.PersonalAccessToken{})
+	if tx.Error != nil {
+		return tx.Error
+	} else if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r tokenRepository) DeletePersonalAccessTokens(userID uint) error {
+	return r.db.Where("user_id = ?", userID).
+		Delete(&domain.PersonalAccessToken{}).Error
+}
@@ -35,6 +35,7 @@ func (r userRepository) Save(user domain.User) (domain.User, error) {
 			"password",
 			"bio",
 			"image",
+			"verified_at",
 		}),
 	}).Create(&user).Error
 	return user, err
@@ -64,6 +65,24 @@ func (r userRepository) FindByEmailOrUsername(email, username string) (domain.Us
 	return user, err
 }
 
+func (r userRepository) FindAll(pageable ports.Pageable) ([]domain.User, error) {
+	var users []domain.User
+	err := r.db.Order("id").Limit(pageable.Limit).Offset(pageable.Offset).Find(&users).Error
+	return users, err
+}
+
+func (r userRepository) UpdateRole(id uint, role domain.Role) error {
+	tx := r.db.Model(&domain.User{}).
+		Where("id = ?", id).
+		Update("role", role)
+	if tx.Error != nil {
+		return tx.Error
+	} else if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func (r userRepository) FindProfile(curUserID, profileUserID uint) (domain.Profile, error) {
 	result := struct {
 		ID        uint
@@ -117,3 +136,32 @@ func (r userRepository) DeleteFollow(followerID, followingID uint) error {
 		Where("following_id = ?", followingID).
 		Delete(&domain.Follow{}).Error
 }
+
+// FindFollowings returns users followed by follower.
+func (r userRepository) FindFollowings(followerID uint) ([]domain.User, error) {
+	var users []domain.User
+	return users, r.db.Where("id IN (?)", r.db.Model(&domain.Follow{}).
+		Where("follower_id = ?", followerID).
+		Select("following_id")).
+		Order("id").
+		Find(&users).Error
+}
+
+// DeleteFollows permanently deletes follows from and to user.
+func (r userRepository) DeleteFollows(userID uint) error {
+	return r.db.Unscoped().
+		Where("follower_id = ?", userID).
+		Or("following_id = ?", userID).
+		Delete(&domain.Follow{}).Error
+}
+
+func (r userRepository) Delete(id uint) error {
+	tx := r.db.Where("id = ?", id).
+		Delete(&domain.User{})
+	if tx.Error != nil {
+		return tx.Error
+	} else if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
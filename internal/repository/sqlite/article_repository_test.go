@@ -4,11 +4,14 @@
 package sqlite
 
 import (
+	"fmt"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 	"testing"
+	"time"
 )
 
 func Test_articleRepository_FindBySearchConditions(t *testing.T) {
@@ -69,29 +72,29 @@ func Test_articleRepository_FindBySearchConditions(t *testing.T) {
 					Favorited: &favorited,
 					Pageable:  ports.Pageable{Limit: 20, Offset: 0},
 				}
-				articles, err := ar.FindBySearchConditions(cond)
+				articles, _, err := ar.FindBySearchConditions(cond)
 				assert.NoError(t, err)
 				assert.Equal(t, 2, len(articles))
-				assert.Equal(t, "test1", articles[0].Slug)
-				assert.Equal(t, "test3", articles[1].Slug)
+				assert.Equal(t, "test3", articles[0].Slug)
+				assert.Equal(t, "test1", articles[1].Slug)
 
 				author := "test1"
 				cond = ports.ArticleSearchConditions{
-					Author:   &author,
+					Authors:  []string{author},
 					Pageable: ports.Pageable{Limit: 20, Offset: 0},
 				}
-				articles, err = ar.FindBySearchConditions(cond)
+				articles, _, err = ar.FindBySearchConditions(cond)
 				assert.NoError(t, err)
 				assert.Equal(t, 2, len(articles))
-				assert.Equal(t, "test1", articles[0].Slug)
-				assert.Equal(t, "test2", articles[1].Slug)
+				assert.Equal(t, "test2", articles[0].Slug)
+				assert.Equal(t, "test1", articles[1].Slug)
 
 				cond = ports.ArticleSearchConditions{
-					Author:    &author,
+					Authors:   []string{author},
 					Favorited: &favorited,
 					Pageable:  ports.Pageable{Limit: 20, Offset: 0},
 				}
-				articles, err = ar.FindBySearchConditions(cond)
+				articles, _, err = ar.FindBySearchConditions(cond)
 				assert.NoError(t, err)
 				assert.Equal(t, 1, len(articles))
 				assert.Equal(t, "test1", articles[0].Slug)
@@ -134,14 +137,14 @@ func Test_articleRepository_FindBySearchConditions(t *testing.T) {
 			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
 				tag := "tag1"
 				cond := ports.ArticleSearchConditions{
-					Tag:      &tag,
+					Tags:     []string{tag},
 					Pageable: ports.Pageable{Limit: 20, Offset: 0},
 				}
-				articles, err := ar.FindBySearchConditions(cond)
+				articles, _, err := ar.FindBySearchConditions(cond)
 				assert.NoError(t, err)
 				assert.Equal(t, 2, len(articles))
-				assert.Equal(t, "test1", articles[0].Slug)
-				assert.Equal(t, "test3", articles[1].Slug)
+				assert.Equal(t, "test3", articles[0].Slug)
+				assert.Equal(t, "test1", articles[1].Slug)
 
 			},
 		},
@@ -187,11 +190,89 @@ func Test_articleRepository_FindBySearchConditions(t *testing.T) {
 					Favorited: &favorited,
 					Pageable:  ports.Pageable{Limit: 20, Offset: 0},
 				}
-				articles, err := ar.FindBySearchConditions(cond)
+				articles, _, err := ar.FindBySearchConditions(cond)
 				assert.NoError(t, err)
 				assert.Equal(t, 0, len(articles))
 			},
 		},
+		{
+			name: "find non-published article only by author",
+			givenFn: func(tx *gorm.DB) error {
+				user1 := domain.User{Email: "test1@example.com", Username: "test1"}
+				user2 := domain.User{Email: "test2@example.com", Username: "test2"}
+				tx.Create(&user1)
+				tx.Create(&user2)
+				tx.Create(&domain.Article{
+					Slug:   "published",
+					Tags:   []string{"public"},
+					Author: domain.Author{ID: user1.ID, Username: user1.Username},
+				})
+				tx.Create(&domain.Article{
+					Slug:   "draft",
+					Tags:   []string{"secret"},
+					Status: domain.StatusDraft,
+					Author: domain.Author{ID: user1.ID, Username: user1.Username},
+				})
+				return tx.Create(&domain.Article{
+					Slug:   "unlisted",
+					Status: domain.StatusUnlisted,
+					Author: domain.Author{ID: user1.ID, Username: user1.Username},
+				}).Error
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				cond := ports.ArticleSearchConditions{
+					ReaderID: 2,
+					Pageable: ports.Pageable{Limit: 20, Offset: 0},
+				}
+				articles, _, err := ar.FindBySearchConditions(cond)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, len(articles))
+				assert.Equal(t, "published", articles[0].Slug)
+				tags, err := ar.FindTags(2)
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"public"}, tags)
+
+				cond.ReaderID = 1
+				articles, _, err = ar.FindBySearchConditions(cond)
+				assert.NoError(t, err)
+				assert.Equal(t, 3, len(articles))
+				tags, err = ar.FindTags(1)
+				assert.NoError(t, err)
+				assert.ElementsMatch(t, []string{"public", "secret"}, tags)
+
+				drafts, _, err := ar.FindDrafts(1, ports.Pageable{Limit: 20, Offset: 0})
+				assert.NoError(t, err)
+				assert.Equal(t, 1, len(drafts))
+				assert.Equal(t, "draft", drafts[0].Slug)
+			},
+		},
+		{
+			name: "find recently edited draft first",
+			givenFn: func(tx *gorm.DB) error {
+				now := time.Now()
+				older := domain.Article{Slug: "older", Status: domain.StatusDraft, Author: domain.Author{ID: 1}}
+				older.CreatedAt, older.UpdatedAt = now.Add(-2*time.Hour), now.Add(-2*time.Hour)
+				newer := domain.Article{Slug: "newer", Status: domain.StatusDraft, Author: domain.Author{ID: 1}}
+				newer.CreatedAt, newer.UpdatedAt = now.Add(-time.Hour), now.Add(-time.Hour)
+				tx.Create(&older)
+				return tx.Create(&newer).Error
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				drafts, _, err := ar.FindDrafts(1, ports.Pageable{Limit: 20})
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"newer", "older"}, []string{drafts[0].Slug, drafts[1].Slug})
+
+				older, err := ar.FindBySlug("older")
+				assert.NoError(t, err)
+				older.Title = "edited"
+				_, err = ar.Save(older)
+				assert.NoError(t, err)
+
+				drafts, _, err = ar.FindDrafts(1, ports.Pageable{Limit: 20})
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"older", "newer"}, []string{drafts[0].Slug, drafts[1].Slug})
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -269,3 +350,575 @@ func Test_articleRepository_FindFavorites(t *testing.T) {
 		})
 	}
 }
+
+func Test_articleRepository_Revisions(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository)
+	}{
+		{
+			name: "find revisions in order of number",
+			givenFn: func(tx *gorm.DB) error {
+				article := domain.Article{Slug: "test1", Title: "test1 title", Author: domain.Author{ID: 1}}
+				tx.Create(&article)
+				tx.Create(&domain.ArticleRevision{ArticleID: article.ID, Number: 2, Body: "second"})
+				tx.Create(&domain.ArticleRevision{ArticleID: article.ID, Number: 1, Body: "first"})
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				revisions, err := ar.FindRevisions(1)
+				assert.NoError(t, err)
+				assert.Equal(t, 2, len(revisions))
+				assert.Equal(t, "first", revisions[0].Body)
+				assert.Equal(t, "second", revisions[1].Body)
+
+				latest, err := ar.FindLatestRevision(1)
+				assert.NoError(t, err)
+				assert.Equal(t, 2, latest.Number)
+
+				revision, err := ar.FindRevision(1, 1)
+				assert.NoError(t, err)
+				assert.Equal(t, "first", revision.Body)
+
+				_, err = ar.FindRevision(1, 3)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "revision number is unique in article",
+			givenFn: func(tx *gorm.DB) error {
+				article := domain.Article{Slug: "test1", Title: "test1 title", Author: domain.Author{ID: 1}}
+				tx.Create(&article)
+				tx.Create(&domain.ArticleRevision{ArticleID: article.ID, Number: 1})
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				_, err := ar.SaveRevision(domain.ArticleRevision{ArticleID: 1, Number: 1})
+				assert.Error(t, err)
+
+				_, err = ar.FindLatestRevision(2)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.run(tt.thenFn)
+		})
+	}
+}
+
+func Test_articleRepository_FindByOldSlug(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository)
+	}{
+		{
+			name: "find article by old slug",
+			givenFn: func(tx *gorm.DB) error {
+				article := domain.Article{Slug: "test1", Title: "test1 title", Author: domain.Author{ID: 1}}
+				tx.Create(&article)
+				tx.Create(&domain.SlugHistory{Slug: "old1", ArticleID: article.ID})
+				tx.Create(&domain.SlugHistory{Slug: "old2", ArticleID: article.ID})
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				article, err := ar.FindByOldSlug("old1")
+				assert.NoError(t, err)
+				assert.Equal(t, "test1", article.Slug)
+
+				err = ar.DeleteSlugHistory("old2")
+				assert.NoError(t, err)
+				_, err = ar.FindByOldSlug("old2")
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+				_, err = ar.SaveSlugHistory(domain.SlugHistory{Slug: "old1", ArticleID: 2})
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "slug is unique",
+			givenFn: func(tx *gorm.DB) error {
+				return tx.Create(&domain.Article{Slug: "test1", Title: "test1 title", Author: domain.Author{ID: 1}}).Error
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				_, err := ar.Save(domain.Article{Slug: "test1", Title: "other title", Author: domain.Author{ID: 2}})
+				assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.run(tt.thenFn)
+		})
+	}
+}
+
+func Test_articleRepository_FullTextSearch(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository)
+	}{
+		{
+			name: "find articles ranked by title, description and body",
+			givenFn: func(tx *gorm.DB) error {
+				author := domain.Author{ID: 1, Username: "test1"}
+				tx.Create(&domain.Article{Slug: "test1", Title: "cooking", Description: "recipes", Body: "how to train dragons", Author: author})
+				tx.Create(&domain.Article{Slug: "test2", Title: "training dragons", Description: "dragon", Body: "dragons", Author: author})
+				tx.Create(&domain.Article{Slug: "test3", Title: "gardening", Description: "flowers", Body: "roses", Author: author})
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				query := "dragon"
+				articles, _, err := ar.FindBySearchConditions(ports.ArticleSearchConditions{
+					Query:    &query,
+					Pageable: ports.Pageable{Limit: 10},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, 2, len(articles))
+				assert.Equal(t, "test2", articles[0].Slug)
+				assert.Equal(t, "test1", articles[1].Slug)
+
+				snippets, err := ar.FindSnippets(query, []uint{articles[0].ID, articles[1].ID})
+				assert.NoError(t, err)
+				assert.Contains(t, snippets[articles[1].ID], "<mark>dragons</mark>")
+			},
+		},
+		{
+			name: "search index follows updated and deleted article",
+			givenFn: func(tx *gorm.DB) error {
+				author := domain.Author{ID: 1, Username: "test1"}
+				tx.Create(&domain.Article{Slug: "test1", Title: "cooking", Author: author})
+				tx.Create(&domain.Article{Slug: "test2", Title: "dragons", Author: author})
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				article, _ := ar.FindBySlug("test1")
+				article.Title = "cooking dragons"
+				_, err := ar.Save(article)
+				assert.NoError(t, err)
+				err = ar.DeleteBySlug("test2")
+				assert.NoError(t, err)
+
+				query := "dragons"
+				articles, _, err := ar.FindBySearchConditions(ports.ArticleSearchConditions{
+					Query:    &query,
+					Pageable: ports.Pageable{Limit: 10},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, 1, len(articles))
+				assert.Equal(t, "test1", articles[0].Slug)
+			},
+		},
+		{
+			name: "snippet escapes html of article",
+			givenFn: func(tx *gorm.DB) error {
+				tx.Create(&domain.Article{Slug: "test1", Title: "cooking", Body: "<script>alert('dragons')</script>", Author: domain.Author{ID: 1}})
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				article, _ := ar.FindBySlug("test1")
+
+				snippets, err := ar.FindSnippets("dragons", []uint{article.ID})
+				assert.NoError(t, err)
+				assert.NotContains(t, snippets[article.ID], "<script>")
+				assert.Contains(t, snippets[article.ID], "&lt;script&gt;")
+				assert.Contains(t, snippets[article.ID], "<mark>dragons</mark>")
+			},
+		},
+		{
+			name: "query syntax is matched literally",
+			givenFn: func(tx *gorm.DB) error {
+				tx.Create(&domain.Article{Slug: "test1", Title: "dragons AND (knights", Author: domain.Author{ID: 1}})
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				query := `"dragons" AND (knights NEAR`
+				articles, _, err := ar.FindBySearchConditions(ports.ArticleSearchConditions{
+					Query:    &query,
+					Pageable: ports.Pageable{Limit: 10},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, 0, len(articles))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.run(tt.thenFn)
+		})
+	}
+}
+
+func Test_articleRepository_TotalCount(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository)
+	}{
+		{
+			name: "count all matching articles, not only the page",
+			givenFn: func(tx *gorm.DB) error {
+				user1 := domain.User{Email: "test1@example.com", Username: "test1"}
+				user2 := domain.User{Email: "test2@example.com", Username: "test2"}
+				tx.Create(&user1)
+				tx.Create(&user2)
+				tx.Create(&domain.Follow{FollowerID: user2.ID, FollowingID: user1.ID})
+				author := domain.Author{ID: user1.ID, Username: user1.Username}
+				tx.Create(&domain.Article{Slug: "test1", Title: "dragons", Author: author})
+				tx.Create(&domain.Article{Slug: "test2", Title: "dragons", Author: author})
+				tx.Create(&domain.Article{Slug: "test3", Title: "knights", Author: author})
+				tx.Create(&domain.Article{Slug: "test4", Title: "dragons", Status: domain.StatusDraft, Author: author})
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				articles, page, err := ar.FindBySearchConditions(ports.ArticleSearchConditions{
+					Pageable: ports.Pageable{Limit: 2},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, 2, len(articles))
+				assert.Equal(t, int64(3), page.Total)
+
+				query := "dragons"
+				articles, page, err = ar.FindBySearchConditions(ports.ArticleSearchConditions{
+					Query:    &query,
+					Pageable: ports.Pageable{Limit: 1},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, 1, len(articles))
+				assert.Equal(t, int64(2), page.Total)
+
+				articles, page, err = ar.FindBySearchConditions(ports.ArticleSearchConditions{
+					Query:    &query,
+					Pageable: ports.Pageable{Limit: 1, Offset: 5},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, 0, len(articles))
+				assert.Equal(t, int64(2), page.Total)
+
+				articles, page, err = ar.FindFeed(2, ports.Pageable{Limit: 1, Offset: 1})
+				assert.NoError(t, err)
+				assert.Equal(t, 1, len(articles))
+				assert.Equal(t, int64(3), page.Total)
+
+				articles, page, err = ar.FindDrafts(1, ports.Pageable{Limit: 20})
+				assert.NoError(t, err)
+				assert.Equal(t, 1, len(articles))
+				assert.Equal(t, int64(1), page.Total)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.run(tt.thenFn)
+		})
+	}
+}
+
+func Test_articleRepository_Cursor(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository)
+	}{
+		{
+			name: "walk pages forward and backward without skips or duplicates",
+			givenFn: func(tx *gorm.DB) error {
+				user := domain.User{Email: "test1@example.com", Username: "test1"}
+				tx.Create(&user)
+				author := domain.Author{ID: user.ID, Username: user.Username}
+				createdAt := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+				for i := 1; i <= 5; i++ {
+					article := domain.Article{Slug: fmt.Sprintf("test%d", i), Author: author}
+					// articles 2 and 3 are created at the same time, which are ordered by id
+					article.CreatedAt = createdAt.Add(time.Duration(i/2) * time.Second)
+					tx.Create(&article)
+				}
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				slugs := func(articles []domain.Article) []string {
+					return lo.Map(articles, func(article domain.Article, index int) string { return article.Slug })
+				}
+				cursorOf := func(article domain.Article, before bool) *ports.Cursor {
+					return &ports.Cursor{CreatedAt: article.CreatedAt, ID: article.ID, Before: before}
+				}
+
+				first, page, err := ar.FindBySearchConditions(ports.ArticleSearchConditions{
+					Pageable: ports.Pageable{Limit: 2},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"test5", "test4"}, slugs(first))
+				assert.Equal(t, ports.Page{Total: 5, HasNext: true}, page)
+
+				second, page, err := ar.FindBySearchConditions(ports.ArticleSearchConditions{
+					Pageable: ports.Pageable{Limit: 2, Cursor: cursorOf(first[1], false)},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"test3", "test2"}, slugs(second))
+				assert.Equal(t, ports.Page{Total: 5, HasPrev: true, HasNext: true}, page)
+
+				third, page, err := ar.FindBySearchConditions(ports.ArticleSearchConditions{
+					Pageable: ports.Pageable{Limit: 2, Cursor: cursorOf(second[1], false)},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"test1"}, slugs(third))
+				assert.Equal(t, ports.Page{Total: 5, HasPrev: true}, page)
+
+				prev, page, err := ar.FindBySearchConditions(ports.ArticleSearchConditions{
+					Pageable: ports.Pageable{Limit: 2, Cursor: cursorOf(third[0], true)},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"test3", "test2"}, slugs(prev))
+				assert.Equal(t, ports.Page{Total: 5, HasPrev: true, HasNext: true}, page)
+
+				prev, page, err = ar.FindBySearchConditions(ports.ArticleSearchConditions{
+					Pageable: ports.Pageable{Limit: 2, Cursor: cursorOf(prev[0], true)},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"test5", "test4"}, slugs(prev))
+				assert.Equal(t, ports.Page{Total: 5, HasNext: true}, page)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.run(tt.thenFn)
+		})
+	}
+}
+
+func Test_articleRepository_Sort(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository)
+	}{
+		{
+			name: "order articles by sort, ties are broken by creation from the newest",
+			givenFn: func(tx *gorm.DB) error {
+				user1 := domain.User{Email: "test1@example.com", Username: "test1"}
+				user2 := domain.User{Email: "test2@example.com", Username: "test2"}
+				tx.Create(&user1)
+				tx.Create(&user2)
+				author := domain.Author{ID: user1.ID, Username: user1.Username}
+				article1 := domain.Article{Slug: "test1", Author: author}
+				article2 := domain.Article{Slug: "test2", Author: author}
+				article3 := domain.Article{Slug: "test3", Author: author}
+				tx.Create(&article1)
+				tx.Create(&article2)
+				tx.Create(&article3)
+				// article1 was favorited long ago, article2 recently
+				old := domain.Favorite{UserID: user1.ID, ArticleID: article1.ID}
+				old.CreatedAt = time.Now().Add(-30 * 24 * time.Hour)
+				tx.Create(&old)
+				tx.Create(&domain.Favorite{UserID: user2.ID, ArticleID: article1.ID})
+				tx.Create(&domain.Favorite{UserID: user1.ID, ArticleID: article2.ID})
+				// unfavorited article is not counted
+				unfavorited := domain.Favorite{UserID: user2.ID, ArticleID: article2.ID}
+				tx.Create(&unfavorited)
+				tx.Delete(&unfavorited)
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				tests := []struct {
+					sort     ports.ArticleSort
+					expected []string
+				}{
+					{"", []string{"test3", "test2", "test1"}},
+					{ports.SortRecent, []string{"test3", "test2", "test1"}},
+					{ports.SortOldest, []string{"test1", "test2", "test3"}},
+					{ports.SortMostFavorited, []string{"test1", "test2", "test3"}},
+					{ports.SortTrending, []string{"test2", "test1", "test3"}},
+				}
+				for _, tt := range tests {
+					articles, _, err := ar.FindBySearchConditions(ports.ArticleSearchConditions{
+						Sort:          tt.sort,
+						TrendingSince: time.Now().Add(-ports.TrendingWindow),
+						Pageable:      ports.Pageable{Limit: 20},
+					})
+					assert.NoError(t, err)
+					assert.Equal(t, tt.expected, lo.Map(articles, func(article domain.Article, index int) string {
+						return article.Slug
+					}), tt.sort)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.run(tt.thenFn)
+		})
+	}
+}
+
+func Test_articleRepository_Filters(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository)
+	}{
+		{
+			name: "combine tag, author, date and follow filters",
+			givenFn: func(tx *gorm.DB) error {
+				user1 := domain.User{Email: "test1@example.com", Username: "test1"}
+				user2 := domain.User{Email: "test2@example.com", Username: "test2"}
+				user3 := domain.User{Email: "test3@example.com", Username: "test3"}
+				tx.Create(&user1)
+				tx.Create(&user2)
+				tx.Create(&user3)
+				tx.Create(&domain.Follow{FollowerID: user3.ID, FollowingID: user2.ID})
+				createdAt := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+				articles := []domain.Article{
+					{Slug: "test1", Tags: []string{"go", "gin"}, Author: domain.Author{ID: user1.ID, Username: user1.Username}},
+					{Slug: "test2", Tags: []string{"golang"}, Author: domain.Author{ID: user1.ID, Username: user1.Username}},
+					{Slug: "test3", Tags: []string{"go", "tag, with comma"}, Author: domain.Author{ID: user2.ID, Username: user2.Username}},
+					{Slug: "test4", Author: domain.Author{ID: user2.ID, Username: user2.Username}},
+				}
+				for i := range articles {
+					articles[i].CreatedAt = createdAt.AddDate(0, 0, i)
+					tx.Create(&articles[i])
+				}
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				since := time.Date(2023, 7, 2, 0, 0, 0, 0, time.UTC)
+				until := time.Date(2023, 7, 4, 0, 0, 0, 0, time.UTC)
+				tests := []struct {
+					name     string
+					cond     ports.ArticleSearchConditions
+					expected []string
+				}{
+					{"tag is not matched by substring", ports.ArticleSearchConditions{Tags: []string{"go"}}, []string{"test3", "test1"}},
+					{"any of tags", ports.ArticleSearchConditions{Tags: []string{"gin", "golang"}}, []string{"test2", "test1"}},
+					{"all of tags", ports.ArticleSearchConditions{Tags: []string{"go", "gin"}, TagMatch: ports.TagMatchAll}, []string{"test1"}},
+					{"quoted tag", ports.ArticleSearchConditions{Tags: []string{"tag, with comma"}}, []string{"test3"}},
+					{"exclude tags", ports.ArticleSearchConditions{ExcludeTags: []string{"gin", "golang"}}, []string{"test4", "test3"}},
+					{"authors", ports.ArticleSearchConditions{Authors: []string{"test1", "test2"}, Tags: []string{"go"}}, []string{"test3", "test1"}},
+					{"created range", ports.ArticleSearchConditions{CreatedAfter: &since, CreatedBefore: &until}, []string{"test3", "test2"}},
+					{"followed only", ports.ArticleSearchConditions{FollowedOnly: true, ReaderID: 3}, []string{"test4", "test3"}},
+				}
+				for _, tt := range tests {
+					tt.cond.Limit = 20
+					articles, page, err := ar.FindBySearchConditions(tt.cond)
+					assert.NoError(t, err)
+					assert.Equal(t, tt.expected, lo.Map(articles, func(article domain.Article, index int) string {
+						return article.Slug
+					}), tt.name)
+					assert.Equal(t, int64(len(tt.expected)), page.Total, tt.name)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.run(tt.thenFn)
+		})
+	}
+}
+
+func Test_articleRepository_FavoritesCount(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository)
+	}{
+		{
+			name: "favorite and unfavorite idempotently with count",
+			givenFn: func(tx *gorm.DB) error {
+				user1 := domain.User{Email: "test1@example.com", Username: "test1"}
+				user2 := domain.User{Email: "test2@example.com", Username: "test2"}
+				tx.Create(&user1)
+				tx.Create(&user2)
+				tx.Create(&domain.Article{Slug: "test1", Author: domain.Author{ID: user1.ID, Username: user1.Username}})
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				count, err := ar.CreateFavorite(1, 1)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, count)
+				count, err = ar.CreateFavorite(1, 1)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, count)
+				count, err = ar.CreateFavorite(2, 1)
+				assert.NoError(t, err)
+				assert.Equal(t, 2, count)
+
+				count, err = ar.DeleteFavorite(1, 1)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, count)
+				count, err = ar.DeleteFavorite(1, 1)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, count)
+				// deleted favorite can be created again
+				count, err = ar.CreateFavorite(1, 1)
+				assert.NoError(t, err)
+				assert.Equal(t, 2, count)
+
+				assert.NoError(t, ar.DeleteFavorites(2))
+				article, _ := ar.FindBySlug("test1")
+				assert.Equal(t, 1, article.FavoritesCount)
+
+				reconciled, err := ar.ReconcileFavoritesCounts()
+				assert.NoError(t, err)
+				assert.Equal(t, int64(0), reconciled)
+			},
+		},
+		{
+			name: "reconcile drifted count",
+			givenFn: func(tx *gorm.DB) error {
+				user := domain.User{Email: "test1@example.com", Username: "test1"}
+				tx.Create(&user)
+				author := domain.Author{ID: user.ID, Username: user.Username}
+				article1 := domain.Article{Slug: "test1", FavoritesCount: 5, Author: author}
+				article2 := domain.Article{Slug: "test2", Author: author}
+				tx.Create(&article1)
+				tx.Create(&article2)
+				tx.Create(&domain.Favorite{UserID: user.ID, ArticleID: article2.ID})
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				reconciled, err := ar.ReconcileFavoritesCounts()
+				assert.NoError(t, err)
+				assert.Equal(t, int64(2), reconciled)
+
+				article1, _ := ar.FindBySlug("test1")
+				article2, _ := ar.FindBySlug("test2")
+				assert.Equal(t, 0, article1.FavoritesCount)
+				assert.Equal(t, 1, article2.FavoritesCount)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.run(tt.thenFn)
+		})
+	}
+}
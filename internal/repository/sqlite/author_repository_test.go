@@ -0,0 +1,144 @@
+//go:build sqlite
+// +build sqlite
+
+package sqlite
+
+import (
+	"database/sql"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func givenAuthors(tx *gorm.DB) error {
+	user1 := domain.User{Email: "test1@example.com", Username: "test1"}
+	user2 := domain.User{Email: "test2@example.com", Username: "test2"}
+	tx.Create(&user1)
+	tx.Create(&user2)
+	article := domain.Article{
+		Slug:   "test1",
+		Author: domain.Author{ID: user1.ID, Username: user1.Username},
+	}
+	tx.Create(&article)
+	tx.Create(&domain.Comment{
+		Body:      "test1 body",
+		ArticleID: article.ID,
+		Author:    domain.Author{ID: user1.ID, Username: user1.Username},
+	})
+	return tx.Create(&domain.Comment{
+		Body:      "test2 body",
+		ArticleID: article.ID,
+		Author:    domain.Author{ID: user2.ID, Username: user2.Username},
+	}).Error
+}
+
+func Test_authorRepository_UpdateAuthorInfo(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ar ports.ArticleRepository, cr ports.CommentRepository, aur ports.AuthorRepository)
+	}{
+		{
+			name:    "update author of articles and comments",
+			givenFn: givenAuthors,
+			thenFn: func(t *testing.T, ar ports.ArticleRepository, cr ports.CommentRepository, aur ports.AuthorRepository) {
+				image := sql.NullString{String: "https://example.com/1.png", Valid: true}
+				err := aur.UpdateAuthorInfo(domain.User{Model: gorm.Model{ID: 1}, Username: "renamed", Bio: "bio", Image: image})
+				assert.NoError(t, err)
+
+				articles, err := ar.FindByAuthor(1)
+				assert.NoError(t, err)
+				assert.Len(t, articles, 1)
+				assert.Equal(t, domain.Author{ID: 1, Username: "renamed", Bio: "bio", Image: image}, articles[0].Author)
+
+				comments, err := cr.FindByAuthor(1)
+				assert.NoError(t, err)
+				assert.Len(t, comments, 1)
+				assert.Equal(t, domain.Author{ID: 1, Username: "renamed", Bio: "bio", Image: image}, comments[0].Author)
+				comments, err = cr.FindByAuthor(2)
+				assert.NoError(t, err)
+				assert.Equal(t, "test2", comments[0].Author.Username)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.runWithAuthor(tt.thenFn)
+		})
+	}
+}
+
+func Test_authorRepository_Drift(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ar ports.ArticleRepository, cr ports.CommentRepository, aur ports.AuthorRepository)
+	}{
+		{
+			name:    "no drift",
+			givenFn: givenAuthors,
+			thenFn: func(t *testing.T, ar ports.ArticleRepository, cr ports.CommentRepository, aur ports.AuthorRepository) {
+				ids, err := aur.FindDriftedAuthorIDs()
+				assert.NoError(t, err)
+				assert.Empty(t, ids)
+			},
+		},
+		{
+			name: "find and repair drifted comments",
+			givenFn: func(tx *gorm.DB) error {
+				if err := givenAuthors(tx); err != nil {
+					return err
+				}
+				// profile was updated without comments
+				return tx.Model(&domain.User{}).Where("id = ?", 2).Updates(map[string]any{
+					"username": "renamed",
+					"image":    sql.NullString{String: "https://example.com/2.png", Valid: true},
+				}).Error
+			},
+			thenFn: func(t *testing.T, ar ports.ArticleRepository, cr ports.CommentRepository, aur ports.AuthorRepository) {
+				ids, err := aur.FindDriftedAuthorIDs()
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{2}, ids)
+
+				repaired, err := aur.RepairAuthorInfo(ids)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), repaired)
+
+				comments, err := cr.FindByAuthor(2)
+				assert.NoError(t, err)
+				assert.Equal(t, "renamed", comments[0].Author.Username)
+				assert.Equal(t, "https://example.com/2.png", comments[0].Author.Image.String)
+				ids, err = aur.FindDriftedAuthorIDs()
+				assert.NoError(t, err)
+				assert.Empty(t, ids)
+			},
+		},
+		{
+			name: "deleted user is compared with anonymized author",
+			givenFn: func(tx *gorm.DB) error {
+				if err := givenAuthors(tx); err != nil {
+					return err
+				}
+				return tx.Delete(&domain.User{}, 1).Error
+			},
+			thenFn: func(t *testing.T, ar ports.ArticleRepository, cr ports.CommentRepository, aur ports.AuthorRepository) {
+				ids, err := aur.FindDriftedAuthorIDs()
+				assert.NoError(t, err)
+				assert.Empty(t, ids)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.runWithAuthor(tt.thenFn)
+		})
+	}
+}
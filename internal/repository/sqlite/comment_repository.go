@@ -4,6 +4,7 @@ import (
 	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
 	"gorm.io/gorm"
+	"sort"
 )
 
 type commentRepository struct {
@@ -26,29 +27,153 @@ func (r commentRepository) Save(comment domain.Comment) (domain.Comment, error)
 	return comment, r.db.Save(&comment).Error
 }
 
-func (r commentRepository) FindFromArticle(slug string) ([]domain.Comment, error) {
-	var ids []int
+func (r commentRepository) FindByID(id uint) (domain.Comment, error) {
+	var comment domain.Comment
+	return comment, r.db.First(&comment, id).Error
+}
+
+// LockByID does not lock the row, since sqlite allows only one writing transaction.
+// Transaction which read comment before it is deleted fails to write instead.
+func (r commentRepository) LockByID(id uint) (domain.Comment, error) {
+	return r.FindByID(id)
+}
+
+// FindFromArticle finds comments of article from the oldest by default.
+func (r commentRepository) FindFromArticle(cond ports.CommentSearchConditions) ([]domain.Comment, ports.Page, error) {
+	tx := r.db.Model(&domain.Comment{}).
+		Where("comments.article_id = (?)", r.db.Model(&domain.Article{}).
+			Where("slug = ?", cond.Slug).
+			Select("id"))
+	if cond.TopLevel {
+		tx = tx.Where("comments.parent_id IS NULL")
+	}
+	if cond.ParentID != nil {
+		tx = tx.Where("comments.parent_id = ?", *cond.ParentID)
+	}
+
+	var ids []uint
+	var page ports.Page
+	var err error
+	if cond.Sort.OrderedByCreation() {
+		ids, page, err = findPageByCreation(tx, "comments", cond.Sort == ports.CommentSortNewest, cond.Pageable)
+	} else {
+		replies := "SELECT parent_id, COUNT(*) AS total FROM comments WHERE deleted_at IS NULL AND parent_id IS NOT NULL GROUP BY parent_id"
+		tx = tx.Joins("LEFT JOIN (" + replies + ") AS replied ON replied.parent_id = comments.id").
+			Order("COALESCE(replied.total, 0) DESC").
+			Order("comments.created_at, comments.id")
+		ids, page, err = findPage(tx, "comments.id", cond.Pageable)
+	}
+	if err != nil || len(ids) == 0 {
+		return nil, page, err
+	}
+
+	var comments []domain.Comment
+	err = r.db.Where("id IN ?", ids).Find(&comments).Error
+	return sortCommentsByIDs(comments, ids), page, err
+}
+
+// sortCommentsByIDs orders comments found by ids as ids are ordered.
+func sortCommentsByIDs(comments []domain.Comment, ids []uint) []domain.Comment {
+	order := make(map[uint]int, len(ids))
+	for i, id := range ids {
+		order[id] = i
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		return order[comments[i].ID] < order[comments[j].ID]
+	})
+	return comments
+}
+
+func (r commentRepository) FindReplies(parentIDs []uint, limit int) ([]domain.Comment, error) {
+	var comments []domain.Comment
+	if len(parentIDs) == 0 {
+		return comments, nil
+	}
+	return comments, r.db.Where("parent_id IN ?", parentIDs).
+		Order("created_at, id").
+		Limit(limit).
+		Find(&comments).Error
+}
+
+func (r commentRepository) CountReplies(parentIDs []uint) (map[uint]int, error) {
+	var rows []struct {
+		ParentID uint
+		Total    int
+	}
+	counts := make(map[uint]int)
+	if len(parentIDs) == 0 {
+		return counts, nil
+	}
 	err := r.db.Model(&domain.Comment{}).
-		Where("article_id = (?)", r.db.Model(&domain.Article{}).
-			Where("slug = ?", slug).
-			Select("id")).
-		Pluck("id", &ids).Error
+		Select("parent_id, COUNT(*) AS total").
+		Where("parent_id IN ?", parentIDs).
+		Group("parent_id").
+		Scan(&rows).Error
 	if err != nil {
 		return nil, err
 	}
+	for _, row := range rows {
+		counts[row.ParentID] = row.Total
+	}
+	return counts, nil
+}
 
+func (r commentRepository) FindByAuthor(authorID uint) ([]domain.Comment, error) {
 	var comments []domain.Comment
-	return comments, r.db.Where("id IN ?", ids).Find(&comments).Error
+	return comments, r.db.Where("author_id = ?", authorID).
+		Order("id").
+		Find(&comments).Error
 }
 
-func (r commentRepository) Delete(id, authorID uint) error {
-	tx := r.db.Where("id = ?", id).
-		Where("author_id = ?", authorID).
-		Delete(&domain.Comment{})
-	if tx.Error != nil {
-		return tx.Error
-	} else if tx.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
-	}
-	return nil
+func (r commentRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var comment domain.Comment
+		if err := tx.First(&comment, id).Error; err != nil {
+			return err
+		}
+		for {
+			var replies int64
+			err := tx.Model(&domain.Comment{}).
+				Where("parent_id = ?", comment.ID).
+				Count(&replies).Error
+			if err != nil {
+				return err
+			}
+			if replies > 0 {
+				if comment.Tombstoned {
+					return nil
+				}
+				return tx.Model(&comment).Updates(map[string]any{
+					"body":       "",
+					"tombstoned": true,
+				}).Error
+			}
+
+			if err := tx.Delete(&comment).Error; err != nil {
+				return err
+			}
+			if comment.ParentID == nil {
+				return nil
+			}
+			var parent domain.Comment
+			if err := tx.First(&parent, *comment.ParentID).Error; err != nil {
+				return err
+			}
+			if !parent.Tombstoned {
+				return nil
+			}
+			comment = parent
+		}
+	})
+}
+
+func (r commentRepository) SaveRevision(revision domain.CommentRevision) (domain.CommentRevision, error) {
+	return revision, r.db.Create(&revision).Error
+}
+
+func (r commentRepository) FindRevisions(commentID uint) ([]domain.CommentRevision, error) {
+	var revisions []domain.CommentRevision
+	return revisions, r.db.Where("comment_id = ?", commentID).
+		Order("id").
+		Find(&revisions).Error
 }
@@ -6,9 +6,11 @@ package sqlite
 import (
 	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 	"testing"
+	"time"
 )
 
 func Test_commentRepository_FindFromArticle(t *testing.T) {
@@ -59,13 +61,13 @@ func Test_commentRepository_FindFromArticle(t *testing.T) {
 				return nil
 			},
 			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository, cr ports.CommentRepository) {
-				comments, err := cr.FindFromArticle("test1")
+				comments, _, err := cr.FindFromArticle(ports.CommentSearchConditions{Slug: "test1"})
 				assert.NoError(t, err)
 				assert.Equal(t, 2, len(comments))
 				assert.Equal(t, "test1 body", comments[0].Body)
 				assert.Equal(t, "test2 body", comments[1].Body)
 
-				comments, err = cr.FindFromArticle("test2")
+				comments, _, err = cr.FindFromArticle(ports.CommentSearchConditions{Slug: "test2"})
 				assert.NoError(t, err)
 				assert.Equal(t, 0, len(comments))
 			},
@@ -118,11 +120,128 @@ func Test_commentRepository_Delete(t *testing.T) {
 				return nil
 			},
 			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository, cr ports.CommentRepository) {
-				err := cr.Delete(1, 1)
+				comment, err := cr.FindByID(2)
 				assert.NoError(t, err)
+				assert.Equal(t, "test2 body", comment.Body)
 
-				err = cr.Delete(2, 2)
+				err = cr.Delete(1)
+				assert.NoError(t, err)
+
+				err = cr.Delete(1)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.runWithComment(tt.thenFn)
+		})
+	}
+}
+
+// givenThread creates comments 1 <- 2 <- 3 and 4 on article "thread".
+func givenThread(tx *gorm.DB) error {
+	user := domain.User{Email: "test1@example.com", Username: "test1"}
+	tx.Create(&user)
+	article := domain.Article{
+		Slug:   "thread",
+		Author: domain.Author{ID: user.ID, Username: user.Username},
+	}
+	tx.Create(&article)
+
+	var parentID *uint
+	for depth, body := range []string{"root", "reply", "reply of reply"} {
+		comment := domain.Comment{
+			Body:      body,
+			ArticleID: article.ID,
+			ParentID:  parentID,
+			Depth:     depth,
+			Author:    domain.Author{ID: user.ID, Username: user.Username},
+		}
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+		parentID = &comment.ID
+	}
+	return tx.Create(&domain.Comment{
+		Body:      "another root",
+		ArticleID: article.ID,
+		Author:    domain.Author{ID: user.ID, Username: user.Username},
+	}).Error
+}
+
+func Test_commentRepository_Thread(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository, cr ports.CommentRepository)
+	}{
+		{
+			name:    "find top level comments and replies",
+			givenFn: givenThread,
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository, cr ports.CommentRepository) {
+				comments, page, err := cr.FindFromArticle(ports.CommentSearchConditions{Slug: "thread", TopLevel: true})
+				assert.NoError(t, err)
+				assert.Equal(t, int64(2), page.Total)
+				assert.Equal(t, []string{"root", "another root"}, bodies(comments))
+
+				replies, err := cr.FindReplies([]uint{comments[0].ID, comments[1].ID}, 10)
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"reply"}, bodies(replies))
+				assert.Equal(t, comments[0].ID, *replies[0].ParentID)
+
+				comments, _, err = cr.FindFromArticle(ports.CommentSearchConditions{Slug: "thread"})
+				assert.NoError(t, err)
+				assert.Len(t, comments, 4)
+			},
+		},
+		{
+			name:    "count replies and continue thread from a comment",
+			givenFn: givenThread,
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository, cr ports.CommentRepository) {
+				roots, _, err := cr.FindFromArticle(ports.CommentSearchConditions{Slug: "thread", TopLevel: true})
+				assert.NoError(t, err)
+
+				counts, err := cr.CountReplies([]uint{roots[0].ID, roots[1].ID})
+				assert.NoError(t, err)
+				assert.Equal(t, map[uint]int{roots[0].ID: 1}, counts)
+
+				replies, _, err := cr.FindFromArticle(ports.CommentSearchConditions{Slug: "thread", ParentID: &roots[0].ID})
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"reply"}, bodies(replies))
+
+				replies, err = cr.FindReplies([]uint{roots[0].ID}, 0)
+				assert.NoError(t, err)
+				assert.Empty(t, replies)
+			},
+		},
+		{
+			name:    "leave tombstone and prune it with the last reply",
+			givenFn: givenThread,
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository, cr ports.CommentRepository) {
+				err := cr.Delete(1)
+				assert.NoError(t, err)
+				root, err := cr.FindByID(1)
+				assert.NoError(t, err)
+				assert.True(t, root.Tombstoned)
+				assert.Empty(t, root.Body)
+
+				// reply still has a reply of its own
+				err = cr.Delete(2)
+				assert.NoError(t, err)
+				err = cr.Delete(3)
+				assert.NoError(t, err)
+
+				_, err = cr.FindByID(2)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				_, err = cr.FindByID(1)
 				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				comments, _, err := cr.FindFromArticle(ports.CommentSearchConditions{Slug: "thread"})
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"another root"}, bodies(comments))
 			},
 		},
 	}
@@ -133,3 +252,113 @@ func Test_commentRepository_Delete(t *testing.T) {
 		})
 	}
 }
+
+func Test_commentRepository_Sort(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository, cr ports.CommentRepository)
+	}{
+		{
+			name: "sort comments",
+			givenFn: func(tx *gorm.DB) error {
+				if err := givenThread(tx); err != nil {
+					return err
+				}
+				// another root is replied twice, root is replied once
+				for _, body := range []string{"reply of another root", "second reply of another root"} {
+					err := tx.Create(&domain.Comment{Body: body, ArticleID: 1, ParentID: lo.ToPtr(uint(4)), Depth: 1}).Error
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository, cr ports.CommentRepository) {
+				comments, page, err := cr.FindFromArticle(ports.CommentSearchConditions{Slug: "thread", TopLevel: true, Sort: ports.CommentSortNewest})
+				assert.NoError(t, err)
+				assert.Equal(t, int64(2), page.Total)
+				assert.Equal(t, []string{"another root", "root"}, bodies(comments))
+
+				comments, page, err = cr.FindFromArticle(ports.CommentSearchConditions{
+					Slug:     "thread",
+					Sort:     ports.CommentSortTop,
+					Pageable: ports.Pageable{Limit: 2},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, int64(6), page.Total)
+				assert.True(t, page.HasNext)
+				assert.Equal(t, []string{"another root", "root"}, bodies(comments))
+
+				comments, _, err = cr.FindFromArticle(ports.CommentSearchConditions{
+					Slug:     "thread",
+					Sort:     ports.CommentSortTop,
+					Pageable: ports.Pageable{Limit: 2, Offset: 2},
+				})
+				assert.NoError(t, err)
+				// root and reply are replied once, ties are ordered from the oldest
+				assert.Equal(t, []string{"reply", "reply of reply"}, bodies(comments))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.runWithComment(tt.thenFn)
+		})
+	}
+}
+
+func Test_commentRepository_Revisions(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository, cr ports.CommentRepository)
+	}{
+		{
+			name:    "keep previous bodies of edited comment",
+			givenFn: givenThread,
+			thenFn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository, cr ports.CommentRepository) {
+				comment, err := cr.FindByID(1)
+				assert.NoError(t, err)
+				for _, body := range []string{"edited", "edited again"} {
+					_, err = cr.SaveRevision(comment.Edit(body, 1, time.Now()))
+					assert.NoError(t, err)
+					comment, err = cr.Save(comment)
+					assert.NoError(t, err)
+				}
+
+				comment, err = cr.FindByID(1)
+				assert.NoError(t, err)
+				assert.Equal(t, "edited again", comment.Body)
+				assert.True(t, comment.EditedAt.Valid)
+				revisions, err := cr.FindRevisions(1)
+				assert.NoError(t, err)
+				assert.Len(t, revisions, 2)
+				assert.Equal(t, "root", revisions[0].Body)
+				assert.Equal(t, "edited", revisions[1].Body)
+				revisions, err = cr.FindRevisions(2)
+				assert.NoError(t, err)
+				assert.Empty(t, revisions)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.runWithComment(tt.thenFn)
+		})
+	}
+}
+
+func bodies(comments []domain.Comment) []string {
+	var bodies []string
+	for _, comment := range comments {
+		bodies = append(bodies, comment.Body)
+	}
+	return bodies
+}
@@ -0,0 +1,90 @@
+//go:build sqlite
+// +build sqlite
+
+package sqlite
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func Test_identityRepository_FindIdentity(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, ir ports.IdentityRepository)
+	}{
+		{
+			name: "find identity by provider and subject",
+			givenFn: func(tx *gorm.DB) error {
+				tx.Create(&domain.Identity{UserID: 1, Provider: "google", Subject: "sub-1"})
+				tx.Create(&domain.Identity{UserID: 2, Provider: "github", Subject: "sub-1"})
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ir ports.IdentityRepository) {
+				identity, err := ir.FindIdentity("github", "sub-1")
+				assert.NoError(t, err)
+				assert.Equal(t, uint(2), identity.UserID)
+
+				_, err = ir.FindIdentity("google", "sub-2")
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+				_, err = ir.SaveIdentity(domain.Identity{UserID: 3, Provider: "google", Subject: "sub-1"})
+				assert.Error(t, err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.runWithIdentity(tt.thenFn)
+		})
+	}
+}
+
+func Test_identityRepository_UseLoginState(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, ir ports.IdentityRepository)
+	}{
+		{
+			name: "use login state only once",
+			givenFn: func(tx *gorm.DB) error {
+				return tx.Create(&domain.LoginState{
+					Provider:  "google",
+					StateHash: "test-hash",
+					ExpiresAt: time.Now().Add(time.Minute),
+				}).Error
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, ir ports.IdentityRepository) {
+				state, err := ir.FindLoginState("test-hash")
+				assert.NoError(t, err)
+				assert.True(t, state.Usable())
+
+				err = ir.UseLoginState(state.ID)
+				assert.NoError(t, err)
+				err = ir.UseLoginState(state.ID)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+				state, err = ir.FindLoginState("test-hash")
+				assert.NoError(t, err)
+				assert.False(t, state.Usable())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.runWithIdentity(tt.thenFn)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package sqlite
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type leaseRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaseRepository(db *gorm.DB) ports.LeaseRepository {
+	return leaseRepository{
+		db: db,
+	}
+}
+
+func (r leaseRepository) WithTx(tx *gorm.DB) ports.LeaseRepository {
+	if tx == nil {
+		return r
+	}
+	r.db = tx
+	return r
+}
+
+// AcquireLease takes lease if it is free, expired or already held by holder, in a single statement.
+// It returns false if another holder has the lease.
+func (r leaseRepository) AcquireLease(name, holder string, now, until time.Time) (bool, error) {
+	lease := domain.Lease{Name: name, Holder: holder, ExpiresAt: until}
+	tx := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"holder", "expires_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			gorm.Expr("leases.holder = ? OR leases.expires_at < ?", holder, now),
+		}},
+	}).Create(&lease)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected == 1, nil
+}
@@ -0,0 +1,139 @@
+//go:build sqlite
+// +build sqlite
+
+package sqlite
+
+import (
+	"database/sql"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func Test_leaseRepository_AcquireLease(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ar ports.ArticleRepository, lr ports.LeaseRepository)
+	}{
+		{
+			name: "only one holder acquires lease until it expires",
+			givenFn: func(tx *gorm.DB) error {
+				return nil
+			},
+			thenFn: func(t *testing.T, ar ports.ArticleRepository, lr ports.LeaseRepository) {
+				now := time.Now()
+				acquired, err := lr.AcquireLease("job", "replica-1", now, now.Add(time.Minute))
+				assert.NoError(t, err)
+				assert.True(t, acquired)
+
+				acquired, err = lr.AcquireLease("job", "replica-2", now.Add(30*time.Second), now.Add(90*time.Second))
+				assert.NoError(t, err)
+				assert.False(t, acquired)
+
+				acquired, err = lr.AcquireLease("job", "replica-1", now.Add(30*time.Second), now.Add(90*time.Second))
+				assert.NoError(t, err)
+				assert.True(t, acquired)
+
+				acquired, err = lr.AcquireLease("job", "replica-2", now.Add(2*time.Minute), now.Add(3*time.Minute))
+				assert.NoError(t, err)
+				assert.True(t, acquired)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.runWithLease(tt.thenFn)
+		})
+	}
+}
+
+func Test_articleRepository_PublishDueArticles(t *testing.T) {
+	f := newSqliteFixture(t)
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ar ports.ArticleRepository, lr ports.LeaseRepository)
+	}{
+		{
+			name: "publish only due articles",
+			givenFn: func(tx *gorm.DB) error {
+				tx.Create(&domain.Article{
+					Slug:      "due",
+					Status:    domain.StatusDraft,
+					PublishAt: sql.NullTime{Time: now.Add(-time.Minute), Valid: true},
+				})
+				tx.Create(&domain.Article{
+					Slug:      "later",
+					Status:    domain.StatusDraft,
+					PublishAt: sql.NullTime{Time: now.Add(time.Hour), Valid: true},
+				})
+				return tx.Create(&domain.Article{
+					Slug:   "unscheduled",
+					Status: domain.StatusDraft,
+				}).Error
+			},
+			thenFn: func(t *testing.T, ar ports.ArticleRepository, lr ports.LeaseRepository) {
+				published, err := ar.PublishDueArticles(now)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), published)
+
+				article, err := ar.FindBySlug("due")
+				assert.NoError(t, err)
+				assert.Equal(t, domain.StatusPublished, article.Status)
+				assert.True(t, article.PublishedAt.Valid)
+				assert.False(t, article.PublishAt.Valid)
+				article, err = ar.FindBySlug("later")
+				assert.NoError(t, err)
+				assert.Equal(t, domain.StatusDraft, article.Status)
+
+				published, err = ar.PublishDueArticles(now)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(0), published)
+			},
+		},
+		{
+			name: "compare publishAt and now given in different zones",
+			givenFn: func(tx *gorm.DB) error {
+				seoul := time.FixedZone("KST", 9*60*60)
+				tx.Create(&domain.Article{
+					Slug:      "due",
+					Status:    domain.StatusDraft,
+					PublishAt: sql.NullTime{Time: now.Add(-time.Minute).In(seoul).Local(), Valid: true},
+				})
+				return tx.Create(&domain.Article{
+					Slug:      "later",
+					Status:    domain.StatusDraft,
+					PublishAt: sql.NullTime{Time: now.Add(time.Minute).In(seoul).Local(), Valid: true},
+				}).Error
+			},
+			thenFn: func(t *testing.T, ar ports.ArticleRepository, lr ports.LeaseRepository) {
+				newYork := time.FixedZone("EST", -5*60*60)
+				published, err := ar.PublishDueArticles(now.In(newYork))
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), published)
+
+				article, err := ar.FindBySlug("due")
+				assert.NoError(t, err)
+				assert.Equal(t, domain.StatusPublished, article.Status)
+				article, err = ar.FindBySlug("later")
+				assert.NoError(t, err)
+				assert.Equal(t, domain.StatusDraft, article.Status)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.runWithLease(tt.thenFn)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+//go:build sqlite
+// +build sqlite
+
+package sqlite
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func Test_loginThrottleRepository_RecordLoginFailure(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, lr ports.LoginThrottleRepository)
+	}{
+		{
+			name: "count failures of each key",
+			givenFn: func(tx *gorm.DB) error {
+				return nil
+			},
+			thenFn: func(t *testing.T, lr ports.LoginThrottleRepository) {
+				now := time.Now()
+				for i := 1; i <= 3; i++ {
+					throttle, err := lr.RecordLoginFailure(domain.AccountLoginKey("test@example.com"), now)
+					assert.NoError(t, err)
+					assert.Equal(t, i, throttle.Failures)
+				}
+				throttle, err := lr.RecordLoginFailure(domain.IPLoginKey("127.0.0.1"), now)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, throttle.Failures)
+
+				throttles, err := lr.FindLoginThrottles([]string{domain.AccountLoginKey("test@example.com"), domain.IPLoginKey("127.0.0.1")})
+				assert.NoError(t, err)
+				assert.Len(t, throttles, 2)
+			},
+		},
+		{
+			name: "lock and reset",
+			givenFn: func(tx *gorm.DB) error {
+				return tx.Create(&domain.LoginThrottle{Key: domain.AccountLoginKey("test@example.com"), Failures: 5}).Error
+			},
+			thenFn: func(t *testing.T, lr ports.LoginThrottleRepository) {
+				key := domain.AccountLoginKey("test@example.com")
+				assert.NoError(t, lr.LockLogin(key, time.Now().Add(time.Minute)))
+
+				throttles, err := lr.FindLoginThrottles([]string{key})
+				assert.NoError(t, err)
+				assert.True(t, throttles[0].Locked(time.Now()))
+
+				assert.NoError(t, lr.DeleteLoginThrottle(key))
+				throttles, err = lr.FindLoginThrottles([]string{key})
+				assert.NoError(t, err)
+				assert.Empty(t, throttles)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.runWithLoginThrottle(tt.thenFn)
+		})
+	}
+}
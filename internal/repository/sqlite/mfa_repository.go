@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"gorm.io/gorm"
+	"time"
+)
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) ports.MFARepository {
+	return mfaRepository{
+		db: db,
+	}
+}
+
+func (r mfaRepository) WithTx(tx *gorm.DB) ports.MFARepository {
+	if tx == nil {
+		return r
+	}
+	r.db = tx
+	return r
+}
+
+func (r mfaRepository) SaveTwoFactor(twoFactor domain.TwoFactor) (domain.TwoFactor, error) {
+	return twoFactor, r.db.Save(&twoFactor).Error
+}
+
+func (r mfaRepository) FindTwoFactor(userID uint) (domain.TwoFactor, error) {
+	var twoFactor domain.TwoFactor
+	return twoFactor, r.db.Where("user_id = ?", userID).First(&twoFactor).Error
+}
+
+// UseTwoFactorStep returns gorm.ErrRecordNotFound if the step or later one is already used,
+// so that a code can not be replayed.
+func (r mfaRepository) UseTwoFactorStep(id uint, step int64) error {
+	tx := r.db.Model(&domain.TwoFactor{}).
+		Where("id = ?", id).
+		Where("last_used_step < ?", step).
+		Update("last_used_step", step)
+	if tx.Error != nil {
+		return tx.Error
+	} else if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteTwoFactor deletes setting and recovery codes permanently, so that user can enroll again.
+func (r mfaRepository) DeleteTwoFactor(userID uint) error {
+	err := r.db.Unscoped().
+		Where("user_id = ?", userID).
+		Delete(&domain.TwoFactor{}).Error
+	if err != nil {
+		return err
+	}
+	return r.db.Unscoped().
+		Where("user_id = ?", userID).
+		Delete(&domain.RecoveryCode{}).Error
+}
+
+func (r mfaRepository) ReplaceRecoveryCodes(userID uint, codes []domain.RecoveryCode) error {
+	err := r.db.Unscoped().
+		Where("user_id = ?", userID).
+		Delete(&domain.RecoveryCode{}).Error
+	if err != nil {
+		return err
+	}
+	return r.db.Create(&codes).Error
+}
+
+// UseRecoveryCode returns gorm.ErrRecordNotFound if code does not exist or is already used.
+func (r mfaRepository) UseRecoveryCode(userID uint, codeHash string) error {
+	tx := r.db.Model(&domain.RecoveryCode{}).
+		Where("user_id = ?", userID).
+		Where("code_hash = ?", codeHash).
+		Where("used_at IS NULL").
+		Update("used_at", time.Now())
+	if tx.Error != nil {
+		return tx.Error
+	} else if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
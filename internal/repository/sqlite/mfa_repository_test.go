@@ -0,0 +1,91 @@
+//go:build sqlite
+// +build sqlite
+
+package sqlite
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func Test_mfaRepository_UseTwoFactorStep(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, mr ports.MFARepository)
+	}{
+		{
+			name: "use totp step only once",
+			givenFn: func(tx *gorm.DB) error {
+				return tx.Create(&domain.TwoFactor{Model: gorm.Model{ID: 1}, UserID: 1, Secret: "secret", LastUsedStep: 10}).Error
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, mr ports.MFARepository) {
+				assert.NoError(t, mr.UseTwoFactorStep(1, 11))
+				assert.ErrorIs(t, mr.UseTwoFactorStep(1, 11), gorm.ErrRecordNotFound)
+				assert.ErrorIs(t, mr.UseTwoFactorStep(1, 9), gorm.ErrRecordNotFound)
+
+				twoFactor, err := mr.FindTwoFactor(1)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(11), twoFactor.LastUsedStep)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.runWithMFA(tt.thenFn)
+		})
+	}
+}
+
+func Test_mfaRepository_RecoveryCode(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, mr ports.MFARepository)
+	}{
+		{
+			name: "use recovery code only once",
+			givenFn: func(tx *gorm.DB) error {
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, mr ports.MFARepository) {
+				codes, recoveryCodes, err := domain.NewRecoveryCodes(1)
+				assert.NoError(t, err)
+				assert.NoError(t, mr.ReplaceRecoveryCodes(1, recoveryCodes))
+
+				assert.NoError(t, mr.UseRecoveryCode(1, domain.HashRecoveryCode(codes[0])))
+				assert.ErrorIs(t, mr.UseRecoveryCode(1, domain.HashRecoveryCode(codes[0])), gorm.ErrRecordNotFound)
+				assert.ErrorIs(t, mr.UseRecoveryCode(2, domain.HashRecoveryCode(codes[1])), gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "replaced recovery codes are not usable",
+			givenFn: func(tx *gorm.DB) error {
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, mr ports.MFARepository) {
+				oldCodes, recoveryCodes, _ := domain.NewRecoveryCodes(1)
+				assert.NoError(t, mr.ReplaceRecoveryCodes(1, recoveryCodes))
+				newCodes, recoveryCodes, _ := domain.NewRecoveryCodes(1)
+				assert.NoError(t, mr.ReplaceRecoveryCodes(1, recoveryCodes))
+
+				assert.ErrorIs(t, mr.UseRecoveryCode(1, domain.HashRecoveryCode(oldCodes[0])), gorm.ErrRecordNotFound)
+				assert.NoError(t, mr.UseRecoveryCode(1, domain.HashRecoveryCode(newCodes[0])))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.runWithMFA(tt.thenFn)
+		})
+	}
+}
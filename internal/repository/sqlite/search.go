@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"gorm.io/gorm"
+	"strings"
+)
+
+// MigrateFullTextSearch creates FTS5 index of articles, which is kept in sync by triggers.
+// Existing articles are indexed when the index is created.
+func MigrateFullTextSearch(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var exists int64
+		err := tx.Raw(`SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'articles_fts'`).
+			Scan(&exists).Error
+		if err != nil {
+			return err
+		}
+
+		statements := []string{
+			`CREATE VIRTUAL TABLE IF NOT EXISTS articles_fts USING fts5(
+				title, description, body,
+				content='articles', content_rowid='id', tokenize='porter unicode61'
+			)`,
+			`CREATE TRIGGER IF NOT EXISTS articles_fts_insert AFTER INSERT ON articles BEGIN
+				INSERT INTO articles_fts(rowid, title, description, body)
+				VALUES (new.id, new.title, new.description, new.body);
+			END`,
+			`CREATE TRIGGER IF NOT EXISTS articles_fts_delete AFTER DELETE ON articles BEGIN
+				INSERT INTO articles_fts(articles_fts, rowid, title, description, body)
+				VALUES ('delete', old.id, old.title, old.description, old.body);
+			END`,
+			`CREATE TRIGGER IF NOT EXISTS articles_fts_update AFTER UPDATE OF title, description, body ON articles BEGIN
+				INSERT INTO articles_fts(articles_fts, rowid, title, description, body)
+				VALUES ('delete', old.id, old.title, old.description, old.body);
+				INSERT INTO articles_fts(rowid, title, description, body)
+				VALUES (new.id, new.title, new.description, new.body);
+			END`,
+		}
+		if exists == 0 {
+			statements = append(statements, `INSERT INTO articles_fts(articles_fts) VALUES ('rebuild')`)
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// matchQuery quotes each word of user query, so that FTS5 query syntax in it is matched literally.
+func matchQuery(query string) string {
+	words := strings.Fields(query)
+	for i, word := range words {
+		words[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,216 @@
+//go:build sqlite
+// +build sqlite
+
+package sqlite
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func Test_tokenRepository_RevokeRefreshToken(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, tr ports.TokenRepository)
+	}{
+		{
+			name: "revoke refresh token only once",
+			givenFn: func(tx *gorm.DB) error {
+				return tx.Create(&domain.RefreshToken{
+					UserID:    1,
+					TokenHash: "test-hash",
+					ExpiresAt: time.Now().Add(time.Hour),
+				}).Error
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, tr ports.TokenRepository) {
+				token, err := tr.FindRefreshToken("test-hash")
+				assert.NoError(t, err)
+				assert.False(t, token.Revoked())
+
+				err = tr.RevokeRefreshToken(token.ID)
+				assert.NoError(t, err)
+				err = tr.RevokeRefreshToken(token.ID)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+				token, err = tr.FindRefreshToken("test-hash")
+				assert.NoError(t, err)
+				assert.True(t, token.Revoked())
+			},
+		},
+		{
+			name: "revoke refresh tokens of user",
+			givenFn: func(tx *gorm.DB) error {
+				tx.Create(&domain.RefreshToken{UserID: 1, TokenHash: "test-hash1", ExpiresAt: time.Now().Add(time.Hour)})
+				tx.Create(&domain.RefreshToken{UserID: 1, TokenHash: "test-hash2", ExpiresAt: time.Now().Add(time.Hour)})
+				tx.Create(&domain.RefreshToken{UserID: 2, TokenHash: "test-hash3", ExpiresAt: time.Now().Add(time.Hour)})
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, tr ports.TokenRepository) {
+				err := tr.RevokeRefreshTokens(1)
+				assert.NoError(t, err)
+
+				token, _ := tr.FindRefreshToken("test-hash1")
+				assert.True(t, token.Revoked())
+				token, _ = tr.FindRefreshToken("test-hash2")
+				assert.True(t, token.Revoked())
+				token, _ = tr.FindRefreshToken("test-hash3")
+				assert.False(t, token.Revoked())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.runWithToken(tt.thenFn)
+		})
+	}
+}
+
+func Test_tokenRepository_ExistsDeniedToken(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, tr ports.TokenRepository)
+	}{
+		{
+			name: "find denied token",
+			thenFn: func(t *testing.T, ur ports.UserRepository, tr ports.TokenRepository) {
+				_, err := tr.CreateDeniedToken("test-jti", time.Now().Add(time.Hour))
+				assert.NoError(t, err)
+
+				denied, err := tr.ExistsDeniedToken("test-jti")
+				assert.NoError(t, err)
+				assert.True(t, denied)
+
+				denied, err = tr.ExistsDeniedToken("null")
+				assert.NoError(t, err)
+				assert.False(t, denied)
+			},
+		},
+		{
+			name: "delete expired denied tokens",
+			thenFn: func(t *testing.T, ur ports.UserRepository, tr ports.TokenRepository) {
+				now := time.Now()
+				_, err := tr.CreateDeniedToken("expired-jti", now.Add(-time.Minute))
+				assert.NoError(t, err)
+				_, err = tr.CreateDeniedToken("valid-jti", now.Add(time.Hour))
+				assert.NoError(t, err)
+
+				deleted, err := tr.DeleteExpiredDeniedTokens(now.UTC())
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), deleted)
+
+				denied, err := tr.ExistsDeniedToken("expired-jti")
+				assert.NoError(t, err)
+				assert.False(t, denied)
+				denied, err = tr.ExistsDeniedToken("valid-jti")
+				assert.NoError(t, err)
+				assert.True(t, denied)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.runWithToken(tt.thenFn)
+		})
+	}
+}
+
+func Test_tokenRepository_UseOneTimeToken(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, tr ports.TokenRepository)
+	}{
+		{
+			name: "use one time token only once",
+			givenFn: func(tx *gorm.DB) error {
+				return tx.Create(&domain.OneTimeToken{
+					UserID:    1,
+					Purpose:   domain.PurposePasswordReset,
+					TokenHash: "test-hash",
+					ExpiresAt: time.Now().Add(time.Hour),
+				}).Error
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, tr ports.TokenRepository) {
+				token, err := tr.FindOneTimeToken(domain.PurposePasswordReset, "test-hash")
+				assert.NoError(t, err)
+				assert.True(t, token.Usable())
+
+				err = tr.UseOneTimeToken(token.ID)
+				assert.NoError(t, err)
+				err = tr.UseOneTimeToken(token.ID)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+				token, err = tr.FindOneTimeToken(domain.PurposePasswordReset, "test-hash")
+				assert.NoError(t, err)
+				assert.False(t, token.Usable())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.runWithToken(tt.thenFn)
+		})
+	}
+}
+
+func Test_tokenRepository_PersonalAccessToken(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		thenFn  func(t *testing.T, ur ports.UserRepository, tr ports.TokenRepository)
+	}{
+		{
+			name: "find and delete personal access token of owner",
+			givenFn: func(tx *gorm.DB) error {
+				tx.Create(&domain.PersonalAccessToken{UserID: 1, Name: "test1", TokenHash: "test-hash1", Scopes: []string{domain.ScopeArticlesWrite, domain.ScopeCommentsWrite}})
+				tx.Create(&domain.PersonalAccessToken{UserID: 1, Name: "test2", TokenHash: "test-hash2"})
+				tx.Create(&domain.PersonalAccessToken{UserID: 2, Name: "test3", TokenHash: "test-hash3"})
+				return nil
+			},
+			thenFn: func(t *testing.T, ur ports.UserRepository, tr ports.TokenRepository) {
+				token, err := tr.FindPersonalAccessToken("test-hash1")
+				assert.NoError(t, err)
+				assert.Equal(t, []string{domain.ScopeArticlesWrite, domain.ScopeCommentsWrite}, []string(token.Scopes))
+
+				err = tr.TouchPersonalAccessToken(token.ID, time.Now())
+				assert.NoError(t, err)
+				token, _ = tr.FindPersonalAccessToken("test-hash1")
+				assert.True(t, token.LastUsedAt.Valid)
+
+				tokens, err := tr.FindPersonalAccessTokens(1)
+				assert.NoError(t, err)
+				assert.Equal(t, 2, len(tokens))
+
+				err = tr.DeletePersonalAccessToken(2, token.ID)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				err = tr.DeletePersonalAccessToken(1, token.ID)
+				assert.NoError(t, err)
+				_, err = tr.FindPersonalAccessToken("test-hash1")
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.runWithToken(tt.thenFn)
+		})
+	}
+}
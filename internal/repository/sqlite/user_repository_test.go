@@ -25,12 +25,20 @@ type sqliteFixture struct {
 
 func newSqliteFixture(t *testing.T) *sqliteFixture {
 	db, err := gorm.Open(sqlite.Open("test.db"), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger:         logger.Default.LogMode(logger.Info),
+		TranslateError: true,
 	})
 	if err != nil {
 		t.Fatal(err)
 	}
-	err = db.AutoMigrate(&domain.User{}, &domain.Follow{}, &domain.Article{}, &domain.Favorite{}, &domain.Comment{})
+	err = db.AutoMigrate(&domain.User{}, &domain.Follow{}, &domain.Article{}, &domain.ArticleRevision{}, &domain.SlugHistory{}, &domain.Favorite{}, &domain.Comment{}, &domain.CommentRevision{},
+		&domain.RefreshToken{}, &domain.DeniedToken{}, &domain.OneTimeToken{},
+		&domain.Identity{}, &domain.LoginState{}, &domain.PersonalAccessToken{},
+		&domain.TwoFactor{}, &domain.RecoveryCode{}, &domain.LoginThrottle{}, &domain.Lease{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = MigrateFullTextSearch(db)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -88,6 +96,108 @@ func (f *sqliteFixture) runWithComment(fn func(t *testing.T, ur ports.UserReposi
 	tx.Rollback()
 }
 
+func (f *sqliteFixture) runWithToken(fn func(t *testing.T, ur ports.UserRepository, tr ports.TokenRepository)) {
+	tx := f.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			f.t.Fatal(r)
+		}
+	}()
+
+	err := f.givenFn(tx)
+	assert.NoError(f.t, err)
+
+	fn(f.t, NewUserRepository(tx), NewTokenRepository(tx))
+
+	tx.Rollback()
+}
+
+func (f *sqliteFixture) runWithIdentity(fn func(t *testing.T, ur ports.UserRepository, ir ports.IdentityRepository)) {
+	tx := f.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			f.t.Fatal(r)
+		}
+	}()
+
+	err := f.givenFn(tx)
+	assert.NoError(f.t, err)
+
+	fn(f.t, NewUserRepository(tx), NewIdentityRepository(tx))
+
+	tx.Rollback()
+}
+
+func (f *sqliteFixture) runWithMFA(fn func(t *testing.T, ur ports.UserRepository, mr ports.MFARepository)) {
+	tx := f.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			f.t.Fatal(r)
+		}
+	}()
+
+	err := f.givenFn(tx)
+	assert.NoError(f.t, err)
+
+	fn(f.t, NewUserRepository(tx), NewMFARepository(tx))
+
+	tx.Rollback()
+}
+
+func (f *sqliteFixture) runWithLoginThrottle(fn func(t *testing.T, lr ports.LoginThrottleRepository)) {
+	tx := f.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			f.t.Fatal(r)
+		}
+	}()
+
+	err := f.givenFn(tx)
+	assert.NoError(f.t, err)
+
+	fn(f.t, NewLoginThrottleRepository(tx))
+
+	tx.Rollback()
+}
+
+func (f *sqliteFixture) runWithLease(fn func(t *testing.T, ar ports.ArticleRepository, lr ports.LeaseRepository)) {
+	tx := f.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			f.t.Fatal(r)
+		}
+	}()
+
+	err := f.givenFn(tx)
+	assert.NoError(f.t, err)
+
+	fn(f.t, NewArticleRepository(tx), NewLeaseRepository(tx))
+
+	tx.Rollback()
+}
+
+func (f *sqliteFixture) runWithAuthor(fn func(t *testing.T, ar ports.ArticleRepository, cr ports.CommentRepository, aur ports.AuthorRepository)) {
+	tx := f.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			f.t.Fatal(r)
+		}
+	}()
+
+	err := f.givenFn(tx)
+	assert.NoError(f.t, err)
+
+	fn(f.t, NewArticleRepository(tx), NewCommentRepository(tx), NewAuthorRepository(tx))
+
+	tx.Rollback()
+}
+
 func (f *sqliteFixture) close() {
 	os.Remove("test.db")
 }
@@ -203,7 +313,7 @@ func Test_sqliteRepository_Save(t *testing.T) {
 
 				updated, err := ur.FindByEmail("test@example.com")
 				assert.NoError(t, err)
-				assert.True(t, updated.ValidPassword("test-password"))
+				assert.True(t, updated.ValidPassword("new-password"))
 			},
 		},
 	}
@@ -384,3 +494,63 @@ func Test_sqliteRepository_FindProfile(t *testing.T) {
 		})
 	}
 }
+
+func Test_sqliteRepository_DeleteFollows(t *testing.T) {
+	f := newSqliteFixture(t)
+
+	tests := []struct {
+		name    string
+		givenFn func(tx *gorm.DB) error
+		fn      func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository)
+	}{
+		{
+			name: "delete follows and favorites of user",
+			givenFn: func(tx *gorm.DB) error {
+				user1 := domain.User{Email: "test1@example.com", Username: "test1"}
+				tx.Create(&user1)
+				user2 := domain.User{Email: "test2@example.com", Username: "test2"}
+				tx.Create(&user2)
+				tx.Create(&domain.Follow{FollowerID: user1.ID, FollowingID: user2.ID})
+				tx.Create(&domain.Follow{FollowerID: user2.ID, FollowingID: user1.ID})
+				article := domain.Article{
+					Slug:   "test",
+					Author: domain.Author{ID: user2.ID, Username: user2.Username},
+				}
+				tx.Create(&article)
+				tx.Create(&domain.Favorite{UserID: user1.ID, ArticleID: article.ID})
+				return tx.Create(&domain.Favorite{UserID: user2.ID, ArticleID: article.ID}).Error
+			},
+			fn: func(t *testing.T, ur ports.UserRepository, ar ports.ArticleRepository) {
+				followings, err := ur.FindFollowings(1)
+				assert.NoError(t, err)
+				assert.Len(t, followings, 1)
+				assert.Equal(t, "test2", followings[0].Username)
+				favorited, err := ar.FindFavoritedArticles(1)
+				assert.NoError(t, err)
+				assert.Len(t, favorited, 1)
+
+				assert.NoError(t, ur.DeleteFollows(1))
+				assert.NoError(t, ar.DeleteFavorites(1))
+
+				followings, err = ur.FindFollowings(1)
+				assert.NoError(t, err)
+				assert.Empty(t, followings)
+				followings, err = ur.FindFollowings(2)
+				assert.NoError(t, err)
+				assert.Empty(t, followings)
+				favorited, err = ar.FindFavoritedArticles(1)
+				assert.NoError(t, err)
+				assert.Empty(t, favorited)
+				favorited, err = ar.FindFavoritedArticles(2)
+				assert.NoError(t, err)
+				assert.Len(t, favorited, 1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expectGiven(tt.givenFn)
+			f.run(tt.fn)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/internal/rest/middleware"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+)
+
+type AccountController struct {
+	accountService ports.AccountService
+}
+
+func NewAccountController(accountService ports.AccountService) *AccountController {
+	return &AccountController{
+		accountService: accountService,
+	}
+}
+
+func (c *AccountController) DeleteAccount(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = c.accountService.WithTx(tx).DeleteAccount(claim)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+type ExportAccountQuery struct {
+	Format string `form:"format,default=json" binding:"oneof=json zip"`
+}
+
+// ExportAccount responds personal data as attachment, either single json or zip of json files.
+func (c *AccountController) ExportAccount(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	request := ExportAccountQuery{}
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	export, err := c.accountService.ExportAccount(claim.UID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	resp := AccountExportToResponse(export)
+
+	filename := fmt.Sprintf("%s-export.%s", export.User.Username, request.Format)
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	if request.Format == "json" {
+		ctx.JSON(http.StatusOK, resp)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("Content-Type", "application/zip")
+	// files are streamed after header is written, so error means the client is gone and can not be responded.
+	_ = writeExportZip(ctx.Writer, resp)
+}
+
+func writeExportZip(w io.Writer, resp AccountExportResponse) error {
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", resp.Profile},
+		{"articles.json", resp.Articles},
+		{"comments.json", resp.Comments},
+		{"favorites.json", resp.Favorites},
+		{"follows.json", resp.Follows},
+	}
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(fw)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(f.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports/mock_ports"
+	"github.com/KumKeeHyun/gin-realworld/internal/rest/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func accountRoute(ctrl *gomock.Controller, accountController *AccountController) *gin.Engine {
+	logger := zap.NewNop()
+	errorHandler := middleware.NewErrorsMiddleware(logger).GinHandlerFunc()
+	checkJwt := checkJwtHandler(ctrl, logger)
+	ensureAuth := middleware.NewEnsureAuthMiddleware(logger).GinHandlerFunc()
+	requireSession := middleware.NewScopeMiddleware(logger).RequireSession()
+
+	r := gin.New()
+	api := r.Group("api", errorHandler, checkJwt)
+	user := api.Group("user")
+	user.GET("/export", ensureAuth, requireSession, accountController.ExportAccount)
+
+	return r
+}
+
+func TestAccountController_ExportAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	as := mock_ports.NewMockAccountService(ctrl)
+
+	as.EXPECT().
+		ExportAccount(gomock.Eq(uint(1))).
+		Return(domain.AccountExport{
+			User:       domain.User{Model: gorm.Model{ID: 1}, Email: "test@test.com", Username: "test"},
+			Articles:   []domain.Article{{Slug: "mine"}},
+			Comments:   []domain.Comment{{Body: "comment", ArticleID: 3}},
+			Favorites:  []domain.Article{{Slug: "favorite"}},
+			Followings: []domain.User{{Username: "following"}},
+		}, nil).
+		Times(2)
+
+	c := NewAccountController(as)
+	r := accountRoute(ctrl, c)
+
+	t.Run("json 형식", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/user/export", nil)
+		setAuthorization(req, 1, "test")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "test-export.json")
+		resp := AccountExportResponse{}
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, "test@test.com", resp.Profile.Email)
+		assert.Equal(t, "mine", resp.Articles[0].Slug)
+		assert.Equal(t, uint(3), resp.Comments[0].ArticleID)
+		assert.True(t, resp.Favorites[0].Favorited)
+		assert.Equal(t, "following", resp.Follows[0].Username)
+	})
+	t.Run("zip 형식", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/user/export?format=zip", nil)
+		setAuthorization(req, 1, "test")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+		zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+		assert.NoError(t, err)
+		var names []string
+		for _, f := range zr.File {
+			names = append(names, f.Name)
+		}
+		assert.Equal(t, []string{"profile.json", "articles.json", "comments.json", "favorites.json", "follows.json"}, names)
+	})
+	t.Run("지원하지 않는 형식", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/user/export?format=xml", nil)
+		setAuthorization(req, 1, "test")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
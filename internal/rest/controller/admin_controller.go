@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/internal/rest/middleware"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type AdminController struct {
+	adminService ports.AdminService
+}
+
+func NewAdminController(adminService ports.AdminService) *AdminController {
+	return &AdminController{
+		adminService: adminService,
+	}
+}
+
+type ListUsersQuery struct {
+	Limit  int `form:"limit,default=20"`
+	Offset int `form:"offset,default=0"`
+}
+
+func (c *AdminController) ListUsers(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	request := ListUsersQuery{}
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	users, err := c.adminService.ListUsers(claim.Actor(), ports.Pageable{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, ManagedUsersToResponse(users))
+}
+
+type ManagedUserUri struct {
+	Username string `uri:"username" binding:"required"`
+}
+
+type UpdateRoleRequest struct {
+	User struct {
+		Role string `json:"role" binding:"required"`
+	} `json:"user" binding:"required"`
+}
+
+func (c *AdminController) UpdateRole(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var requestUri ManagedUserUri
+	if err := ctx.ShouldBindUri(&requestUri); err != nil {
+		ctx.Error(err)
+		return
+	}
+	var request UpdateRoleRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	user, err := c.adminService.WithTx(tx).UpdateRole(claim.Actor(), requestUri.Username, domain.Role(request.User.Role))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, ManagedUserResponse{User: ManagedUserToDto(user)})
+}
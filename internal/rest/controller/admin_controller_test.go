@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports/mock_ports"
+	"github.com/KumKeeHyun/gin-realworld/internal/rest/middleware"
+	"github.com/KumKeeHyun/gin-realworld/pkg/jwtutil"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func adminRoute(ctrl *gomock.Controller, adminController *AdminController) *gin.Engine {
+	logger := zap.NewNop()
+	errorHandler := middleware.NewErrorsMiddleware(logger).GinHandlerFunc()
+	checkJwt := checkJwtHandler(ctrl, logger)
+	ensureAuth := middleware.NewEnsureAuthMiddleware(logger).GinHandlerFunc()
+	requireSession := middleware.NewScopeMiddleware(logger).RequireSession()
+
+	r := gin.New()
+	api := r.Group("api", errorHandler, checkJwt)
+	admin := api.Group("admin", ensureAuth, requireSession)
+	admin.GET("/users", adminController.ListUsers)
+
+	return r
+}
+
+func setRoleAuthorization(req *http.Request, id uint, username string, role domain.Role) {
+	jwtUtil := jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret"))
+	token, _ := jwtUtil.SignClaims(domain.AccessClaim{
+		UID:      id,
+		Username: username,
+		Verified: true,
+		Role:     role,
+	})
+	req.Header["Authorization"] = []string{"token " + token}
+}
+
+func TestAdminController_ListUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	as := mock_ports.NewMockAdminService(ctrl)
+
+	as.EXPECT().
+		ListUsers(gomock.Eq(domain.Actor{ID: 1, Role: domain.RoleAdmin}), gomock.Eq(ports.Pageable{Limit: 20, Offset: 0})).
+		Return([]domain.User{
+			{Model: gorm.Model{ID: 1}, Username: "admin", Role: domain.RoleAdmin},
+			{Model: gorm.Model{ID: 2}, Username: "test", Role: domain.RoleUser},
+		}, nil)
+	as.EXPECT().
+		ListUsers(gomock.Eq(domain.Actor{ID: 2}), gomock.Any()).
+		Return(nil, ports.ErrPermissionDenied)
+
+	c := NewAdminController(as)
+	r := adminRoute(ctrl, c)
+
+	t.Run("유저 목록 조회", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/users", nil)
+		setRoleAuthorization(req, 1, "admin", domain.RoleAdmin)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		resp := MultipleManagedUsersResponse{}
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Len(t, resp.Users, 2)
+		assert.Equal(t, "admin", resp.Users[0].Role)
+	})
+	t.Run("관리자가 아닌 유저", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/users", nil)
+		setAuthorization(req, 2, "test")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+	t.Run("인증 없이 요청", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/users", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
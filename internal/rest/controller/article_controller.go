@@ -1,19 +1,26 @@
 package controller
 
 import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
 	"github.com/KumKeeHyun/gin-realworld/internal/rest/middleware"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
 )
 
 type ArticleController struct {
 	articleService ports.ArticleService
+	paginator      *Paginator
 }
 
-func NewArticleController(articleService ports.ArticleService) *ArticleController {
+func NewArticleController(articleService ports.ArticleService, paginator *Paginator) *ArticleController {
 	return &ArticleController{
 		articleService: articleService,
+		paginator:      paginator,
 	}
 }
 
@@ -23,9 +30,24 @@ type CreateArticleRequest struct {
 		Description string   `json:"description" binding:"required"`
 		Body        string   `json:"body" binding:"required"`
 		TagList     []string `json:"tagList"`
+		// Slug is generated from Title if it is not set
+		Slug *string `json:"slug"`
+		// Status is published by default, or draft if PublishAt is set
+		Status    *string    `json:"status" binding:"omitempty,oneof=draft published unlisted"`
+		PublishAt *time.Time `json:"publishAt"`
 	} `json:"article" binding:"required"`
 }
 
+func (r CreateArticleRequest) ArticleStatus() domain.ArticleStatus {
+	if r.Article.Status != nil {
+		return domain.ArticleStatus(*r.Article.Status)
+	}
+	if r.Article.PublishAt != nil {
+		return domain.StatusDraft
+	}
+	return domain.StatusPublished
+}
+
 func (c *ArticleController) CreateArticle(ctx *gin.Context) {
 	claim, err := middleware.GetAccessClaim(ctx)
 	if err != nil {
@@ -39,12 +61,21 @@ func (c *ArticleController) CreateArticle(ctx *gin.Context) {
 		return
 	}
 
-	created, err := c.articleService.Create(
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	created, err := c.articleService.WithTx(tx).Create(
 		claim.UID,
 		request.Article.Title,
 		request.Article.Description,
 		request.Article.Body,
 		request.Article.TagList,
+		request.ArticleStatus(),
+		request.Article.PublishAt,
+		request.Article.Slug,
 	)
 	if err != nil {
 		ctx.Error(err)
@@ -53,46 +84,98 @@ func (c *ArticleController) CreateArticle(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, ArticleViewToResponse(created))
 }
 
+// ListArticlesQuery takes repeated tag, excludeTag and author parameters, such as ?tag=go&tag=gin.
 type ListArticlesQuery struct {
-	Tag       *string `form:"tag"`
-	Author    *string `form:"author"`
-	Favorited *string `form:"favorited"`
-	Limit     int     `form:"limit,default=20"`
-	Offset    int     `form:"offset,default=0"`
+	Query       *string  `form:"q" binding:"omitempty,max=200"`
+	Tags        []string `form:"tag" binding:"max=10,dive,required"`
+	TagMatch    string   `form:"tagMatch" binding:"omitempty,oneof=any all"`
+	ExcludeTags []string `form:"excludeTag" binding:"max=10,dive,required"`
+	Authors     []string `form:"author" binding:"max=10,dive,required"`
+	Favorited   *string  `form:"favorited"`
+	// CreatedAfter and CreatedBefore are RFC3339 times
+	CreatedAfter  *time.Time `form:"createdAfter"`
+	CreatedBefore *time.Time `form:"createdBefore"`
+	// Followed lists only articles of followed authors, which requires authentication
+	Followed bool `form:"followed"`
+	// Sort is newest first by default, or relevance if Query is set
+	Sort   string `form:"sort" binding:"omitempty,oneof=recent oldest mostFavorited trending"`
+	Limit  int    `form:"limit,default=20"`
+	Offset int    `form:"offset,default=0"`
+	// Cursor is nextCursor or prevCursor of previous response, Offset is ignored if it is set.
+	// It can only be used with recent or oldest Sort.
+	Cursor string `form:"cursor"`
 }
 
-func (q ListArticlesQuery) ToSearchConditions() ports.ArticleSearchConditions {
+func (q ListArticlesQuery) ToSearchConditions(pageable ports.Pageable) ports.ArticleSearchConditions {
+	// blank query searches nothing, it is ignored
+	query := q.Query
+	if query != nil && strings.TrimSpace(*query) == "" {
+		query = nil
+	}
 	return ports.ArticleSearchConditions{
-		Tag:       q.Tag,
-		Author:    q.Author,
-		Favorited: q.Favorited,
-		Pageable: ports.Pageable{
-			Limit:  q.Limit,
-			Offset: q.Offset,
-		},
+		Query:         query,
+		Tags:          q.Tags,
+		TagMatch:      ports.TagMatch(q.TagMatch),
+		ExcludeTags:   q.ExcludeTags,
+		Authors:       q.Authors,
+		Favorited:     q.Favorited,
+		CreatedAfter:  q.CreatedAfter,
+		CreatedBefore: q.CreatedBefore,
+		FollowedOnly:  q.Followed,
+		Sort:          ports.ArticleSort(q.Sort),
+		Pageable:      pageable,
 	}
 }
 
+// CursorScope identifies listing by sort and filters of query, regardless of page.
+func (q ListArticlesQuery) CursorScope() CursorScope {
+	sort := q.Sort
+	if sort == "" {
+		sort = string(ports.SortRecent)
+	}
+	q.Sort, q.Limit, q.Offset, q.Cursor = "", 0, 0, ""
+	return CursorScope{Sort: sort, Filters: q}
+}
+
 func (c *ArticleController) ListArticles(ctx *gin.Context) {
-	claim, _ := middleware.GetAccessClaim(ctx)
+	claim, claimErr := middleware.GetAccessClaim(ctx)
 
 	request := ListArticlesQuery{}
 	if err := ctx.ShouldBindQuery(&request); err != nil {
 		ctx.Error(err)
 		return
 	}
+	if request.Followed && claimErr != nil {
+		ctx.Error(middleware.ErrEnsureAuth)
+		return
+	}
 
-	articles, err := c.articleService.ListByConditions(claim.UID, request.ToSearchConditions())
+	scope := request.CursorScope()
+	pageable, err := c.paginator.Pageable(request.Limit, request.Offset, request.Cursor, scope)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, ArticlesToResponse(articles))
+
+	conditions := request.ToSearchConditions(pageable)
+	articles, page, err := c.articleService.ListByConditions(claim.UID, conditions)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	resp := ArticlesToResponse(articles, page.Total)
+	if conditions.OrderedByCreation() {
+		first, last := articleCursors(articles)
+		resp.PrevCursor, resp.NextCursor = c.paginator.Cursors(page, first, last, scope)
+	}
+	ctx.JSON(http.StatusOK, resp)
 }
 
 type FeedArticlesQuery struct {
 	Limit  int `form:"limit,default=20"`
 	Offset int `form:"offset,default=0"`
+	// Cursor is nextCursor or prevCursor of previous response, Offset is ignored if it is set
+	Cursor string `form:"cursor"`
 }
 
 func (q FeedArticlesQuery) ToPageable() ports.Pageable {
@@ -102,6 +185,9 @@ func (q FeedArticlesQuery) ToPageable() ports.Pageable {
 	}
 }
 
+// feed is always the newest first
+var feedCursorScope = CursorScope{Sort: string(ports.SortRecent), Filters: "feed"}
+
 func (c *ArticleController) FeedArticles(ctx *gin.Context) {
 	claim, err := middleware.GetAccessClaim(ctx)
 	if err != nil {
@@ -115,12 +201,47 @@ func (c *ArticleController) FeedArticles(ctx *gin.Context) {
 		return
 	}
 
-	articles, err := c.articleService.ListFeed(claim.UID, request.ToPageable())
+	pageable, err := c.paginator.Pageable(request.Limit, request.Offset, request.Cursor, feedCursorScope)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	articles, page, err := c.articleService.ListFeed(claim.UID, pageable)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	resp := ArticlesToResponse(articles, page.Total)
+	first, last := articleCursors(articles)
+	resp.PrevCursor, resp.NextCursor = c.paginator.Cursors(page, first, last, feedCursorScope)
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (c *ArticleController) ListDrafts(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	request := FeedArticlesQuery{}
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+	// drafts are ordered by last update, which cursor does not point to
+	if request.Cursor != "" {
+		ctx.Error(ports.ErrUnsupportedCursor)
+		return
+	}
+
+	articles, page, err := c.articleService.ListDrafts(claim.UID, request.ToPageable())
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, ArticlesToResponse(articles))
+	ctx.JSON(http.StatusOK, ArticlesToResponse(articles, page.Total))
 }
 
 type ArticleUri struct {
@@ -141,14 +262,23 @@ func (c *ArticleController) GetArticle(ctx *gin.Context) {
 		ctx.Error(err)
 		return
 	}
+	// article is found by its old slug. Redirect is temporary, since article can take the old slug back
+	// and cached permanent redirects would then loop between the slugs.
+	if article.Slug != requestUri.Slug {
+		location := url.URL{Path: path.Join(path.Dir(ctx.Request.URL.Path), article.Slug), RawQuery: ctx.Request.URL.RawQuery}
+		ctx.Redirect(http.StatusFound, location.String())
+		return
+	}
 	ctx.JSON(http.StatusOK, ArticleViewToResponse(article))
 }
 
 type UpdateArticleRequest struct {
 	Article struct {
-		Title       *string `json:"title"`
-		Description *string `json:"description"`
-		Body        *string `json:"body"`
+		Title       *string    `json:"title"`
+		Description *string    `json:"description"`
+		Body        *string    `json:"body"`
+		Slug        *string    `json:"slug"`
+		PublishAt   *time.Time `json:"publishAt"`
 	} `json:"article" binding:"required"`
 }
 
@@ -157,6 +287,8 @@ func (r UpdateArticleRequest) ToArticleUpdateFields() ports.ArticleUpdateFields
 		Title:       r.Article.Title,
 		Description: r.Article.Description,
 		Body:        r.Article.Body,
+		Slug:        r.Article.Slug,
+		PublishAt:   r.Article.PublishAt,
 	}
 }
 
@@ -178,7 +310,13 @@ func (c *ArticleController) UpdateArticle(ctx *gin.Context) {
 		return
 	}
 
-	updated, err := c.articleService.Update(claim.UID, requestUri.Slug, request.ToArticleUpdateFields())
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	updated, err := c.articleService.WithTx(tx).Update(claim.Actor(), requestUri.Slug, request.ToArticleUpdateFields())
 	if err != nil {
 		ctx.Error(err)
 		return
@@ -199,7 +337,7 @@ func (c *ArticleController) DeleteArticle(ctx *gin.Context) {
 		return
 	}
 
-	err = c.articleService.Delete(claim.UID, requestUri.Slug)
+	err = c.articleService.Delete(claim.Actor(), requestUri.Slug)
 	if err != nil {
 		ctx.Error(err)
 		return
@@ -207,6 +345,119 @@ func (c *ArticleController) DeleteArticle(ctx *gin.Context) {
 	ctx.Status(http.StatusOK)
 }
 
+func (c *ArticleController) PublishArticle(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var requestUri ArticleUri
+	if err := ctx.ShouldBindUri(&requestUri); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	article, err := c.articleService.Publish(claim.Actor(), requestUri.Slug)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, ArticleViewToResponse(article))
+}
+
+func (c *ArticleController) ListRevisions(ctx *gin.Context) {
+	claim, _ := middleware.GetAccessClaim(ctx)
+
+	var requestUri ArticleUri
+	if err := ctx.ShouldBindUri(&requestUri); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	revisions, err := c.articleService.ListRevisions(claim.UID, requestUri.Slug)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, ArticleRevisionsToResponse(revisions))
+}
+
+type RevisionUri struct {
+	Slug   string `uri:"slug" binding:"required"`
+	Number int    `uri:"number" binding:"required,min=1"`
+}
+
+func (c *ArticleController) GetRevision(ctx *gin.Context) {
+	claim, _ := middleware.GetAccessClaim(ctx)
+
+	var requestUri RevisionUri
+	if err := ctx.ShouldBindUri(&requestUri); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	revision, err := c.articleService.FindRevision(claim.UID, requestUri.Slug, requestUri.Number)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, ArticleRevisionToResponse(revision))
+}
+
+type DiffRevisionsQuery struct {
+	From int `form:"from" binding:"required,min=1"`
+	To   int `form:"to" binding:"required,min=1"`
+}
+
+func (c *ArticleController) DiffRevisions(ctx *gin.Context) {
+	claim, _ := middleware.GetAccessClaim(ctx)
+
+	var requestUri ArticleUri
+	if err := ctx.ShouldBindUri(&requestUri); err != nil {
+		ctx.Error(err)
+		return
+	}
+	var request DiffRevisionsQuery
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	diff, err := c.articleService.DiffRevisions(claim.UID, requestUri.Slug, request.From, request.To)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, RevisionDiffToResponse(diff))
+}
+
+func (c *ArticleController) RestoreRevision(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var requestUri RevisionUri
+	if err := ctx.ShouldBindUri(&requestUri); err != nil {
+		ctx.Error(err)
+		return
+	}
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	article, err := c.articleService.WithTx(tx).RestoreRevision(claim.Actor(), requestUri.Slug, requestUri.Number)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, ArticleViewToResponse(article))
+}
+
 func (c *ArticleController) FavoriteArticle(ctx *gin.Context) {
 	claim, err := middleware.GetAccessClaim(ctx)
 	if err != nil {
@@ -250,7 +501,9 @@ func (c *ArticleController) UnfavoriteArticle(ctx *gin.Context) {
 }
 
 func (c *ArticleController) GetTags(ctx *gin.Context) {
-	tags, err := c.articleService.ListTags()
+	claim, _ := middleware.GetAccessClaim(ctx)
+
+	tags, err := c.articleService.ListTags(claim.UID)
 	if err != nil {
 		ctx.Error(err)
 		return
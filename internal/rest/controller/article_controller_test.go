@@ -2,27 +2,36 @@ package controller
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/ports/mock_ports"
 	"github.com/KumKeeHyun/gin-realworld/internal/rest/middleware"
+	"github.com/KumKeeHyun/gin-realworld/pkg/crypto"
+	"github.com/KumKeeHyun/gin-realworld/pkg/cursor"
 	"github.com/KumKeeHyun/gin-realworld/pkg/jwtutil"
 	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
-func articleRoute(articleController *ArticleController) *gin.Engine {
+func articleRoute(ctrl *gomock.Controller, articleController *ArticleController) *gin.Engine {
 	logger := zap.NewNop()
 	errorHandler := middleware.NewErrorsMiddleware(logger).GinHandlerFunc()
-	checkJwt := middleware.NewCheckJwtMiddleware(jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), logger).GinHandlerFunc()
+	checkJwt := checkJwtHandler(ctrl, logger)
 	ensureAuth := middleware.NewEnsureAuthMiddleware(logger).GinHandlerFunc()
+	ensureVerified := middleware.NewEnsureVerifiedMiddleware(true, verifiedUserRepository(ctrl), logger).GinHandlerFunc()
+	requireScope := middleware.NewScopeMiddleware(logger).RequireScope
+	transaction := middleware.NewTransactionMiddleware(testDB(), logger).GinHandlerFunc()
 
 	r := gin.New()
 	api := r.Group("api", errorHandler, checkJwt)
@@ -30,20 +39,84 @@ func articleRoute(articleController *ArticleController) *gin.Engine {
 	articles.GET("", articleController.ListArticles)
 	articles.GET("/feed", ensureAuth, articleController.FeedArticles)
 	articles.GET("/:slug", articleController.GetArticle)
-	articles.POST("", ensureAuth, articleController.CreateArticle)
+	articles.POST("", ensureAuth, requireScope(domain.ScopeArticlesWrite), ensureVerified, transaction, articleController.CreateArticle)
 	articles.PUT("/:slug", ensureAuth, articleController.UpdateArticle)
 	articles.DELETE("/:slug", ensureAuth, articleController.DeleteArticle)
+	articles.POST("/:slug/publish", ensureAuth, articleController.PublishArticle)
+	articles.GET("/:slug/revisions", articleController.ListRevisions)
+	articles.GET("/:slug/revisions/diff", articleController.DiffRevisions)
+	articles.GET("/:slug/revisions/:number", articleController.GetRevision)
 	articles.POST("/:slug/favorite", ensureAuth, articleController.FavoriteArticle)
 	articles.DELETE("/:slug/favorite", ensureAuth, articleController.UnfavoriteArticle)
 
 	return r
 }
 
+// testDB only opens transactions for routes, services are mocked.
+func testDB() *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
+
+var testPaginator = NewPaginator(cursor.New([]byte("test-cursor-key")))
+
+// testPersonalAccessToken belongs to user 1 and has only comments:write scope.
+const testPersonalAccessToken = "pat_test"
+
+func checkJwtHandler(ctrl *gomock.Controller, logger *zap.Logger) gin.HandlerFunc {
+	tr := mock_ports.NewMockTokenRepository(ctrl)
+	tr.EXPECT().
+		ExistsDeniedToken(gomock.Any()).
+		Return(false, nil).
+		AnyTimes()
+	tr.EXPECT().
+		FindPersonalAccessToken(gomock.Eq(crypto.HashToken(testPersonalAccessToken))).
+		Return(domain.PersonalAccessToken{
+			Model:      gorm.Model{ID: 1},
+			UserID:     1,
+			Scopes:     []string{domain.ScopeCommentsWrite},
+			LastUsedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}, nil).
+		AnyTimes()
+	tr.EXPECT().
+		FindPersonalAccessToken(gomock.Any()).
+		Return(domain.PersonalAccessToken{}, gorm.ErrRecordNotFound).
+		AnyTimes()
+	ur := mock_ports.NewMockUserRepository(ctrl)
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(1))).
+		Return(domain.User{
+			Model:      gorm.Model{ID: 1},
+			Username:   "test",
+			VerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}, nil).
+		AnyTimes()
+	return middleware.NewCheckJwtMiddleware(jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), tr, ur, logger).GinHandlerFunc()
+}
+
+// verifiedUserRepository has verified user 1 and unverified user 2.
+func verifiedUserRepository(ctrl *gomock.Controller) ports.UserRepository {
+	ur := mock_ports.NewMockUserRepository(ctrl)
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(1))).
+		Return(domain.User{Model: gorm.Model{ID: 1}, VerifiedAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil).
+		AnyTimes()
+	ur.EXPECT().
+		FindByID(gomock.Eq(uint(2))).
+		Return(domain.User{Model: gorm.Model{ID: 2}}, nil).
+		AnyTimes()
+	return ur
+}
+
 func setAuthorization(req *http.Request, id uint, username string) {
 	jwtUtil := jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret"))
 	token, _ := jwtUtil.SignClaims(domain.AccessClaim{
 		UID:      id,
 		Username: username,
+		Verified: true,
 	})
 	req.Header["Authorization"] = []string{"token " + token}
 }
@@ -52,8 +125,9 @@ func TestArticleController_CreateArticle(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	as := mock_ports.NewMockArticleService(ctrl)
 
+	as.EXPECT().WithTx(gomock.Any()).Return(as).AnyTimes()
 	as.EXPECT().
-		Create(gomock.Eq(uint(1)), gomock.Eq("test title"), gomock.Eq("test desc"), gomock.Eq("test body"), gomock.Any()).
+		Create(gomock.Eq(uint(1)), gomock.Eq("test title"), gomock.Eq("test desc"), gomock.Eq("test body"), gomock.Any(), gomock.Eq(domain.StatusPublished), gomock.Nil(), gomock.Nil()).
 		Return(domain.ArticleView{
 			ID:          1,
 			Slug:        "test-slug",
@@ -63,8 +137,8 @@ func TestArticleController_CreateArticle(t *testing.T) {
 		}, nil).
 		AnyTimes()
 
-	c := NewArticleController(as)
-	r := articleRoute(c)
+	c := NewArticleController(as, testPaginator)
+	r := articleRoute(ctrl, c)
 
 	t.Run("글 작성 성공", func(t *testing.T) {
 		w := httptest.NewRecorder()
@@ -89,6 +163,24 @@ func TestArticleController_CreateArticle(t *testing.T) {
 		assert.Equal(t, "test desc", resp.Article.Description)
 		assert.Equal(t, "test body", resp.Article.Body)
 	})
+	t.Run("지원하지 않는 상태", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		createReq := CreateArticleRequest{}
+		createReq.Article.Title = "test title"
+		createReq.Article.Description = "test desc"
+		createReq.Article.Body = "test body"
+		status := "archived"
+		createReq.Article.Status = &status
+		body, err := json.Marshal(&createReq)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/articles", bytes.NewReader(body))
+		setAuthorization(req, 1, "test")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 	t.Run("인증 없이 요청", func(t *testing.T) {
 		w := httptest.NewRecorder()
 
@@ -104,6 +196,57 @@ func TestArticleController_CreateArticle(t *testing.T) {
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 	})
+	t.Run("이메일 인증 없이 요청", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		createReq := CreateArticleRequest{}
+		createReq.Article.Title = "test title"
+		createReq.Article.Description = "test desc"
+		createReq.Article.Body = "test body"
+		body, err := json.Marshal(&createReq)
+		assert.NoError(t, err)
+
+		// claim issued before email change still says verified
+		req := httptest.NewRequest(http.MethodPost, "/api/articles", bytes.NewReader(body))
+		setAuthorization(req, 2, "test2")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+	t.Run("인증 전에 발급된 토큰으로 요청", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		createReq := CreateArticleRequest{}
+		createReq.Article.Title = "test title"
+		createReq.Article.Description = "test desc"
+		createReq.Article.Body = "test body"
+		body, err := json.Marshal(&createReq)
+		assert.NoError(t, err)
+
+		token, _ := jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")).
+			SignClaims(domain.AccessClaim{UID: 1, Username: "test"})
+		req := httptest.NewRequest(http.MethodPost, "/api/articles", bytes.NewReader(body))
+		req.Header["Authorization"] = []string{"token " + token}
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+	t.Run("scope 없는 personal access token으로 요청", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		createReq := CreateArticleRequest{}
+		createReq.Article.Title = "test title"
+		createReq.Article.Description = "test desc"
+		createReq.Article.Body = "test body"
+		body, err := json.Marshal(&createReq)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/articles", bytes.NewReader(body))
+		req.Header["Authorization"] = []string{"token " + testPersonalAccessToken}
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
 }
 
 func TestArticleController_GetArticle(t *testing.T) {
@@ -120,9 +263,15 @@ func TestArticleController_GetArticle(t *testing.T) {
 	as.EXPECT().
 		Find(gomock.Any(), "null").
 		Return(domain.ArticleView{}, ports.ErrResourceNotFound)
+	as.EXPECT().
+		Find(gomock.Any(), "old-slug").
+		Return(domain.ArticleView{
+			ID:   1,
+			Slug: "test-slug",
+		}, nil)
 
-	c := NewArticleController(as)
-	r := articleRoute(c)
+	c := NewArticleController(as, testPaginator)
+	r := articleRoute(ctrl, c)
 
 	t.Run("글 조회 성공", func(t *testing.T) {
 		w := httptest.NewRecorder()
@@ -156,6 +305,14 @@ func TestArticleController_GetArticle(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
+	t.Run("이전 slug로 글 조회", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles/old-slug", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, "/api/articles/test-slug", w.Header().Get("Location"))
+	})
 }
 
 func TestArticleController_DeleteArticle(t *testing.T) {
@@ -170,8 +327,8 @@ func TestArticleController_DeleteArticle(t *testing.T) {
 		Delete(gomock.Any(), "null").
 		Return(ports.ErrResourceNotFound)
 
-	c := NewArticleController(as)
-	r := articleRoute(c)
+	c := NewArticleController(as, testPaginator)
+	r := articleRoute(ctrl, c)
 
 	t.Run("글 삭제 성공", func(t *testing.T) {
 		w := httptest.NewRecorder()
@@ -217,8 +374,8 @@ func TestArticleController_FavoriteArticle(t *testing.T) {
 		}, nil).
 		AnyTimes()
 
-	c := NewArticleController(as)
-	r := articleRoute(c)
+	c := NewArticleController(as, testPaginator)
+	r := articleRoute(ctrl, c)
 
 	t.Run("글 좋아요 성공", func(t *testing.T) {
 		w := httptest.NewRecorder()
@@ -242,3 +399,246 @@ func TestArticleController_FavoriteArticle(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 	})
 }
+
+func TestArticleController_PublishArticle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	as := mock_ports.NewMockArticleService(ctrl)
+
+	as.EXPECT().
+		Publish(gomock.Eq(domain.Actor{ID: 1}), gomock.Eq("test-slug")).
+		Return(domain.ArticleView{
+			ID:          1,
+			Slug:        "test-slug",
+			Status:      domain.StatusPublished,
+			PublishedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}, nil)
+	as.EXPECT().
+		Publish(gomock.Eq(domain.Actor{ID: 2}), gomock.Eq("test-slug")).
+		Return(domain.ArticleView{}, ports.ErrNonOwnedContent)
+
+	c := NewArticleController(as, testPaginator)
+	r := articleRoute(ctrl, c)
+
+	t.Run("글 발행 성공", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/articles/test-slug/publish", nil)
+		setAuthorization(req, 1, "test")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		resp := ArticleResponse{}
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, "published", resp.Article.Status)
+		assert.NotNil(t, resp.Article.PublishedAt)
+	})
+	t.Run("다른 유저의 글 발행", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/articles/test-slug/publish", nil)
+		setAuthorization(req, 2, "test2")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestArticleController_Revisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	as := mock_ports.NewMockArticleService(ctrl)
+
+	as.EXPECT().
+		ListRevisions(gomock.Eq(uint(0)), gomock.Eq("test-slug")).
+		Return([]domain.ArticleRevision{
+			{Number: 1, Title: "title"},
+			{Number: 2, Title: "title", RestoredFrom: sql.NullInt32{Int32: 1, Valid: true}},
+		}, nil)
+	as.EXPECT().
+		FindRevision(gomock.Eq(uint(0)), gomock.Eq("test-slug"), gomock.Eq(2)).
+		Return(domain.ArticleRevision{Number: 2, Title: "title"}, nil)
+	as.EXPECT().
+		DiffRevisions(gomock.Eq(uint(0)), gomock.Eq("test-slug"), gomock.Eq(1), gomock.Eq(2)).
+		Return(domain.RevisionDiff{From: 1, To: 2, Unified: "--- revisions/1/body"}, nil)
+
+	c := NewArticleController(as, testPaginator)
+	r := articleRoute(ctrl, c)
+
+	t.Run("리비전 목록 조회", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles/test-slug/revisions", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		resp := MultipleArticleRevisionsResponse{}
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Len(t, resp.Revisions, 2)
+		assert.Nil(t, resp.Revisions[0].RestoredFrom)
+		assert.Equal(t, 1, *resp.Revisions[1].RestoredFrom)
+	})
+	t.Run("리비전 조회", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles/test-slug/revisions/2", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		resp := ArticleRevisionResponse{}
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, resp.Revision.Number)
+	})
+	t.Run("잘못된 리비전 번호", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles/test-slug/revisions/0", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("리비전 비교", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles/test-slug/revisions/diff?from=1&to=2", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		resp := RevisionDiffResponse{}
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, "--- revisions/1/body", resp.Diff.Unified)
+	})
+}
+
+func TestArticleController_ListArticles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	as := mock_ports.NewMockArticleService(ctrl)
+	query := "dragons"
+	createdAt := time.Date(2023, 7, 1, 12, 0, 0, 123456789, time.UTC)
+
+	as.EXPECT().
+		ListByConditions(gomock.Any(), gomock.Eq(ports.ArticleSearchConditions{
+			Query:    &query,
+			Pageable: ports.Pageable{Limit: 20},
+		})).
+		Return([]domain.ArticleView{{ID: 1, Slug: "test-slug", Snippet: "<mark>dragons</mark>"}}, ports.Page{Total: 21, HasNext: true}, nil)
+	as.EXPECT().
+		ListByConditions(gomock.Any(), gomock.Eq(ports.ArticleSearchConditions{
+			Pageable: ports.Pageable{Limit: 20},
+		})).
+		Return([]domain.ArticleView{{ID: 1, Slug: "test-slug"}}, ports.Page{Total: 1}, nil)
+	as.EXPECT().
+		ListByConditions(gomock.Any(), gomock.Eq(ports.ArticleSearchConditions{
+			Pageable: ports.Pageable{Limit: 2},
+		})).
+		Return([]domain.ArticleView{
+			{ID: 3, Slug: "test-slug-3", CreatedAt: createdAt.Add(time.Second)},
+			{ID: 2, Slug: "test-slug-2", CreatedAt: createdAt},
+		}, ports.Page{Total: 3, HasNext: true}, nil)
+	as.EXPECT().
+		ListByConditions(gomock.Any(), gomock.Eq(ports.ArticleSearchConditions{
+			Pageable: ports.Pageable{Limit: 2, Cursor: &ports.Cursor{CreatedAt: createdAt, ID: 2}},
+		})).
+		Return([]domain.ArticleView{
+			{ID: 1, Slug: "test-slug-1", CreatedAt: createdAt.Add(-time.Second)},
+		}, ports.Page{Total: 3, HasPrev: true}, nil)
+
+	as.EXPECT().
+		ListByConditions(gomock.Any(), gomock.Eq(ports.ArticleSearchConditions{
+			Tags:        []string{"go", "gin"},
+			TagMatch:    ports.TagMatchAll,
+			ExcludeTags: []string{"draft"},
+			Authors:     []string{"test1", "test2"},
+			Pageable:    ports.Pageable{Limit: 20},
+		})).
+		Return(nil, ports.Page{}, nil)
+
+	c := NewArticleController(as, testPaginator)
+	r := articleRoute(ctrl, c)
+
+	t.Run("여러 필터 조합", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles?tag=go&tag=gin&tagMatch=all&excludeTag=draft&author=test1&author=test2", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+	t.Run("로그인하지 않고 팔로우한 작성자 글 조회", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles?followed=true", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+	t.Run("전문 검색", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles?q=dragons", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		resp := MultipleArticlesResponse{}
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, "<mark>dragons</mark>", resp.Articles[0].Snippet)
+		assert.Equal(t, 21, resp.ArticlesCount)
+		// articles ordered by rank can not be found by cursor
+		assert.Nil(t, resp.NextCursor)
+	})
+	t.Run("빈 검색어는 무시", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles?q=+", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+	t.Run("nextCursor로 다음 페이지 조회", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles?limit=2", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		resp := MultipleArticlesResponse{}
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Nil(t, resp.PrevCursor)
+		assert.NotNil(t, resp.NextCursor)
+
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "/api/articles?limit=2&cursor="+*resp.NextCursor, nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		resp = MultipleArticlesResponse{}
+		err = json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, "test-slug-1", resp.Articles[0].Slug)
+		assert.NotNil(t, resp.PrevCursor)
+		assert.Nil(t, resp.NextCursor)
+
+		// cursor points to position in the listing it was issued for
+		for _, query := range []string{"&sort=oldest", "&sort=mostFavorited", "&tag=go", "&author=test1"} {
+			w = httptest.NewRecorder()
+			req = httptest.NewRequest(http.MethodGet, "/api/articles?limit=2&cursor="+*resp.PrevCursor+query, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+	t.Run("지원하지 않는 정렬", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles?sort=popular", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("위조된 cursor", func(t *testing.T) {
+		forged, _ := cursor.New([]byte("other-key")).Encode(map[string]any{"t": createdAt, "i": 2})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles?limit=2&cursor="+forged, nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
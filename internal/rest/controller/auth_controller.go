@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"errors"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
 	"github.com/KumKeeHyun/gin-realworld/internal/rest/middleware"
 	"github.com/gin-gonic/gin"
@@ -28,8 +30,40 @@ func (c *AuthController) AuthenticateUser(ctx *gin.Context) {
 		ctx.Error(err)
 		return
 	}
-	user, err := c.authService.Login(request.User.Email, request.User.Password)
+	user, err := c.authService.Login(request.User.Email, request.User.Password, ctx.ClientIP())
 	if err != nil {
+		middleware.ObserveFailedLogin(err)
+		ctx.Error(err)
+		return
+	}
+	loginResponse(ctx, user)
+}
+
+func loginResponse(ctx *gin.Context, user domain.User) {
+	if user.MFAChallenge != "" {
+		ctx.JSON(http.StatusOK, MFAChallengeToResp(user.MFAChallenge))
+		return
+	}
+	ctx.JSON(http.StatusOK, UserToResp(user))
+}
+
+type AuthenticateMFARequest struct {
+	MFA struct {
+		Challenge string `json:"challenge" binding:"required"`
+		Code      string `json:"code" binding:"required"`
+	} `json:"mfa" binding:"required"`
+}
+
+// AuthenticateMFA runs without transaction, so that challenge is used up even if code is wrong.
+func (c *AuthController) AuthenticateMFA(ctx *gin.Context) {
+	request := AuthenticateMFARequest{}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+	user, err := c.authService.LoginMFA(request.MFA.Challenge, request.MFA.Code)
+	if err != nil {
+		middleware.ObserveFailedLogin(err)
 		ctx.Error(err)
 		return
 	}
@@ -59,6 +93,154 @@ func (c *AuthController) RegisterUser(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, UserToResp(user))
 }
 
+type RefreshTokenRequest struct {
+	User struct {
+		RefreshToken string `json:"refreshToken" binding:"required"`
+	} `json:"user" binding:"required"`
+}
+
+func (c *AuthController) RefreshToken(ctx *gin.Context) {
+	request := RefreshTokenRequest{}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// old token is revoked and new one is saved together, otherwise failed rotation looks like reuse
+	user, err := c.authService.WithTx(tx).Refresh(request.User.RefreshToken)
+	if errors.Is(err, ports.ErrInvalidRefreshToken) {
+		// sessions revoked on reuse of rotated token must stay revoked
+		middleware.CommitOnError(ctx)
+	}
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, UserToResp(user))
+}
+
+func (c *AuthController) Logout(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = c.authService.WithTx(tx).Logout(claim)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+type PasswordResetRequest struct {
+	User struct {
+		Email string `json:"email" binding:"required"`
+	} `json:"user" binding:"required"`
+}
+
+func (c *AuthController) RequestPasswordReset(ctx *gin.Context) {
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	request := PasswordResetRequest{}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = c.authService.WithTx(tx).RequestPasswordReset(request.User.Email)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+type ConfirmPasswordResetRequest struct {
+	User struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	} `json:"user" binding:"required"`
+}
+
+func (c *AuthController) ConfirmPasswordReset(ctx *gin.Context) {
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	request := ConfirmPasswordResetRequest{}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = c.authService.WithTx(tx).ResetPassword(request.User.Token, request.User.Password)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+type VerifyEmailQuery struct {
+	Token string `form:"token" binding:"required"`
+}
+
+func (c *AuthController) VerifyEmail(ctx *gin.Context) {
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	request := VerifyEmailQuery{}
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = c.authService.WithTx(tx).Verify(request.Token)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+func (c *AuthController) ResendVerification(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = c.authService.ResendVerification(claim.UID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
 func (c *AuthController) GetCurrentUser(ctx *gin.Context) {
 	claim, err := middleware.GetAccessClaim(ctx)
 	if err != nil {
@@ -117,3 +299,156 @@ func (c *AuthController) UpdateUser(ctx *gin.Context) {
 	}
 	ctx.JSON(http.StatusOK, UserToResp(user))
 }
+
+func (c *AuthController) ListExternalProviders(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, ExternalProvidersResponse{Providers: c.authService.ExternalProviders()})
+}
+
+func (c *AuthController) BeginExternalLogin(ctx *gin.Context) {
+	authURL, err := c.authService.BeginExternalLogin(ctx.Param("provider"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Redirect(http.StatusFound, authURL)
+}
+
+type ExternalLoginCallbackQuery struct {
+	State string `form:"state" binding:"required"`
+	Code  string `form:"code"`
+	Error string `form:"error"`
+}
+
+func (c *AuthController) CompleteExternalLogin(ctx *gin.Context) {
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	request := ExternalLoginCallbackQuery{}
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+	// user denied consent or provider rejected the request
+	if request.Error != "" || request.Code == "" {
+		ctx.Error(ports.ErrExternalLogin)
+		return
+	}
+
+	user, err := c.authService.WithTx(tx).CompleteExternalLogin(ctx.Param("provider"), request.State, request.Code)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	loginResponse(ctx, user)
+}
+
+func (c *AuthController) EnrollTOTP(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	enrollment, err := c.authService.WithTx(tx).EnrollTOTP(claim.UID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, TOTPEnrollmentToResp(enrollment))
+}
+
+type MFACodeRequest struct {
+	MFA struct {
+		Code string `json:"code" binding:"required"`
+	} `json:"mfa" binding:"required"`
+}
+
+func (c *AuthController) ConfirmTOTP(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	request := MFACodeRequest{}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	codes, err := c.authService.WithTx(tx).ConfirmTOTP(claim.UID, request.MFA.Code)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (c *AuthController) DisableTOTP(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	request := MFACodeRequest{}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = c.authService.WithTx(tx).DisableTOTP(claim.UID, request.MFA.Code)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+func (c *AuthController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	request := MFACodeRequest{}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	codes, err := c.authService.WithTx(tx).RegenerateRecoveryCodes(claim.UID, request.MFA.Code)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
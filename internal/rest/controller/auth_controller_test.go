@@ -4,11 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/ports/mock_ports"
 	"github.com/KumKeeHyun/gin-realworld/internal/rest/middleware"
-	"github.com/KumKeeHyun/gin-realworld/pkg/jwtutil"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
@@ -18,18 +17,22 @@ import (
 	"testing"
 )
 
-func authRoute(authController *AuthController) *gin.Engine {
+func authRoute(ctrl *gomock.Controller, authController *AuthController) *gin.Engine {
 	logger := zap.NewNop()
 	errorHandler := middleware.NewErrorsMiddleware(logger).GinHandlerFunc()
-	checkJwt := middleware.NewCheckJwtMiddleware(jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), logger).GinHandlerFunc()
+	checkJwt := checkJwtHandler(ctrl, logger)
 	ensureAuth := middleware.NewEnsureAuthMiddleware(logger).GinHandlerFunc()
 	ensureNotAuth := middleware.NewEnsureNotAuthMiddleware(logger).GinHandlerFunc()
+	transaction := middleware.NewTransactionMiddleware(testDB(), logger).GinHandlerFunc()
 
 	r := gin.New()
 	api := r.Group("api", errorHandler, checkJwt)
 	users := api.Group("users")
 	users.POST("/login", ensureNotAuth, authController.AuthenticateUser)
+	users.POST("/login/mfa", ensureNotAuth, authController.AuthenticateMFA)
 	users.POST("", ensureNotAuth, authController.RegisterUser)
+	users.POST("/refresh", transaction, authController.RefreshToken)
+	users.GET("/oauth/:provider", ensureNotAuth, authController.BeginExternalLogin)
 
 	user := api.Group("user")
 	user.GET("", ensureAuth, authController.GetCurrentUser)
@@ -41,7 +44,7 @@ func TestAuthController_AuthenticateUser(t *testing.T) {
 	as := mock_ports.NewMockAuthService(ctrl)
 
 	as.EXPECT().
-		Login(gomock.Eq("test@example.com"), gomock.Eq("test-password")).
+		Login(gomock.Eq("test@example.com"), gomock.Eq("test-password"), gomock.Any()).
 		Return(domain.User{
 			Model: gorm.Model{ID: 1},
 			Email: "test@example.com",
@@ -49,7 +52,7 @@ func TestAuthController_AuthenticateUser(t *testing.T) {
 		}, nil)
 
 	c := NewAuthController(as)
-	r := authRoute(c)
+	r := authRoute(ctrl, c)
 
 	t.Run("로그인 성공", func(t *testing.T) {
 		w := httptest.NewRecorder()
@@ -72,6 +75,80 @@ func TestAuthController_AuthenticateUser(t *testing.T) {
 	})
 }
 
+func TestAuthController_AuthenticateMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	as := mock_ports.NewMockAuthService(ctrl)
+
+	as.EXPECT().
+		Login(gomock.Eq("mfa@example.com"), gomock.Eq("test-password"), gomock.Any()).
+		Return(domain.User{MFAChallenge: "test-challenge"}, nil)
+	as.EXPECT().
+		LoginMFA(gomock.Eq("test-challenge"), gomock.Eq("123456")).
+		Return(domain.User{
+			Model: gorm.Model{ID: 1},
+			Email: "mfa@example.com",
+			Token: "test-token",
+		}, nil)
+	as.EXPECT().
+		LoginMFA(gomock.Eq("test-challenge"), gomock.Eq("000000")).
+		Return(domain.User{}, ports.ErrInvalidMFACode)
+
+	c := NewAuthController(as)
+	r := authRoute(ctrl, c)
+
+	t.Run("2단계 인증 사용자 로그인", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		authReq := AuthenticateUserRequest{}
+		authReq.User.Email = "mfa@example.com"
+		authReq.User.Password = "test-password"
+		body, err := json.Marshal(&authReq)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/users/login", bytes.NewReader(body))
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		resp := MFAChallengeResponse{}
+		err = json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, "test-challenge", resp.MFA.Challenge)
+		assert.Equal(t, int(domain.MFAChallengeTTL.Seconds()), resp.MFA.ExpiresIn)
+	})
+	t.Run("2단계 인증 성공", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		mfaReq := AuthenticateMFARequest{}
+		mfaReq.MFA.Challenge = "test-challenge"
+		mfaReq.MFA.Code = "123456"
+		body, err := json.Marshal(&mfaReq)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/users/login/mfa", bytes.NewReader(body))
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		resp := UserResponse{}
+		err = json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, "test-token", resp.User.Token)
+	})
+	t.Run("틀린 코드", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		mfaReq := AuthenticateMFARequest{}
+		mfaReq.MFA.Challenge = "test-challenge"
+		mfaReq.MFA.Code = "000000"
+		body, err := json.Marshal(&mfaReq)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/users/login/mfa", bytes.NewReader(body))
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
 func TestAuthController_RegisterUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	as := mock_ports.NewMockAuthService(ctrl)
@@ -87,7 +164,7 @@ func TestAuthController_RegisterUser(t *testing.T) {
 		AnyTimes()
 
 	c := NewAuthController(as)
-	r := authRoute(c)
+	r := authRoute(ctrl, c)
 
 	t.Run("회원가입 성공", func(t *testing.T) {
 		w := httptest.NewRecorder()
@@ -126,12 +203,65 @@ func TestAuthController_RegisterUser(t *testing.T) {
 	})
 }
 
+func TestAuthController_RefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	as := mock_ports.NewMockAuthService(ctrl)
+
+	as.EXPECT().WithTx(gomock.Any()).Return(as).AnyTimes()
+	as.EXPECT().
+		Refresh(gomock.Eq("test-refresh-token")).
+		Return(domain.User{
+			Model:        gorm.Model{ID: 1},
+			Email:        "test@example.com",
+			Token:        "new-token",
+			RefreshToken: "new-refresh-token",
+		}, nil)
+	as.EXPECT().
+		Refresh(gomock.Eq("invalid-refresh-token")).
+		Return(domain.User{}, ports.ErrInvalidRefreshToken)
+
+	c := NewAuthController(as)
+	r := authRoute(ctrl, c)
+
+	t.Run("토큰 갱신 성공", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		refreshReq := RefreshTokenRequest{}
+		refreshReq.User.RefreshToken = "test-refresh-token"
+		body, err := json.Marshal(&refreshReq)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/users/refresh", bytes.NewReader(body))
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		resp := UserResponse{}
+		err = json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, "new-token", resp.User.Token)
+		assert.Equal(t, "new-refresh-token", resp.User.RefreshToken)
+	})
+	t.Run("잘못된 토큰으로 갱신", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		refreshReq := RefreshTokenRequest{}
+		refreshReq.User.RefreshToken = "invalid-refresh-token"
+		body, err := json.Marshal(&refreshReq)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/users/refresh", bytes.NewReader(body))
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
 func TestAuthController_GetCurrentUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	as := mock_ports.NewMockAuthService(ctrl)
 
 	c := NewAuthController(as)
-	r := authRoute(c)
+	r := authRoute(ctrl, c)
 
 	t.Run("유저 정보 조회 성공", func(t *testing.T) {
 		w := httptest.NewRecorder()
@@ -147,3 +277,36 @@ func TestAuthController_GetCurrentUser(t *testing.T) {
 		assert.Equal(t, "test", resp.User.Username)
 	})
 }
+
+func TestAuthController_BeginExternalLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	as := mock_ports.NewMockAuthService(ctrl)
+
+	as.EXPECT().
+		BeginExternalLogin(gomock.Eq("test")).
+		Return("https://idp.example.com/authorize?state=test-state", nil)
+	as.EXPECT().
+		BeginExternalLogin(gomock.Eq("null")).
+		Return("", ports.ErrUnknownProvider)
+
+	c := NewAuthController(as)
+	r := authRoute(ctrl, c)
+
+	t.Run("provider로 리다이렉트", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/users/oauth/test", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, "https://idp.example.com/authorize?state=test-state", w.Header().Get("Location"))
+	})
+	t.Run("없는 provider", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/users/oauth/null", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
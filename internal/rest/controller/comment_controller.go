@@ -9,17 +9,21 @@ import (
 
 type CommentController struct {
 	commentService ports.CommentService
+	paginator      *Paginator
 }
 
-func NewCommentController(commentService ports.CommentService) *CommentController {
+func NewCommentController(commentService ports.CommentService, paginator *Paginator) *CommentController {
 	return &CommentController{
 		commentService: commentService,
+		paginator:      paginator,
 	}
 }
 
 type AddCommentRequest struct {
 	Comment struct {
 		Body string `json:"body" binding:"required"`
+		// ParentID is id of comment to reply to
+		ParentID *uint `json:"parentId"`
 	} `json:"comment" binding:"required"`
 }
 
@@ -41,7 +45,13 @@ func (c *CommentController) AddCommentToArticle(ctx *gin.Context) {
 		return
 	}
 
-	created, err := c.commentService.Create(claim.UID, requestUri.Slug, request.Comment.Body)
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	created, err := c.commentService.WithTx(tx).Create(claim.UID, requestUri.Slug, request.Comment.Body, request.Comment.ParentID)
 	if err != nil {
 		ctx.Error(err)
 		return
@@ -49,6 +59,37 @@ func (c *CommentController) AddCommentToArticle(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, CommentViewToResponse(created))
 }
 
+// ListCommentsQuery returns the first 20 comments by default, client which listed every comment at once should paginate.
+type ListCommentsQuery struct {
+	Limit  int `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset int `form:"offset,default=0" binding:"min=0"`
+	// Cursor is nextCursor or prevCursor of previous response, Offset is ignored if it is set.
+	// It can only be used with oldest or newest Sort.
+	Cursor string `form:"cursor"`
+	// Sort is the oldest first by default, top orders by the number of replies
+	Sort string `form:"sort" binding:"omitempty,oneof=oldest newest top"`
+	// Layout is flat by default, tree paginates top level comments with nested replies
+	Layout string `form:"layout" binding:"omitempty,oneof=flat tree"`
+	// ParentID paginates replies of the comment instead, to continue thread cut in tree layout
+	ParentID *uint `form:"parentId" binding:"omitempty,min=1"`
+}
+
+// CursorScope identifies listing of comments of article by sort and filters of query, regardless of page.
+func (q ListCommentsQuery) CursorScope(slug string) CursorScope {
+	sort := q.Sort
+	if sort == "" {
+		sort = string(ports.CommentSortOldest)
+	}
+	return CursorScope{
+		Sort: sort,
+		Filters: struct {
+			Slug     string
+			Layout   string
+			ParentID *uint
+		}{slug, q.Layout, q.ParentID},
+	}
+}
+
 func (c *CommentController) GetCommentsFromArticle(ctx *gin.Context) {
 	claim, _ := middleware.GetAccessClaim(ctx)
 
@@ -57,12 +98,34 @@ func (c *CommentController) GetCommentsFromArticle(ctx *gin.Context) {
 		ctx.Error(err)
 		return
 	}
-	comments, err := c.commentService.GetFromArticle(claim.UID, requestUri.Slug)
+	var request ListCommentsQuery
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+	scope := request.CursorScope(requestUri.Slug)
+	pageable, err := c.paginator.Pageable(request.Limit, request.Offset, request.Cursor, scope)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	options := ports.CommentListOptions{
+		Layout:   ports.CommentLayout(request.Layout),
+		Sort:     ports.CommentSort(request.Sort),
+		ParentID: request.ParentID,
+	}
+	comments, page, err := c.commentService.GetFromArticle(claim.UID, requestUri.Slug, options, pageable)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, CommentsToResponse(comments))
+	resp := CommentsToResponse(comments, page.Total)
+	if options.Sort.OrderedByCreation() {
+		first, last := commentCursors(comments)
+		resp.PrevCursor, resp.NextCursor = c.paginator.Cursors(page, first, last, scope)
+	}
+	ctx.JSON(http.StatusOK, resp)
 }
 
 type CommentUri struct {
@@ -70,6 +133,65 @@ type CommentUri struct {
 	ID   uint   `uri:"id" binding:"required"`
 }
 
+type UpdateCommentRequest struct {
+	Comment struct {
+		Body string `json:"body" binding:"required"`
+	} `json:"comment" binding:"required"`
+}
+
+func (c *CommentController) UpdateComment(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var requestUri CommentUri
+	if err := ctx.ShouldBindUri(&requestUri); err != nil {
+		ctx.Error(err)
+		return
+	}
+	var request UpdateCommentRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	tx, err := middleware.GetTransaction(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	updated, err := c.commentService.WithTx(tx).Update(claim.Actor(), requestUri.Slug, requestUri.ID, request.Comment.Body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, CommentViewToResponse(updated))
+}
+
+func (c *CommentController) ListCommentRevisions(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var requestUri CommentUri
+	if err := ctx.ShouldBindUri(&requestUri); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	revisions, err := c.commentService.ListRevisions(claim.Actor(), requestUri.Slug, requestUri.ID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, CommentRevisionsToResponse(revisions))
+}
+
 func (c *CommentController) DeleteComment(ctx *gin.Context) {
 	claim, err := middleware.GetAccessClaim(ctx)
 	if err != nil {
@@ -83,7 +205,7 @@ func (c *CommentController) DeleteComment(ctx *gin.Context) {
 		return
 	}
 
-	err = c.commentService.Delete(claim.UID, requestUri.ID)
+	err = c.commentService.Delete(claim.Actor(), requestUri.ID)
 	if err != nil {
 		ctx.Error(err)
 		return
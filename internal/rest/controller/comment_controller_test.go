@@ -4,11 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/ports/mock_ports"
 	"github.com/KumKeeHyun/gin-realworld/internal/rest/middleware"
-	"github.com/KumKeeHyun/gin-realworld/pkg/jwtutil"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
@@ -17,18 +17,21 @@ import (
 	"testing"
 )
 
-func commentRoute(commentController *CommentController) *gin.Engine {
+func commentRoute(ctrl *gomock.Controller, commentController *CommentController) *gin.Engine {
 	logger := zap.NewNop()
 	errorHandler := middleware.NewErrorsMiddleware(logger).GinHandlerFunc()
-	checkJwt := middleware.NewCheckJwtMiddleware(jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), logger).GinHandlerFunc()
+	checkJwt := checkJwtHandler(ctrl, logger)
 	ensureAuth := middleware.NewEnsureAuthMiddleware(logger).GinHandlerFunc()
+	requireScope := middleware.NewScopeMiddleware(logger).RequireScope
+	transaction := middleware.NewTransactionMiddleware(testDB(), logger).GinHandlerFunc()
 
 	r := gin.New()
 	api := r.Group("api", errorHandler, checkJwt)
 	articles := api.Group("articles")
 	comments := articles.Group(":slug/comments")
-	comments.POST("", ensureAuth, commentController.AddCommentToArticle)
+	comments.POST("", ensureAuth, requireScope(domain.ScopeCommentsWrite), transaction, commentController.AddCommentToArticle)
 	comments.GET("", commentController.GetCommentsFromArticle)
+	comments.GET("/:id/revisions", ensureAuth, commentController.ListCommentRevisions)
 	comments.DELETE("/:id", ensureAuth, commentController.DeleteComment)
 
 	return r
@@ -38,8 +41,9 @@ func TestCommentController_AddCommentToArticle(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	cs := mock_ports.NewMockCommentService(ctrl)
 
+	cs.EXPECT().WithTx(gomock.Any()).Return(cs).AnyTimes()
 	cs.EXPECT().
-		Create(gomock.Eq(uint(1)), gomock.Eq("test-slug"), gomock.Eq("test body")).
+		Create(gomock.Eq(uint(1)), gomock.Eq("test-slug"), gomock.Eq("test body"), gomock.Nil()).
 		Return(domain.CommentView{
 			ID:              1,
 			Body:            "test-body",
@@ -49,9 +53,19 @@ func TestCommentController_AddCommentToArticle(t *testing.T) {
 			AuthorFollowing: false,
 		}, nil).
 		AnyTimes()
+	cs.EXPECT().
+		Create(gomock.Eq(uint(1)), gomock.Eq("test-slug"), gomock.Eq("test reply"), gomock.Eq(lo.ToPtr(uint(1)))).
+		Return(domain.CommentView{
+			ID:             2,
+			Body:           "test reply",
+			ArticleID:      1,
+			ParentID:       lo.ToPtr(uint(1)),
+			AuthorID:       1,
+			AuthorUsername: "test",
+		}, nil)
 
-	c := NewCommentController(cs)
-	r := commentRoute(c)
+	c := NewCommentController(cs, testPaginator)
+	r := commentRoute(ctrl, c)
 
 	t.Run("댓글 작성 성공", func(t *testing.T) {
 		w := httptest.NewRecorder()
@@ -71,6 +85,111 @@ func TestCommentController_AddCommentToArticle(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, "test-body", resp.Comment.Body)
 	})
+	t.Run("personal access token으로 댓글 작성", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		commentReq := AddCommentRequest{}
+		commentReq.Comment.Body = "test body"
+		body, err := json.Marshal(&commentReq)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/articles/test-slug/comments", bytes.NewReader(body))
+		req.Header["Authorization"] = []string{"token " + testPersonalAccessToken}
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+	t.Run("답글 작성 성공", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		body := []byte(`{"comment":{"body":"test reply","parentId":1}}`)
+		req := httptest.NewRequest(http.MethodPost, "/api/articles/test-slug/comments", bytes.NewReader(body))
+		setAuthorization(req, 1, "test")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		resp := CommentResponse{}
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, lo.ToPtr(uint(1)), resp.Comment.ParentId)
+	})
+}
+
+func TestCommentController_GetCommentsFromArticle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cs := mock_ports.NewMockCommentService(ctrl)
+
+	cs.EXPECT().
+		GetFromArticle(gomock.Eq(uint(0)), gomock.Eq("test-slug"), gomock.Eq(ports.CommentListOptions{Layout: ports.LayoutTree}), gomock.Eq(ports.Pageable{Limit: 20})).
+		Return([]domain.CommentView{
+			{
+				ID:             1,
+				Body:           domain.DeletedCommentBody,
+				Tombstoned:     true,
+				AuthorUsername: domain.DeletedCommentBody,
+				Replies: []domain.CommentView{
+					{ID: 2, Body: "test reply", ParentID: lo.ToPtr(uint(1)), AuthorUsername: "test"},
+				},
+			},
+		}, ports.Page{Total: 1}, nil)
+	cs.EXPECT().
+		GetFromArticle(gomock.Eq(uint(0)), gomock.Eq("test-slug"), gomock.Eq(ports.CommentListOptions{Sort: ports.CommentSortTop}), gomock.Eq(ports.Pageable{Limit: 1})).
+		Return([]domain.CommentView{
+			{ID: 2, Body: "most replied", AuthorUsername: "test"},
+		}, ports.Page{Total: 3, HasNext: true}, nil)
+
+	c := NewCommentController(cs, testPaginator)
+	r := commentRoute(ctrl, c)
+
+	t.Run("댓글을 트리로 조회", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles/test-slug/comments?layout=tree", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		resp := MultipleCommentsResponse{}
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Len(t, resp.Comments, 1)
+		assert.True(t, resp.Comments[0].Deleted)
+		assert.Len(t, resp.Comments[0].Replies, 1)
+		assert.Equal(t, "test reply", resp.Comments[0].Replies[0].Body)
+	})
+	t.Run("답글 순 정렬", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles/test-slug/comments?sort=top&limit=1", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		resp := MultipleCommentsResponse{}
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, resp.CommentsCount)
+		assert.Equal(t, "most replied", resp.Comments[0].Body)
+		// cursor can not point to comment ordered by replies
+		assert.Nil(t, resp.NextCursor)
+	})
+	t.Run("잘못된 정렬", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles/test-slug/comments?sort=best", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("최대 개수 초과", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles/test-slug/comments?limit=1000", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("잘못된 layout", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles/test-slug/comments?layout=nested", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 }
 
 func TestCommentController_DeleteComment(t *testing.T) {
@@ -78,12 +197,12 @@ func TestCommentController_DeleteComment(t *testing.T) {
 	cs := mock_ports.NewMockCommentService(ctrl)
 
 	cs.EXPECT().
-		Delete(gomock.Eq(uint(1)), gomock.Eq(uint(1))).
+		Delete(gomock.Eq(domain.Actor{ID: 1}), gomock.Eq(uint(1))).
 		Return(nil).
 		AnyTimes()
 
-	c := NewCommentController(cs)
-	r := commentRoute(c)
+	c := NewCommentController(cs, testPaginator)
+	r := commentRoute(ctrl, c)
 
 	t.Run("댓글 삭제 성공", func(t *testing.T) {
 		w := httptest.NewRecorder()
@@ -101,3 +220,42 @@ func TestCommentController_DeleteComment(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 	})
 }
+
+func TestCommentController_ListCommentRevisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cs := mock_ports.NewMockCommentService(ctrl)
+
+	cs.EXPECT().
+		ListRevisions(gomock.Eq(domain.Actor{ID: 1, Role: domain.RoleModerator}), gomock.Eq("test-slug"), gomock.Eq(uint(1))).
+		Return([]domain.CommentRevision{
+			{CommentID: 1, Body: "first body", EditorID: 2},
+		}, nil)
+	cs.EXPECT().
+		ListRevisions(gomock.Eq(domain.Actor{ID: 2, Role: domain.RoleUser}), gomock.Eq("test-slug"), gomock.Eq(uint(1))).
+		Return(nil, ports.ErrPermissionDenied)
+
+	c := NewCommentController(cs, testPaginator)
+	r := commentRoute(ctrl, c)
+
+	t.Run("모더레이터의 수정 이력 조회", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles/test-slug/comments/1/revisions", nil)
+		setRoleAuthorization(req, 1, "moderator", domain.RoleModerator)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		resp := MultipleCommentRevisionsResponse{}
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Len(t, resp.Revisions, 1)
+		assert.Equal(t, "first body", resp.Revisions[0].Body)
+	})
+	t.Run("일반 유저의 수정 이력 조회", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/articles/test-slug/comments/1/revisions", nil)
+		setRoleAuthorization(req, 2, "test", domain.RoleUser)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
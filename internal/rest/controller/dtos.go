@@ -28,11 +28,13 @@ func (t *JSONTime) UnmarshalJSON(data []byte) error {
 
 type UserResponse struct {
 	User struct {
-		Email    string  `json:"email"`
-		Token    string  `json:"token"`
-		Username string  `json:"username"`
-		Bio      string  `json:"bio"`
-		Image    *string `json:"image"`
+		Email        string  `json:"email"`
+		Token        string  `json:"token"`
+		RefreshToken string  `json:"refreshToken,omitempty"`
+		Username     string  `json:"username"`
+		Bio          string  `json:"bio"`
+		Image        *string `json:"image"`
+		Verified     bool    `json:"verified"`
 	} `json:"user"`
 }
 
@@ -40,9 +42,11 @@ func UserToResp(user domain.User) UserResponse {
 	var resp UserResponse
 	resp.User.Email = user.Email
 	resp.User.Token = user.Token
+	resp.User.RefreshToken = user.RefreshToken
 	resp.User.Username = user.Username
 	resp.User.Bio = user.Bio
 	resp.User.Image = lo.If(user.Image.Valid, &user.Image.String).Else(nil)
+	resp.User.Verified = user.Verified()
 	return resp
 }
 
@@ -53,9 +57,47 @@ func ClaimToUserResp(claim domain.AccessClaim, token string) UserResponse {
 	resp.User.Username = claim.Username
 	resp.User.Bio = claim.Bio
 	resp.User.Image = claim.Image
+	resp.User.Verified = claim.Verified
 	return resp
 }
 
+// MFAChallengeResponse is returned by login instead of UserResponse, when user enabled two factor authentication.
+type MFAChallengeResponse struct {
+	MFA struct {
+		Challenge string `json:"challenge"`
+		ExpiresIn int    `json:"expiresIn"`
+	} `json:"mfa"`
+}
+
+func MFAChallengeToResp(challenge string) MFAChallengeResponse {
+	var resp MFAChallengeResponse
+	resp.MFA.Challenge = challenge
+	resp.MFA.ExpiresIn = int(domain.MFAChallengeTTL.Seconds())
+	return resp
+}
+
+type TOTPEnrollmentResponse struct {
+	TOTP struct {
+		Secret     string `json:"secret"`
+		OtpauthURI string `json:"otpauthUri"`
+	} `json:"totp"`
+}
+
+func TOTPEnrollmentToResp(enrollment domain.TOTPEnrollment) TOTPEnrollmentResponse {
+	var resp TOTPEnrollmentResponse
+	resp.TOTP.Secret = enrollment.Secret
+	resp.TOTP.OtpauthURI = enrollment.URI
+	return resp
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type ExternalProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
+
 type ProfileResponse struct {
 	Profile struct {
 		Username  string  `json:"username"`
@@ -77,15 +119,19 @@ func ProfileToResp(profile domain.Profile) ProfileResponse {
 }
 
 type Article struct {
-	Slug           string   `json:"slug"`
-	Title          string   `json:"title"`
-	Description    string   `json:"description"`
-	Body           string   `json:"body"`
-	TagList        []string `json:"tagList"`
-	CreatedAt      JSONTime `json:"createdAt"`
-	UpdatedAt      JSONTime `json:"updatedAt"`
-	Favorited      bool     `json:"favorited"`
-	FavoritesCount int      `json:"favoritesCount"`
+	Slug           string    `json:"slug"`
+	Title          string    `json:"title"`
+	Description    string    `json:"description"`
+	Body           string    `json:"body"`
+	TagList        []string  `json:"tagList"`
+	CreatedAt      JSONTime  `json:"createdAt"`
+	UpdatedAt      JSONTime  `json:"updatedAt"`
+	Favorited      bool      `json:"favorited"`
+	FavoritesCount int       `json:"favoritesCount"`
+	Status         string    `json:"status"`
+	PublishedAt    *JSONTime `json:"publishedAt"`
+	PublishAt      *JSONTime `json:"publishAt,omitempty"`
+	Snippet        string    `json:"snippet,omitempty"`
 	Author         struct {
 		Username  string  `json:"username"`
 		Bio       string  `json:"bio"`
@@ -105,6 +151,16 @@ func ArticleViewToDto(article domain.ArticleView) Article {
 	a.UpdatedAt = JSONTime(article.UpdatedAt)
 	a.Favorited = article.Favorited
 	a.FavoritesCount = article.FavoritesCount
+	a.Status = string(article.Status)
+	if article.PublishedAt.Valid {
+		publishedAt := JSONTime(article.PublishedAt.Time)
+		a.PublishedAt = &publishedAt
+	}
+	if article.PublishAt.Valid {
+		publishAt := JSONTime(article.PublishAt.Time)
+		a.PublishAt = &publishAt
+	}
+	a.Snippet = article.Snippet
 	a.Author.Username = article.AuthorUsername
 	a.Author.Bio = article.AuthorBio
 	if article.AuthorImage.Valid {
@@ -127,14 +183,76 @@ func ArticleViewToResponse(article domain.ArticleView) ArticleResponse {
 type MultipleArticlesResponse struct {
 	Articles      []Article `json:"articles"`
 	ArticlesCount int       `json:"articlesCount"`
+	PrevCursor    *string   `json:"prevCursor"`
+	NextCursor    *string   `json:"nextCursor"`
 }
 
-func ArticlesToResponse(articles []domain.ArticleView) MultipleArticlesResponse {
+// ArticlesToResponse sets ArticlesCount to total count of articles in all pages.
+func ArticlesToResponse(articles []domain.ArticleView, total int64) MultipleArticlesResponse {
 	var resp MultipleArticlesResponse
 	resp.Articles = lo.Map(articles, func(article domain.ArticleView, index int) Article {
 		return ArticleViewToDto(article)
 	})
-	resp.ArticlesCount = len(articles)
+	resp.ArticlesCount = int(total)
+	return resp
+}
+
+type ArticleRevision struct {
+	Number       int      `json:"number"`
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	Body         string   `json:"body"`
+	CreatedAt    JSONTime `json:"createdAt"`
+	RestoredFrom *int     `json:"restoredFrom"`
+}
+
+func ArticleRevisionToDto(revision domain.ArticleRevision) ArticleRevision {
+	var r ArticleRevision
+	r.Number = revision.Number
+	r.Title = revision.Title
+	r.Description = revision.Description
+	r.Body = revision.Body
+	r.CreatedAt = JSONTime(revision.CreatedAt)
+	if revision.RestoredFrom.Valid {
+		restoredFrom := int(revision.RestoredFrom.Int32)
+		r.RestoredFrom = &restoredFrom
+	}
+	return r
+}
+
+type ArticleRevisionResponse struct {
+	Revision ArticleRevision `json:"revision"`
+}
+
+func ArticleRevisionToResponse(revision domain.ArticleRevision) ArticleRevisionResponse {
+	return ArticleRevisionResponse{Revision: ArticleRevisionToDto(revision)}
+}
+
+type MultipleArticleRevisionsResponse struct {
+	Revisions []ArticleRevision `json:"revisions"`
+}
+
+func ArticleRevisionsToResponse(revisions []domain.ArticleRevision) MultipleArticleRevisionsResponse {
+	var resp MultipleArticleRevisionsResponse
+	resp.Revisions = lo.Map(revisions, func(revision domain.ArticleRevision, index int) ArticleRevision {
+		return ArticleRevisionToDto(revision)
+	})
+	return resp
+}
+
+type RevisionDiffResponse struct {
+	Diff struct {
+		From    int    `json:"from"`
+		To      int    `json:"to"`
+		Unified string `json:"unified"`
+	} `json:"diff"`
+}
+
+func RevisionDiffToResponse(diff domain.RevisionDiff) RevisionDiffResponse {
+	var resp RevisionDiffResponse
+	resp.Diff.From = diff.From
+	resp.Diff.To = diff.To
+	resp.Diff.Unified = diff.Unified
 	return resp
 }
 
@@ -143,12 +261,20 @@ type Comment struct {
 	CreatedAt JSONTime `json:"createdAt"`
 	UpdatedAt JSONTime `json:"updatedAt"`
 	Body      string   `json:"body"`
-	Author    struct {
+	ParentId  *uint    `json:"parentId"`
+	// Deleted comment is a tombstone kept for its replies
+	Deleted  bool      `json:"deleted"`
+	Edited   bool      `json:"edited"`
+	EditedAt *JSONTime `json:"editedAt"`
+	Author   struct {
 		Username  string  `json:"username"`
 		Bio       string  `json:"bio"`
 		Image     *string `json:"image"`
 		Following bool    `json:"following"`
 	} `json:"author"`
+	Replies []Comment `json:"replies,omitempty"`
+	// RepliesCount is set in tree layout, thread is cut if it is larger than the number of replies
+	RepliesCount int `json:"repliesCount,omitempty"`
 }
 
 func CommentViewToDto(comment domain.CommentView) Comment {
@@ -157,12 +283,23 @@ func CommentViewToDto(comment domain.CommentView) Comment {
 	c.CreatedAt = JSONTime(comment.CreatedAt)
 	c.UpdatedAt = JSONTime(comment.UpdatedAt)
 	c.Body = comment.Body
+	c.ParentId = comment.ParentID
+	c.Deleted = comment.Tombstoned
+	if comment.EditedAt.Valid {
+		editedAt := JSONTime(comment.EditedAt.Time)
+		c.Edited = true
+		c.EditedAt = &editedAt
+	}
 	c.Author.Username = comment.AuthorUsername
 	c.Author.Bio = comment.AuthorBio
 	if comment.AuthorImage.Valid {
 		c.Author.Image = &comment.AuthorImage.String
 	}
 	c.Author.Following = comment.AuthorFollowing
+	c.Replies = lo.Map(comment.Replies, func(reply domain.CommentView, index int) Comment {
+		return CommentViewToDto(reply)
+	})
+	c.RepliesCount = comment.RepliesCount
 	return c
 }
 
@@ -177,17 +314,193 @@ func CommentViewToResponse(comment domain.CommentView) CommentResponse {
 }
 
 type MultipleCommentsResponse struct {
-	Comments []Comment `json:"comments"`
+	Comments      []Comment `json:"comments"`
+	CommentsCount int       `json:"commentsCount"`
+	PrevCursor    *string   `json:"prevCursor"`
+	NextCursor    *string   `json:"nextCursor"`
 }
 
-func CommentsToResponse(comments []domain.CommentView) MultipleCommentsResponse {
+func CommentsToResponse(comments []domain.CommentView, total int64) MultipleCommentsResponse {
 	var resp MultipleCommentsResponse
 	resp.Comments = lo.Map(comments, func(comment domain.CommentView, index int) Comment {
 		return CommentViewToDto(comment)
 	})
+	resp.CommentsCount = int(total)
+	return resp
+}
+
+type CommentRevision struct {
+	Body      string   `json:"body"`
+	CreatedAt JSONTime `json:"createdAt"`
+}
+
+type MultipleCommentRevisionsResponse struct {
+	Revisions []CommentRevision `json:"revisions"`
+}
+
+func CommentRevisionsToResponse(revisions []domain.CommentRevision) MultipleCommentRevisionsResponse {
+	var resp MultipleCommentRevisionsResponse
+	resp.Revisions = lo.Map(revisions, func(revision domain.CommentRevision, index int) CommentRevision {
+		return CommentRevision{
+			Body:      revision.Body,
+			CreatedAt: JSONTime(revision.CreatedAt),
+		}
+	})
 	return resp
 }
 
 type TagsResponse struct {
 	Tags []string `json:"tags"`
 }
+
+type PersonalAccessToken struct {
+	ID         uint      `json:"id"`
+	Name       string    `json:"name"`
+	Scopes     []string  `json:"scopes"`
+	Token      string    `json:"token,omitempty"`
+	CreatedAt  JSONTime  `json:"createdAt"`
+	ExpiresAt  *JSONTime `json:"expiresAt"`
+	LastUsedAt *JSONTime `json:"lastUsedAt"`
+}
+
+// PersonalAccessTokenToDto includes the token itself only when it is just created.
+func PersonalAccessTokenToDto(token domain.PersonalAccessToken) PersonalAccessToken {
+	var t PersonalAccessToken
+	t.ID = token.ID
+	t.Name = token.Name
+	t.Scopes = token.Scopes
+	if t.Scopes == nil {
+		t.Scopes = []string{}
+	}
+	t.Token = token.Token
+	t.CreatedAt = JSONTime(token.CreatedAt)
+	if token.ExpiresAt.Valid {
+		expiresAt := JSONTime(token.ExpiresAt.Time)
+		t.ExpiresAt = &expiresAt
+	}
+	if token.LastUsedAt.Valid {
+		lastUsedAt := JSONTime(token.LastUsedAt.Time)
+		t.LastUsedAt = &lastUsedAt
+	}
+	return t
+}
+
+type PersonalAccessTokenResponse struct {
+	Token PersonalAccessToken `json:"token"`
+}
+
+func PersonalAccessTokenToResponse(token domain.PersonalAccessToken) PersonalAccessTokenResponse {
+	return PersonalAccessTokenResponse{Token: PersonalAccessTokenToDto(token)}
+}
+
+type MultiplePersonalAccessTokensResponse struct {
+	Tokens []PersonalAccessToken `json:"tokens"`
+}
+
+func PersonalAccessTokensToResponse(tokens []domain.PersonalAccessToken) MultiplePersonalAccessTokensResponse {
+	var resp MultiplePersonalAccessTokensResponse
+	resp.Tokens = lo.Map(tokens, func(token domain.PersonalAccessToken, index int) PersonalAccessToken {
+		return PersonalAccessTokenToDto(token)
+	})
+	return resp
+}
+
+type ManagedUser struct {
+	ID        uint     `json:"id"`
+	Email     string   `json:"email"`
+	Username  string   `json:"username"`
+	Role      string   `json:"role"`
+	Verified  bool     `json:"verified"`
+	CreatedAt JSONTime `json:"createdAt"`
+}
+
+func ManagedUserToDto(user domain.User) ManagedUser {
+	return ManagedUser{
+		ID:        user.ID,
+		Email:     user.Email,
+		Username:  user.Username,
+		Role:      string(user.Role),
+		Verified:  user.Verified(),
+		CreatedAt: JSONTime(user.CreatedAt),
+	}
+}
+
+type ManagedUserResponse struct {
+	User ManagedUser `json:"user"`
+}
+
+type MultipleManagedUsersResponse struct {
+	Users []ManagedUser `json:"users"`
+}
+
+func ManagedUsersToResponse(users []domain.User) MultipleManagedUsersResponse {
+	var resp MultipleManagedUsersResponse
+	resp.Users = lo.Map(users, func(user domain.User, index int) ManagedUser {
+		return ManagedUserToDto(user)
+	})
+	return resp
+}
+
+type ExportedProfile struct {
+	Email     string   `json:"email"`
+	Username  string   `json:"username"`
+	Bio       string   `json:"bio"`
+	Image     *string  `json:"image"`
+	Role      string   `json:"role"`
+	Verified  bool     `json:"verified"`
+	CreatedAt JSONTime `json:"createdAt"`
+}
+
+type ExportedComment struct {
+	Comment
+	ArticleID uint `json:"articleId"`
+}
+
+type ExportedFollow struct {
+	Username string  `json:"username"`
+	Bio      string  `json:"bio"`
+	Image    *string `json:"image"`
+}
+
+// AccountExportResponse is personal data of user. Each field is written to separate file in zip format.
+type AccountExportResponse struct {
+	Profile   ExportedProfile   `json:"profile"`
+	Articles  []Article         `json:"articles"`
+	Comments  []ExportedComment `json:"comments"`
+	Favorites []Article         `json:"favorites"`
+	Follows   []ExportedFollow  `json:"follows"`
+}
+
+func AccountExportToResponse(export domain.AccountExport) AccountExportResponse {
+	var resp AccountExportResponse
+	user := export.User
+	resp.Profile = ExportedProfile{
+		Email:     user.Email,
+		Username:  user.Username,
+		Bio:       user.Bio,
+		Image:     lo.If(user.Image.Valid, &user.Image.String).Else(nil),
+		Role:      string(user.Role),
+		Verified:  user.Verified(),
+		CreatedAt: JSONTime(user.CreatedAt),
+	}
+	resp.Articles = lo.Map(export.Articles, func(article domain.Article, index int) Article {
+		return ArticleViewToDto(domain.NewArticleView(article, false, false))
+	})
+	resp.Comments = lo.Map(export.Comments, func(comment domain.Comment, index int) ExportedComment {
+		return ExportedComment{
+			Comment:   CommentViewToDto(domain.NewCommentView(comment, false)),
+			ArticleID: comment.ArticleID,
+		}
+	})
+	resp.Favorites = lo.Map(export.Favorites, func(article domain.Article, index int) Article {
+		return ArticleViewToDto(domain.NewArticleView(article, true, false))
+	})
+	resp.Follows = lo.Map(export.Followings, func(following domain.User, index int) ExportedFollow {
+		return ExportedFollow{
+			Username: following.Username,
+			Bio:      following.Bio,
+			Image:    lo.If(following.Image.Valid, &following.Image.String).Else(nil),
+		}
+	})
+	return resp
+}
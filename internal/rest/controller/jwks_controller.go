@@ -0,0 +1,20 @@
+package controller
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/pkg/jwtutil"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type JwksController struct {
+	jwtUtil *jwtutil.JwtUtil
+}
+
+func NewJwksController(jwtUtil *jwtutil.JwtUtil) *JwksController {
+	return &JwksController{jwtUtil: jwtUtil}
+}
+
+func (c *JwksController) GetJwks(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, c.jwtUtil.JWKS())
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/pkg/cursor"
+	"time"
+)
+
+// Paginator converts cursors of pages to signed tokens, so that clients only pass them back as they are.
+type Paginator struct {
+	codec *cursor.Codec
+}
+
+func NewPaginator(codec *cursor.Codec) *Paginator {
+	return &Paginator{
+		codec: codec,
+	}
+}
+
+// CursorScope is the listing which cursor is issued for. Cursor used for other listing is rejected,
+// since the row it points to is placed differently there.
+type CursorScope struct {
+	// Sort is the order of rows, such as oldest or newest
+	Sort string
+	// Filters are marshaled to json and hashed, so they should be the same value for the same listing
+	Filters any
+}
+
+func (s CursorScope) hash() string {
+	b, err := json.Marshal(s.Filters)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return base64.RawURLEncoding.EncodeToString(sum[:16])
+}
+
+type cursorPayload struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uint      `json:"i"`
+	Before    bool      `json:"b,omitempty"`
+	Sort      string    `json:"s"`
+	Filters   string    `json:"f"`
+}
+
+// Pageable uses token for keyset pagination if it is set, otherwise offset.
+// Token is invalid if it was not issued for scope.
+func (p *Paginator) Pageable(limit, offset int, token string, scope CursorScope) (ports.Pageable, error) {
+	pageable := ports.Pageable{
+		Limit:  limit,
+		Offset: offset,
+	}
+	if token == "" {
+		return pageable, nil
+	}
+
+	var payload cursorPayload
+	if err := p.codec.Decode(token, &payload); err != nil {
+		return ports.Pageable{}, ports.ErrInvalidCursor
+	}
+	if payload.Sort != scope.Sort || payload.Filters != scope.hash() {
+		return ports.Pageable{}, ports.ErrInvalidCursor
+	}
+	pageable.Offset = 0
+	pageable.Cursor = &ports.Cursor{
+		CreatedAt: payload.CreatedAt,
+		ID:        payload.ID,
+		Before:    payload.Before,
+	}
+	return pageable, nil
+}
+
+// Cursors returns tokens of previous and next pages, from first and last rows of page.
+// Token is nil if there is no such page.
+func (p *Paginator) Cursors(page ports.Page, first, last ports.Cursor, scope CursorScope) (prev, next *string) {
+	filters := scope.hash()
+	if page.HasPrev && first.ID != 0 {
+		prev = p.encode(cursorPayload{CreatedAt: first.CreatedAt, ID: first.ID, Before: true, Sort: scope.Sort, Filters: filters})
+	}
+	if page.HasNext && last.ID != 0 {
+		next = p.encode(cursorPayload{CreatedAt: last.CreatedAt, ID: last.ID, Sort: scope.Sort, Filters: filters})
+	}
+	return prev, next
+}
+
+func (p *Paginator) encode(payload cursorPayload) *string {
+	token, err := p.codec.Encode(payload)
+	if err != nil {
+		return nil
+	}
+	return &token
+}
+
+func articleCursors(articles []domain.ArticleView) (first, last ports.Cursor) {
+	if len(articles) == 0 {
+		return first, last
+	}
+	head, tail := articles[0], articles[len(articles)-1]
+	return ports.Cursor{CreatedAt: head.CreatedAt, ID: head.ID}, ports.Cursor{CreatedAt: tail.CreatedAt, ID: tail.ID}
+}
+
+func commentCursors(comments []domain.CommentView) (first, last ports.Cursor) {
+	if len(comments) == 0 {
+		return first, last
+	}
+	head, tail := comments[0], comments[len(comments)-1]
+	return ports.Cursor{CreatedAt: head.CreatedAt, ID: head.ID}, ports.Cursor{CreatedAt: tail.CreatedAt, ID: tail.ID}
+}
@@ -5,9 +5,7 @@ import (
 	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/ports/mock_ports"
 	"github.com/KumKeeHyun/gin-realworld/internal/rest/middleware"
-	"github.com/KumKeeHyun/gin-realworld/pkg/jwtutil"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
@@ -16,10 +14,10 @@ import (
 	"testing"
 )
 
-func profileRoute(profileController *ProfileController) *gin.Engine {
+func profileRoute(ctrl *gomock.Controller, profileController *ProfileController) *gin.Engine {
 	logger := zap.NewNop()
 	errorHandler := middleware.NewErrorsMiddleware(logger).GinHandlerFunc()
-	checkJwt := middleware.NewCheckJwtMiddleware(jwtutil.New(jwt.SigningMethodHS256, []byte("test-secret")), logger).GinHandlerFunc()
+	checkJwt := checkJwtHandler(ctrl, logger)
 	ensureAuth := middleware.NewEnsureAuthMiddleware(logger).GinHandlerFunc()
 
 	r := gin.New()
@@ -54,7 +52,7 @@ func TestProfileController_GetProfile(t *testing.T) {
 		AnyTimes()
 
 	c := NewProfileController(ps)
-	r := profileRoute(c)
+	r := profileRoute(ctrl, c)
 
 	t.Run("프로필 조회 성공", func(t *testing.T) {
 		w := httptest.NewRecorder()
@@ -99,7 +97,7 @@ func TestProfileController_FollowUser(t *testing.T) {
 		AnyTimes()
 
 	c := NewProfileController(ps)
-	r := profileRoute(c)
+	r := profileRoute(ctrl, c)
 
 	t.Run("팔로우 성공", func(t *testing.T) {
 		w := httptest.NewRecorder()
@@ -138,7 +136,7 @@ func TestProfileController_UnfollowUser(t *testing.T) {
 		AnyTimes()
 
 	c := NewProfileController(ps)
-	r := profileRoute(c)
+	r := profileRoute(ctrl, c)
 
 	t.Run("언팔로우 성공", func(t *testing.T) {
 		w := httptest.NewRecorder()
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/internal/rest/middleware"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+type TokenController struct {
+	tokenService ports.TokenService
+}
+
+func NewTokenController(tokenService ports.TokenService) *TokenController {
+	return &TokenController{
+		tokenService: tokenService,
+	}
+}
+
+type CreateTokenRequest struct {
+	Token struct {
+		Name      string    `json:"name" binding:"required"`
+		Scopes    []string  `json:"scopes"`
+		ExpiresAt *JSONTime `json:"expiresAt"`
+	} `json:"token" binding:"required"`
+}
+
+func (c *TokenController) CreateToken(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var request CreateTokenRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.Error(err)
+		return
+	}
+	var expiresAt *time.Time
+	if request.Token.ExpiresAt != nil {
+		t := time.Time(*request.Token.ExpiresAt)
+		expiresAt = &t
+	}
+
+	created, err := c.tokenService.CreatePersonalAccessToken(claim.UID, request.Token.Name, request.Token.Scopes, expiresAt)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, PersonalAccessTokenToResponse(created))
+}
+
+func (c *TokenController) ListTokens(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	tokens, err := c.tokenService.ListPersonalAccessTokens(claim.UID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, PersonalAccessTokensToResponse(tokens))
+}
+
+type TokenUri struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+func (c *TokenController) RevokeToken(ctx *gin.Context) {
+	claim, err := middleware.GetAccessClaim(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var requestUri TokenUri
+	if err := ctx.ShouldBindUri(&requestUri); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = c.tokenService.RevokePersonalAccessToken(claim.UID, requestUri.ID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
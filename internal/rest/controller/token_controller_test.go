@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports/mock_ports"
+	"github.com/KumKeeHyun/gin-realworld/internal/rest/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func tokenRoute(ctrl *gomock.Controller, tokenController *TokenController) *gin.Engine {
+	logger := zap.NewNop()
+	errorHandler := middleware.NewErrorsMiddleware(logger).GinHandlerFunc()
+	checkJwt := checkJwtHandler(ctrl, logger)
+	ensureAuth := middleware.NewEnsureAuthMiddleware(logger).GinHandlerFunc()
+	requireSession := middleware.NewScopeMiddleware(logger).RequireSession()
+
+	r := gin.New()
+	api := r.Group("api", errorHandler, checkJwt)
+	tokens := api.Group("user/tokens", ensureAuth, requireSession)
+	tokens.POST("", tokenController.CreateToken)
+	tokens.GET("", tokenController.ListTokens)
+	tokens.DELETE("/:id", tokenController.RevokeToken)
+
+	return r
+}
+
+func TestTokenController_CreateToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ts := mock_ports.NewMockTokenService(ctrl)
+
+	ts.EXPECT().
+		CreatePersonalAccessToken(gomock.Eq(uint(1)), gomock.Eq("deploy"), gomock.Eq([]string{domain.ScopeArticlesWrite}), gomock.Nil()).
+		Return(domain.PersonalAccessToken{
+			Model:  gorm.Model{ID: 1},
+			Name:   "deploy",
+			Scopes: []string{domain.ScopeArticlesWrite},
+			Token:  "pat_created",
+		}, nil)
+	ts.EXPECT().
+		CreatePersonalAccessToken(gomock.Eq(uint(1)), gomock.Eq("invalid"), gomock.Any(), gomock.Nil()).
+		Return(domain.PersonalAccessToken{}, ports.ErrInvalidScope)
+
+	c := NewTokenController(ts)
+	r := tokenRoute(ctrl, c)
+
+	t.Run("토큰 생성 성공", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		createReq := CreateTokenRequest{}
+		createReq.Token.Name = "deploy"
+		createReq.Token.Scopes = []string{domain.ScopeArticlesWrite}
+		body, err := json.Marshal(&createReq)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/user/tokens", bytes.NewReader(body))
+		setAuthorization(req, 1, "test")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		resp := PersonalAccessTokenResponse{}
+		err = json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, "pat_created", resp.Token.Token)
+		assert.Equal(t, []string{domain.ScopeArticlesWrite}, resp.Token.Scopes)
+	})
+	t.Run("잘못된 scope", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		createReq := CreateTokenRequest{}
+		createReq.Token.Name = "invalid"
+		createReq.Token.Scopes = []string{"admin"}
+		body, err := json.Marshal(&createReq)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/user/tokens", bytes.NewReader(body))
+		setAuthorization(req, 1, "test")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("personal access token으로 토큰 생성", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		createReq := CreateTokenRequest{}
+		createReq.Token.Name = "escalation"
+		body, err := json.Marshal(&createReq)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/user/tokens", bytes.NewReader(body))
+		req.Header["Authorization"] = []string{"token " + testPersonalAccessToken}
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestTokenController_RevokeToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ts := mock_ports.NewMockTokenService(ctrl)
+
+	ts.EXPECT().
+		RevokePersonalAccessToken(gomock.Eq(uint(1)), gomock.Eq(uint(1))).
+		Return(nil)
+	ts.EXPECT().
+		RevokePersonalAccessToken(gomock.Eq(uint(1)), gomock.Eq(uint(2))).
+		Return(ports.ErrResourceNotFound)
+
+	c := NewTokenController(ts)
+	r := tokenRoute(ctrl, c)
+
+	t.Run("토큰 폐기 성공", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/user/tokens/1", nil)
+		setAuthorization(req, 1, "test")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+	t.Run("없는 토큰 폐기", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/user/tokens/2", nil)
+		setAuthorization(req, 1, "test")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
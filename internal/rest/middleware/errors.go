@@ -53,18 +53,47 @@ func NewErrorsMiddleware(rawLogger *zap.Logger) ErrorsMiddleware {
 					ctx.JSON(http.StatusInternalServerError, NewErrorsResponse(err))
 					return
 				case ports.ErrResourceNotFound,
-					ports.ErrInvalidPassword,
+					ports.ErrInvalidCredentials,
 					ports.ErrSelfFollowing,
 					ports.ErrDuplicatedEmailOrUsername,
+					ports.ErrReservedEmailOrUsername,
+					ports.ErrInvalidResetToken,
+					ports.ErrInvalidVerificationToken,
+					ports.ErrUnknownProvider,
+					ports.ErrInvalidLoginState,
+					ports.ErrInvalidScope,
+					ports.ErrMFAAlreadyEnabled,
+					ports.ErrMFANotEnabled,
+					ports.ErrInvalidRole,
+					ports.ErrSelfRoleChange,
+					ports.ErrInvalidArticleStatus,
+					ports.ErrInvalidPublishAt,
+					ports.ErrInvalidSlug,
+					ports.ErrDuplicatedSlug,
+					ports.ErrInvalidCursor,
+					ports.ErrUnsupportedCursor,
+					ports.ErrInvalidParentComment,
+					ports.ErrCommentTooDeep,
 					ErrEnsureNotAuth:
 					ctx.JSON(http.StatusBadRequest, NewErrorsResponse(err))
 					return
-				case ports.ErrNonOwnedContent:
+				case ports.ErrNonOwnedContent,
+					ports.ErrPermissionDenied,
+					ErrEnsureVerified,
+					ErrInsufficientScope,
+					ErrSessionRequired:
 					ctx.JSON(http.StatusForbidden, NewErrorsResponse(err))
 					return
-				case ErrEnsureAuth:
+				case ErrEnsureAuth,
+					ports.ErrInvalidRefreshToken,
+					ports.ErrExternalLogin,
+					ports.ErrInvalidMFAChallenge,
+					ports.ErrInvalidMFACode:
 					ctx.JSON(http.StatusUnauthorized, NewErrorsResponse(err))
 					return
+				case ports.ErrTooManyLoginAttempts:
+					ctx.JSON(http.StatusTooManyRequests, NewErrorsResponse(err))
+					return
 				default:
 					errs = append(errs, err)
 				}
@@ -3,14 +3,21 @@ package middleware
 import (
 	"errors"
 	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/pkg/crypto"
 	"github.com/KumKeeHyun/gin-realworld/pkg/jwtutil"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 	"strings"
+	"time"
 )
 
 const (
-	keyClaim = "claim"
+	keyClaim  = "claim"
+	keyScopes = "scopes"
+
+	touchInterval = time.Minute
 )
 
 var (
@@ -19,6 +26,7 @@ var (
 	ErrTokenNotExists = errors.New("token not exists")
 	ErrEnsureAuth     = errors.New("authentication is required")
 	ErrEnsureNotAuth  = errors.New("authentication is not required")
+	ErrEnsureVerified = errors.New("email verification is required")
 )
 
 type (
@@ -31,6 +39,9 @@ type (
 	EnsureNotAuthMiddleware struct {
 		fn gin.HandlerFunc
 	}
+	EnsureVerifiedMiddleware struct {
+		fn gin.HandlerFunc
+	}
 )
 
 func (m CheckJwtMiddleware) GinHandlerFunc() gin.HandlerFunc {
@@ -45,18 +56,40 @@ func (m EnsureNotAuthMiddleware) GinHandlerFunc() gin.HandlerFunc {
 	return m.fn
 }
 
-func NewCheckJwtMiddleware(jwtUtil *jwtutil.JwtUtil, rawLogger *zap.Logger) CheckJwtMiddleware {
+func (m EnsureVerifiedMiddleware) GinHandlerFunc() gin.HandlerFunc {
+	return m.fn
+}
+
+func NewCheckJwtMiddleware(
+	jwtUtil *jwtutil.JwtUtil,
+	tokenRepo ports.TokenRepository,
+	userRepo ports.UserRepository,
+	rawLogger *zap.Logger) CheckJwtMiddleware {
 	logger := rawLogger.Sugar().Named("checkJwtMiddleware")
 	return CheckJwtMiddleware{
 		fn: func(ctx *gin.Context) {
 			tokenString := ctx.GetHeader("Authorization")
 			tokenString, err := stripBearerPrefix(tokenString)
-			if err == nil {
+			if err == nil && strings.HasPrefix(tokenString, domain.PersonalAccessTokenPrefix) {
+				claims, scopes, err := authenticatePersonalAccessToken(tokenRepo, userRepo, tokenString)
+				if err == nil {
+					ctx.Set(keyClaim, claims)
+					ctx.Set(keyScopes, scopes)
+				} else {
+					logger.Infow("failed to authenticate personal access token", "err", err)
+				}
+			} else if err == nil {
 				logger.Debugw("find authorization", "token", tokenString)
 				token, err := jwtUtil.ParseToClaims(tokenString, &domain.AccessClaim{})
 				if err == nil {
 					if claims, ok := token.Claims.(*domain.AccessClaim); ok && token.Valid {
-						ctx.Set(keyClaim, claims)
+						if denied, err := isDenied(tokenRepo, claims); err != nil {
+							logger.Errorw("failed to check denied token", "err", err)
+						} else if denied {
+							logger.Infow("denied token is used", "user-id", claims.UID)
+						} else {
+							ctx.Set(keyClaim, claims)
+						}
 					}
 				} else {
 					logger.Errorw("failed to parse jwt token", "err", err)
@@ -67,6 +100,45 @@ func NewCheckJwtMiddleware(jwtUtil *jwtutil.JwtUtil, rawLogger *zap.Logger) Chec
 	}
 }
 
+// authenticatePersonalAccessToken returns the claim of token owner, as if the owner logged in.
+func authenticatePersonalAccessToken(
+	tokenRepo ports.TokenRepository,
+	userRepo ports.UserRepository,
+	tokenString string) (*domain.AccessClaim, []string, error) {
+	token, err := tokenRepo.FindPersonalAccessToken(crypto.HashToken(tokenString))
+	if err != nil {
+		return nil, nil, err
+	}
+	if token.Expired() {
+		return nil, nil, ErrInvalidToken
+	}
+	user, err := userRepo.FindByID(token.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// last used time is informational, so it is written at most once per interval
+	now := time.Now()
+	if !token.LastUsedAt.Valid || now.Sub(token.LastUsedAt.Time) > touchInterval {
+		if err := tokenRepo.TouchPersonalAccessToken(token.ID, now); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	claim := user.AccessClaim()
+	// claim is not signed, so it has no jti to be denied
+	claim.ID = ""
+	return &claim, token.Scopes, nil
+}
+
+func isDenied(tokenRepo ports.TokenRepository, claims *domain.AccessClaim) (bool, error) {
+	// tokens issued before revocation was introduced have no jti
+	if claims.ID == "" {
+		return false, nil
+	}
+	return tokenRepo.ExistsDeniedToken(claims.ID)
+}
+
 func stripBearerPrefix(tokenString string) (string, error) {
 	parts := strings.Split(tokenString, " ")
 	if len(parts) != 2 {
@@ -119,3 +191,41 @@ func NewEnsureNotAuthMiddleware(*zap.Logger) EnsureNotAuthMiddleware {
 		},
 	}
 }
+
+// NewEnsureVerifiedMiddleware should be placed after EnsureAuthMiddleware.
+// If required is false, it passes every request.
+// Verification is read from database instead of the claim, so that it follows verification and email change at once.
+func NewEnsureVerifiedMiddleware(required bool, userRepo ports.UserRepository, rawLogger *zap.Logger) EnsureVerifiedMiddleware {
+	logger := rawLogger.Sugar().Named("ensureVerifiedMiddleware")
+	return EnsureVerifiedMiddleware{
+		fn: func(ctx *gin.Context) {
+			if !required {
+				ctx.Next()
+				return
+			}
+			claim, err := GetAccessClaim(ctx)
+			if err != nil {
+				ctx.Error(ErrEnsureAuth)
+				ctx.Abort()
+				return
+			}
+			user, err := userRepo.FindByID(claim.UID)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				ctx.Error(ErrEnsureAuth)
+				ctx.Abort()
+				return
+			} else if err != nil {
+				logger.Errorw("failed to find user by id", "id", claim.UID, "err", err)
+				ctx.Error(ports.ErrInternal)
+				ctx.Abort()
+				return
+			}
+			if !user.Verified() {
+				ctx.Error(ErrEnsureVerified)
+				ctx.Abort()
+				return
+			}
+			ctx.Next()
+		},
+	}
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"errors"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -57,8 +59,27 @@ var (
 			Help:      "Number of http requests which are currently running.",
 		},
 	)
+
+	failedLoginsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "failed_logins_total",
+		Help:      "Total number of failed logins.",
+	}, []string{"reason"})
 )
 
+// ObserveFailedLogin counts login failed by err. Errors not caused by client are not counted.
+func ObserveFailedLogin(err error) {
+	switch {
+	case errors.Is(err, ports.ErrInvalidCredentials):
+		failedLoginsTotal.WithLabelValues("invalid_credentials").Inc()
+	case errors.Is(err, ports.ErrTooManyLoginAttempts):
+		failedLoginsTotal.WithLabelValues("locked").Inc()
+	case errors.Is(err, ports.ErrInvalidMFAChallenge), errors.Is(err, ports.ErrInvalidMFACode):
+		failedLoginsTotal.WithLabelValues("invalid_mfa").Inc()
+	}
+}
+
 type (
 	MetricMiddleware struct {
 		fn gin.HandlerFunc
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInsufficientScope = errors.New("token does not have required scope")
+	ErrSessionRequired   = errors.New("personal access token is not allowed")
+)
+
+// ScopeMiddleware restricts requests authenticated by personal access token.
+// Requests authenticated by jwt are sessions of the user, so every scope is granted.
+type ScopeMiddleware struct{}
+
+func NewScopeMiddleware(*zap.Logger) ScopeMiddleware {
+	return ScopeMiddleware{}
+}
+
+// RequireScope should be placed after EnsureAuthMiddleware.
+func (m ScopeMiddleware) RequireScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		scopes, isToken := ctx.Get(keyScopes)
+		if isToken && !lo.Contains(scopes.([]string), scope) {
+			ctx.Error(ErrInsufficientScope)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// RequireSession rejects personal access tokens, e.g. for managing tokens or credentials.
+func (m ScopeMiddleware) RequireSession() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, isToken := ctx.Get(keyScopes); isToken {
+			ctx.Error(ErrSessionRequired)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -9,7 +9,8 @@ import (
 )
 
 const (
-	keyTx = "db_tx"
+	keyTx       = "db_tx"
+	keyTxCommit = "db_tx_commit"
 )
 
 var (
@@ -50,7 +51,7 @@ func NewTransactionMiddleware(db *gorm.DB, rawLogger *zap.Logger) TransactionMid
 			ctx.Set(keyTx, tx)
 			ctx.Next()
 
-			if StatusInList(ctx.Writer.Status(), []int{http.StatusOK, http.StatusCreated}) {
+			if StatusInList(ctx.Writer.Status(), []int{http.StatusOK, http.StatusCreated}) || ctx.GetBool(keyTxCommit) {
 				logger.Debugw("commit transaction")
 				if err := tx.Commit().Error; err != nil {
 					logger.Errorw("failed to commit transaction", "err", err)
@@ -70,3 +71,8 @@ func GetTransaction(ctx *gin.Context) (*gorm.DB, error) {
 	}
 	return tx.(*gorm.DB), nil
 }
+
+// CommitOnError keeps writes of transaction even if request fails, when they are the response to the failure itself.
+func CommitOnError(ctx *gin.Context) {
+	ctx.Set(keyTxCommit, true)
+}
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/domain"
 	"github.com/KumKeeHyun/gin-realworld/internal/rest/controller"
 	"github.com/KumKeeHyun/gin-realworld/internal/rest/middleware"
 	"github.com/gin-contrib/cors"
@@ -16,17 +17,26 @@ func NewRouter(
 	checkJwtMiddleware middleware.CheckJwtMiddleware,
 	ensureAuthMiddleware middleware.EnsureAuthMiddleware,
 	ensureNotAuthMiddleware middleware.EnsureNotAuthMiddleware,
+	ensureVerifiedMiddleware middleware.EnsureVerifiedMiddleware,
+	scopeMiddleware middleware.ScopeMiddleware,
 	transactionMiddleware middleware.TransactionMiddleware,
 	errorsMiddleware middleware.ErrorsMiddleware,
 	metricMiddleware middleware.MetricMiddleware,
 	authController *controller.AuthController,
 	profileController *controller.ProfileController,
 	articleController *controller.ArticleController,
-	commentController *controller.CommentController) *gin.Engine {
+	commentController *controller.CommentController,
+	tokenController *controller.TokenController,
+	adminController *controller.AdminController,
+	accountController *controller.AccountController,
+	jwksController *controller.JwksController) *gin.Engine {
 
 	checkJwt := checkJwtMiddleware.GinHandlerFunc()
 	ensureAuth := ensureAuthMiddleware.GinHandlerFunc()
 	ensureNotAuth := ensureNotAuthMiddleware.GinHandlerFunc()
+	ensureVerified := ensureVerifiedMiddleware.GinHandlerFunc()
+	requireScope := scopeMiddleware.RequireScope
+	requireSession := scopeMiddleware.RequireSession()
 	transaction := transactionMiddleware.GinHandlerFunc()
 	errorHandler := errorsMiddleware.GinHandlerFunc()
 	metrics := metricMiddleware.GinHandlerFunc()
@@ -47,38 +57,74 @@ func NewRouter(
 	r.Use(metrics)
 
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	r.GET("/.well-known/jwks.json", jwksController.GetJwks)
 
 	api := r.Group("api", errorHandler, checkJwt)
 
 	users := api.Group("users")
 	users.POST("/login", ensureNotAuth, authController.AuthenticateUser)
+	users.POST("/login/mfa", ensureNotAuth, authController.AuthenticateMFA)
 	users.POST("", ensureNotAuth, authController.RegisterUser)
+	users.POST("/refresh", transaction, authController.RefreshToken)
+	users.POST("/logout", ensureAuth, requireSession, transaction, authController.Logout)
+	users.POST("/password-reset", ensureNotAuth, transaction, authController.RequestPasswordReset)
+	users.POST("/password-reset/confirm", ensureNotAuth, transaction, authController.ConfirmPasswordReset)
+	users.GET("/verify", transaction, authController.VerifyEmail)
+	users.GET("/oauth", authController.ListExternalProviders)
+	users.GET("/oauth/:provider", ensureNotAuth, authController.BeginExternalLogin)
+	users.GET("/oauth/:provider/callback", ensureNotAuth, transaction, authController.CompleteExternalLogin)
 
 	user := api.Group("user")
 	user.GET("", ensureAuth, authController.GetCurrentUser)
-	user.PUT("", ensureAuth, transaction, authController.UpdateUser)
+	user.PUT("", ensureAuth, requireSession, transaction, authController.UpdateUser)
+	user.DELETE("", ensureAuth, requireSession, transaction, accountController.DeleteAccount)
+	user.GET("/export", ensureAuth, requireSession, accountController.ExportAccount)
+	user.GET("/drafts", ensureAuth, articleController.ListDrafts)
+	user.POST("/verify", ensureAuth, requireSession, authController.ResendVerification)
+
+	tokens := user.Group("tokens", ensureAuth, requireSession)
+	tokens.POST("", tokenController.CreateToken)
+	tokens.GET("", tokenController.ListTokens)
+	tokens.DELETE("/:id", tokenController.RevokeToken)
+
+	mfa := user.Group("mfa", ensureAuth, requireSession, transaction)
+	mfa.POST("/totp", authController.EnrollTOTP)
+	mfa.POST("/totp/confirm", authController.ConfirmTOTP)
+	mfa.POST("/totp/disable", authController.DisableTOTP)
+	mfa.POST("/recovery-codes", authController.RegenerateRecoveryCodes)
 
 	profiles := api.Group("profiles")
 	profiles.GET("/:username", profileController.GetProfile)
-	profiles.POST("/:username/follow", ensureAuth, profileController.FollowUser)
-	profiles.DELETE("/:username/follow", ensureAuth, profileController.UnfollowUser)
+	profiles.POST("/:username/follow", ensureAuth, requireScope(domain.ScopeProfilesWrite), profileController.FollowUser)
+	profiles.DELETE("/:username/follow", ensureAuth, requireScope(domain.ScopeProfilesWrite), profileController.UnfollowUser)
 
 	articles := api.Group("articles")
 	articles.GET("", articleController.ListArticles)
 	articles.GET("/feed", ensureAuth, articleController.FeedArticles)
 	articles.GET("/:slug", articleController.GetArticle)
-	articles.POST("", ensureAuth, articleController.CreateArticle)
-	articles.PUT("/:slug", ensureAuth, articleController.UpdateArticle)
-	articles.DELETE("/:slug", ensureAuth, articleController.DeleteArticle)
-	articles.POST("/:slug/favorite", ensureAuth, articleController.FavoriteArticle)
-	articles.DELETE("/:slug/favorite", ensureAuth, articleController.UnfavoriteArticle)
+	articles.POST("", ensureAuth, requireScope(domain.ScopeArticlesWrite), ensureVerified, transaction, articleController.CreateArticle)
+	articles.PUT("/:slug", ensureAuth, requireScope(domain.ScopeArticlesWrite), transaction, articleController.UpdateArticle)
+	articles.DELETE("/:slug", ensureAuth, requireScope(domain.ScopeArticlesWrite), articleController.DeleteArticle)
+	articles.POST("/:slug/publish", ensureAuth, requireScope(domain.ScopeArticlesWrite), articleController.PublishArticle)
+	articles.GET("/:slug/revisions", articleController.ListRevisions)
+	articles.GET("/:slug/revisions/diff", articleController.DiffRevisions)
+	articles.GET("/:slug/revisions/:number", articleController.GetRevision)
+	articles.POST("/:slug/revisions/:number/restore", ensureAuth, requireScope(domain.ScopeArticlesWrite), transaction, articleController.RestoreRevision)
+	articles.POST("/:slug/favorite", ensureAuth, requireScope(domain.ScopeFavoritesWrite), articleController.FavoriteArticle)
+	articles.DELETE("/:slug/favorite", ensureAuth, requireScope(domain.ScopeFavoritesWrite), articleController.UnfavoriteArticle)
 
 	comments := articles.Group(":slug/comments")
-	comments.POST("", ensureAuth, commentController.AddCommentToArticle)
+	comments.POST("", ensureAuth, requireScope(domain.ScopeCommentsWrite), ensureVerified, transaction, commentController.AddCommentToArticle)
 	comments.GET("", commentController.GetCommentsFromArticle)
-	comments.DELETE("/:id", ensureAuth, commentController.DeleteComment)
+	comments.PUT("/:id", ensureAuth, requireScope(domain.ScopeCommentsWrite), transaction, commentController.UpdateComment)
+	comments.GET("/:id/revisions", ensureAuth, commentController.ListCommentRevisions)
+	comments.DELETE("/:id", ensureAuth, requireScope(domain.ScopeCommentsWrite), commentController.DeleteComment)
 
 	api.GET("/tags", articleController.GetTags)
 
+	admin := api.Group("admin", ensureAuth, requireSession)
+	admin.GET("/users", adminController.ListUsers)
+	admin.PUT("/users/:username/role", transaction, adminController.UpdateRole)
+
 	return r
 }
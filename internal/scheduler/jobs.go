@@ -0,0 +1,34 @@
+package scheduler
+
+import (
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	PublishScheduledArticlesJob = "publish-scheduled-articles"
+	PurgeDeniedTokensJob        = "purge-denied-tokens"
+)
+
+func NewPublishJob(articleService ports.ArticleService, interval time.Duration) Job {
+	return Job{
+		Name:     PublishScheduledArticlesJob,
+		Interval: interval,
+		Run: func(tx *gorm.DB, now time.Time) error {
+			_, err := articleService.WithTx(tx).PublishScheduled(now)
+			return err
+		},
+	}
+}
+
+func NewPurgeDeniedTokensJob(authService ports.AuthService, interval time.Duration) Job {
+	return Job{
+		Name:     PurgeDeniedTokensJob,
+		Interval: interval,
+		Run: func(tx *gorm.DB, now time.Time) error {
+			_, err := authService.WithTx(tx).PurgeDeniedTokens(now)
+			return err
+		},
+	}
+}
@@ -0,0 +1,98 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports"
+	"github.com/KumKeeHyun/gin-realworld/pkg/clock"
+	"github.com/KumKeeHyun/gin-realworld/pkg/crypto"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"os"
+	"time"
+)
+
+// Job is run periodically in a transaction, by the replica holding the lease of its name.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(tx *gorm.DB, now time.Time) error
+}
+
+type Scheduler struct {
+	db        *gorm.DB
+	leaseRepo ports.LeaseRepository
+	clock     clock.Clock
+	holder    string
+	jobs      []Job
+	logger    *zap.SugaredLogger
+}
+
+func New(db *gorm.DB, leaseRepo ports.LeaseRepository, clock clock.Clock, logger *zap.Logger) *Scheduler {
+	return &Scheduler{
+		db:        db,
+		leaseRepo: leaseRepo,
+		clock:     clock,
+		holder:    newHolder(),
+		logger:    logger.Sugar().Named("scheduler"),
+	}
+}
+
+// newHolder identifies this replica, random suffix distinguishes processes on the same host.
+func newHolder() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	token, err := crypto.GenerateToken()
+	if err != nil {
+		return fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), token[:8])
+}
+
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start runs each job on its interval until ctx is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		go s.loop(ctx, job)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.RunOnce(job); err != nil {
+			s.logger.Errorw("failed to run job", "job", job.Name, "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce acquires lease and runs job in the same transaction.
+// Lease lasts two intervals, so that holder keeps it while it is alive and others take over when it is not.
+// It returns false without running job if another replica holds the lease.
+func (s *Scheduler) RunOnce(job Job) (bool, error) {
+	now := s.clock.Now()
+	ran := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		acquired, err := s.leaseRepo.WithTx(tx).AcquireLease(job.Name, s.holder, now, now.Add(2*job.Interval))
+		if err != nil || !acquired {
+			return err
+		}
+		ran = true
+		return job.Run(tx, now)
+	})
+	if err != nil {
+		return false, err
+	}
+	return ran, nil
+}
@@ -0,0 +1,117 @@
+package scheduler
+
+import (
+	"errors"
+	"github.com/KumKeeHyun/gin-realworld/internal/core/ports/mock_ports"
+	"github.com/KumKeeHyun/gin-realworld/pkg/clock"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestScheduler_RunOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	lr := mock_ports.NewMockLeaseRepository(ctrl)
+	lr.EXPECT().WithTx(gomock.Any()).Return(lr).AnyTimes()
+
+	now := time.Date(2023, 7, 1, 9, 0, 0, 0, time.UTC)
+	c := clock.NewMock(now)
+	s := New(newTestDB(t), lr, c, zap.NewNop())
+
+	var runAt []time.Time
+	job := Job{
+		Name:     "test-job",
+		Interval: time.Minute,
+		Run: func(tx *gorm.DB, now time.Time) error {
+			runAt = append(runAt, now)
+			return nil
+		},
+	}
+
+	t.Run("lease 획득시 실행", func(t *testing.T) {
+		lr.EXPECT().
+			AcquireLease(gomock.Eq("test-job"), gomock.Eq(s.holder), gomock.Eq(now), gomock.Eq(now.Add(2*time.Minute))).
+			Return(true, nil)
+
+		ran, err := s.RunOnce(job)
+
+		assert.NoError(t, err)
+		assert.True(t, ran)
+		assert.Equal(t, []time.Time{now}, runAt)
+	})
+	t.Run("다른 replica가 lease를 가지면 실행하지 않음", func(t *testing.T) {
+		c.Add(time.Minute)
+		lr.EXPECT().
+			AcquireLease(gomock.Eq("test-job"), gomock.Any(), gomock.Eq(now.Add(time.Minute)), gomock.Any()).
+			Return(false, nil)
+
+		ran, err := s.RunOnce(job)
+
+		assert.NoError(t, err)
+		assert.False(t, ran)
+		assert.Len(t, runAt, 1)
+	})
+	t.Run("작업 실패", func(t *testing.T) {
+		lr.EXPECT().
+			AcquireLease(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(true, nil)
+		failed := errors.New("failed")
+
+		ran, err := s.RunOnce(Job{
+			Name:     "failing-job",
+			Interval: time.Minute,
+			Run: func(tx *gorm.DB, now time.Time) error {
+				return failed
+			},
+		})
+
+		assert.ErrorIs(t, err, failed)
+		assert.False(t, ran)
+	})
+}
+
+func TestNewPublishJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	as := mock_ports.NewMockArticleService(ctrl)
+	now := time.Date(2023, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	as.EXPECT().WithTx(gomock.Any()).Return(as)
+	as.EXPECT().
+		PublishScheduled(gomock.Eq(now)).
+		Return(int64(2), nil)
+
+	job := NewPublishJob(as, time.Minute)
+	err := job.Run(nil, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, PublishScheduledArticlesJob, job.Name)
+}
+
+func TestNewPurgeDeniedTokensJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	as := mock_ports.NewMockAuthService(ctrl)
+	now := time.Date(2023, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	as.EXPECT().WithTx(gomock.Any()).Return(as)
+	as.EXPECT().
+		PurgeDeniedTokens(gomock.Eq(now)).
+		Return(int64(3), nil)
+
+	job := NewPurgeDeniedTokensJob(as, time.Minute)
+	err := job.Run(nil, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, PurgeDeniedTokensJob, job.Name)
+}
@@ -1,6 +1,9 @@
 package crypto
 
-import "testing"
+import (
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+)
 
 func TestHashPassword(t *testing.T) {
 	expected := "test password"
@@ -12,3 +15,50 @@ func TestHashPassword(t *testing.T) {
 		t.Error("check password expect true, got false")
 	}
 }
+
+func TestGenerateToken(t *testing.T) {
+	token1, err := GenerateToken()
+	if err != nil {
+		t.Errorf("generate token error = %v", err)
+	}
+	token2, _ := GenerateToken()
+	if token1 == token2 {
+		t.Error("generated tokens expect different, got same")
+	}
+	if HashToken(token1) != HashToken(token1) {
+		t.Error("hash of same token expect same, got different")
+	}
+}
+
+func TestCheckBcryptHash(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("test password"), bcrypt.MinCost)
+	if !CheckHashAndPassword(string(hash), "test password") {
+		t.Error("check bcrypt password expect true, got false")
+	}
+	if CheckHashAndPassword(string(hash), "wrong password") {
+		t.Error("check wrong password expect false, got true")
+	}
+	if !NeedsRehash(string(hash)) {
+		t.Error("bcrypt hash expect rehash, got not")
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	hash, _ := HashPassword("test password")
+	if NeedsRehash(hash) {
+		t.Error("hash with current params expect no rehash, got rehash")
+	}
+
+	defer SetPasswordParams(DefaultArgon2Params)
+	params := DefaultArgon2Params
+	params.Iterations++
+	if err := SetPasswordParams(params); err != nil {
+		t.Fatalf("set params error = %v", err)
+	}
+	if !NeedsRehash(hash) {
+		t.Error("hash with old params expect rehash, got not")
+	}
+	if !CheckHashAndPassword(hash, "test password") {
+		t.Error("hash with old params expect still valid, got invalid")
+	}
+}
@@ -0,0 +1,128 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+)
+
+// Argon2Params are parameters of argon2id. Hashes made with other parameters are upgraded on next login.
+type Argon2Params struct {
+	// Memory is in KiB
+	Memory     uint32
+	Iterations uint32
+	Threads    uint8
+	SaltLength uint32
+	KeyLength  uint32
+}
+
+// DefaultArgon2Params follows the second recommended option of RFC 9106.
+var DefaultArgon2Params = Argon2Params{
+	Memory:     64 * 1024,
+	Iterations: 3,
+	Threads:    4,
+	SaltLength: 16,
+	KeyLength:  32,
+}
+
+var passwordParams = DefaultArgon2Params
+
+// SetPasswordParams changes parameters of new hashes. It should be called on startup before any hashing.
+func SetPasswordParams(params Argon2Params) error {
+	if params.Iterations < 1 || params.Threads < 1 || params.SaltLength < 8 || params.KeyLength < 16 {
+		return errors.New("invalid argon2 parameters")
+	}
+	if params.Memory < 8*uint32(params.Threads) {
+		return errors.New("argon2 memory must be at least 8KiB per thread")
+	}
+	passwordParams = params
+	return nil
+}
+
+const argon2idPrefix = "$argon2id$"
+
+// HashPassword returns argon2id hash in PHC string format,
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<threads>$<salt>$<key>
+func HashPassword(password string) (string, error) {
+	params := passwordParams
+	salt := make([]byte, params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Threads, params.KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version, params.Memory, params.Iterations, params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// CheckHashAndPassword verifies argon2id hash, and bcrypt hash made before argon2id was introduced.
+func CheckHashAndPassword(hash, password string) bool {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		return checkBcrypt(hash, password)
+	}
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return false
+	}
+	actual := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Threads, params.KeyLength)
+	return subtle.ConstantTimeCompare(key, actual) == 1
+}
+
+// NeedsRehash reports whether hash is made by outdated algorithm or parameters.
+func NeedsRehash(hash string) bool {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		return true
+	}
+	params, salt, _, err := decodeArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	current := passwordParams
+	return params.Memory != current.Memory ||
+		params.Iterations != current.Iterations ||
+		params.Threads != current.Threads ||
+		params.KeyLength != current.KeyLength ||
+		uint32(len(salt)) != current.SaltLength
+}
+
+func checkBcrypt(hash, password string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	return err == nil
+}
+
+func decodeArgon2id(hash string) (Argon2Params, []byte, []byte, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return Argon2Params{}, nil, nil, errors.New("invalid argon2id hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return Argon2Params{}, nil, nil, err
+	}
+	if version != argon2.Version {
+		return Argon2Params{}, nil, nil, errors.New("unsupported argon2 version")
+	}
+	var params Argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Threads); err != nil {
+		return Argon2Params{}, nil, nil, err
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2Params{}, nil, nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return Argon2Params{}, nil, nil, err
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
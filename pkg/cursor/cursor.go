@@ -0,0 +1,63 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var ErrInvalid = errors.New("invalid cursor")
+
+// Codec encodes values into opaque cursors signed by HMAC-SHA256, so that clients can not forge them.
+type Codec struct {
+	key []byte
+}
+
+func New(key []byte) *Codec {
+	return &Codec{key: key}
+}
+
+// NewEphemeral returns Codec with random key, cursors are invalidated on restart.
+func NewEphemeral() (*Codec, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return New(key), nil
+}
+
+// Encode formats v as base64url encoded json and its signature, separated by dot.
+func (c *Codec) Encode(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(b)
+	return payload + "." + c.sign(payload), nil
+}
+
+// Decode verifies signature of cursor and unmarshals its payload into v.
+func (c *Codec) Decode(cursor string, v any) error {
+	payload, sig, ok := strings.Cut(cursor, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(c.sign(payload))) {
+		return ErrInvalid
+	}
+	b, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return ErrInvalid
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return ErrInvalid
+	}
+	return nil
+}
+
+func (c *Codec) sign(payload string) string {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,38 @@
+package cursor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type payload struct {
+	ID uint `json:"i"`
+}
+
+func TestCodec(t *testing.T) {
+	codec := New([]byte("test key"))
+	token, err := codec.Encode(payload{ID: 3})
+	if err != nil {
+		t.Errorf("encode error = %v", err)
+	}
+
+	var decoded payload
+	if err := codec.Decode(token, &decoded); err != nil {
+		t.Errorf("decode error = %v", err)
+	}
+	if decoded.ID != 3 {
+		t.Errorf("decoded id expect 3, got %d", decoded.ID)
+	}
+
+	forged, _ := New([]byte("other key")).Encode(payload{ID: 3})
+	if err := codec.Decode(forged, &decoded); !errors.Is(err, ErrInvalid) {
+		t.Errorf("decode forged cursor expect ErrInvalid, got %v", err)
+	}
+	_, sig, _ := strings.Cut(token, ".")
+	tampered, _ := New(nil).Encode(payload{ID: 4})
+	tampered, _, _ = strings.Cut(tampered, ".")
+	if err := codec.Decode(tampered+"."+sig, &decoded); !errors.Is(err, ErrInvalid) {
+		t.Errorf("decode tampered cursor expect ErrInvalid, got %v", err)
+	}
+}
@@ -1,25 +1,86 @@
 package jwtutil
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrUnknownKeyID      = errors.New("unknown kid")
+	ErrUnexpectedMethod  = errors.New("unexpected signing method")
+	ErrNoSigningKey      = errors.New("signing key not exists")
+	ErrDuplicatedKeyID   = errors.New("duplicated kid")
+	ErrVerifyOnlyKeySign = errors.New("verify only key can not sign")
+)
+
+// Key is a signing or verification key identified by kid.
+// Sign is nil for verify only keys, for example a retired key during rotation window.
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+	Sign   any
+	Verify any
+}
 
 type JwtUtil struct {
-	method jwt.SigningMethod
-	key    any
+	signing Key
+	keys    map[string]Key
 }
 
+// New returns JwtUtil using single key without kid, e.g. HS256 shared secret.
 func New(method jwt.SigningMethod, key any) *JwtUtil {
+	k := Key{
+		Method: method,
+		Sign:   key,
+		Verify: key,
+	}
 	return &JwtUtil{
-		method: method,
-		key:    key,
+		signing: k,
+		keys:    map[string]Key{"": k},
 	}
 }
 
+// NewWithKeys returns JwtUtil signing with signing key and verifying with signing key and others.
+func NewWithKeys(signing Key, others ...Key) (*JwtUtil, error) {
+	if signing.Sign == nil {
+		return nil, ErrVerifyOnlyKeySign
+	}
+	keys := map[string]Key{signing.ID: signing}
+	for _, k := range others {
+		if _, exists := keys[k.ID]; exists {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicatedKeyID, k.ID)
+		}
+		keys[k.ID] = k
+	}
+	return &JwtUtil{
+		signing: signing,
+		keys:    keys,
+	}, nil
+}
+
 func (u *JwtUtil) SignClaims(claims jwt.Claims) (string, error) {
-	return jwt.NewWithClaims(u.method, claims).SignedString(u.key)
+	if u.signing.Sign == nil {
+		return "", ErrNoSigningKey
+	}
+	token := jwt.NewWithClaims(u.signing.Method, claims)
+	if u.signing.ID != "" {
+		token.Header["kid"] = u.signing.ID
+	}
+	return token.SignedString(u.signing.Sign)
 }
 
 func (u *JwtUtil) ParseToClaims(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
 	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return u.key, nil
+		kid, _ := token.Header["kid"].(string)
+		key, exists := u.keys[kid]
+		if !exists {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, kid)
+		}
+		// the key decides the algorithm, not the token header
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("%w: %s", ErrUnexpectedMethod, token.Method.Alg())
+		}
+		return key.Verify, nil
 	})
 }
@@ -0,0 +1,116 @@
+package jwtutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"testing"
+)
+
+func pemOf(t *testing.T, priv any) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestParseKeyPEM(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edKey, _ := NewEphemeralKey("ed")
+
+	tests := []struct {
+		name string
+		priv any
+		alg  string
+	}{
+		{name: "rsa", priv: rsaKey, alg: "RS256"},
+		{name: "ecdsa", priv: ecKey, alg: "ES256"},
+		{name: "ed25519", priv: edKey.Sign, alg: "EdDSA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ParseKeyPEM(tt.name, pemOf(t, tt.priv))
+			if err != nil {
+				t.Fatalf("parse pem error = %v", err)
+			}
+			if key.Method.Alg() != tt.alg {
+				t.Errorf("alg expect %s, got %s", tt.alg, key.Method.Alg())
+			}
+
+			u, err := NewWithKeys(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			token, err := u.SignClaims(jwt.RegisteredClaims{Subject: "test"})
+			if err != nil {
+				t.Fatalf("sign error = %v", err)
+			}
+			parsed, err := u.ParseToClaims(token, &jwt.RegisteredClaims{})
+			if err != nil || !parsed.Valid {
+				t.Errorf("parse error = %v", err)
+			}
+			if parsed.Header["kid"] != tt.name {
+				t.Errorf("kid expect %s, got %v", tt.name, parsed.Header["kid"])
+			}
+			if len(u.JWKS().Keys) != 1 {
+				t.Errorf("jwks expect 1 key, got %d", len(u.JWKS().Keys))
+			}
+
+			jwk, _ := u.JWKS().Find(tt.name)
+			pub, err := jwk.PublicKey()
+			if err != nil {
+				t.Fatalf("jwk public key error = %v", err)
+			}
+			if _, err := jwt.Parse(token, func(*jwt.Token) (any, error) { return pub, nil }); err != nil {
+				t.Errorf("parse with jwk public key error = %v", err)
+			}
+		})
+	}
+}
+
+func TestJwtUtil_Rotation(t *testing.T) {
+	oldKey, _ := NewEphemeralKey("old")
+	newKey, _ := NewEphemeralKey("new")
+	unknownKey, _ := NewEphemeralKey("unknown")
+
+	oldUtil, _ := NewWithKeys(oldKey)
+	oldToken, _ := oldUtil.SignClaims(jwt.RegisteredClaims{Subject: "test"})
+	unknownUtil, _ := NewWithKeys(unknownKey)
+	unknownToken, _ := unknownUtil.SignClaims(jwt.RegisteredClaims{Subject: "test"})
+
+	retired := Key{ID: oldKey.ID, Method: oldKey.Method, Verify: oldKey.Verify}
+	u, err := NewWithKeys(newKey, retired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := u.ParseToClaims(oldToken, &jwt.RegisteredClaims{}); err != nil {
+		t.Errorf("token signed by retired key expect valid, got %v", err)
+	}
+	if _, err := u.ParseToClaims(unknownToken, &jwt.RegisteredClaims{}); !errors.Is(err, ErrUnknownKeyID) {
+		t.Errorf("token signed by unknown key expect ErrUnknownKeyID, got %v", err)
+	}
+	if len(u.JWKS().Keys) != 2 {
+		t.Errorf("jwks expect 2 keys, got %d", len(u.JWKS().Keys))
+	}
+}
+
+func TestJwtUtil_RejectAlgorithmConfusion(t *testing.T) {
+	key, _ := NewEphemeralKey("")
+	u, _ := NewWithKeys(key)
+
+	// HS256 token using public key bytes as secret must not be accepted
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{}).
+		SignedString([]byte(key.Verify.(ed25519.PublicKey)))
+	if _, err := u.ParseToClaims(token, &jwt.RegisteredClaims{}); err == nil {
+		t.Error("token with unexpected alg expect error, got nil")
+	}
+}
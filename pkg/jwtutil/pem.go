@@ -0,0 +1,124 @@
+package jwtutil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"os"
+)
+
+var (
+	ErrInvalidPEM          = errors.New("invalid pem")
+	ErrUnsupportedKeyType  = errors.New("unsupported key type")
+	ErrUnsupportedKeyCurve = errors.New("unsupported ecdsa curve")
+)
+
+// LoadKeyFile reads private or public key from PEM file.
+// Public key file makes verify only key.
+func LoadKeyFile(kid, path string) (Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, err
+	}
+	return ParseKeyPEM(kid, data)
+}
+
+// ParseKeyPEM parses RSA, ECDSA or Ed25519 key and picks RS256, ES256/384/512 or EdDSA by key type.
+func ParseKeyPEM(kid string, data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, ErrInvalidPEM
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return Key{}, err
+		}
+		return newKey(kid, nil, pub)
+	case "RSA PUBLIC KEY":
+		pub, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return Key{}, err
+		}
+		return newKey(kid, nil, pub)
+	case "PRIVATE KEY":
+		priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return Key{}, err
+		}
+		return newKeyFromPrivate(kid, priv)
+	case "RSA PRIVATE KEY":
+		priv, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return Key{}, err
+		}
+		return newKeyFromPrivate(kid, priv)
+	case "EC PRIVATE KEY":
+		priv, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return Key{}, err
+		}
+		return newKeyFromPrivate(kid, priv)
+	default:
+		return Key{}, fmt.Errorf("%w: %s", ErrUnsupportedKeyType, block.Type)
+	}
+}
+
+func newKeyFromPrivate(kid string, priv any) (Key, error) {
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return Key{}, ErrUnsupportedKeyType
+	}
+	return newKey(kid, priv, signer.Public())
+}
+
+func newKey(kid string, priv, pub any) (Key, error) {
+	var method jwt.SigningMethod
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			method = jwt.SigningMethodES256
+		case elliptic.P384():
+			method = jwt.SigningMethodES384
+		case elliptic.P521():
+			method = jwt.SigningMethodES512
+		default:
+			return Key{}, ErrUnsupportedKeyCurve
+		}
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+		// jwt expects ed25519.PrivateKey as crypto.Signer value
+		if p, ok := priv.(*ed25519.PrivateKey); ok {
+			priv = *p
+		}
+	default:
+		return Key{}, fmt.Errorf("%w: %T", ErrUnsupportedKeyType, pub)
+	}
+	return Key{
+		ID:     kid,
+		Method: method,
+		Sign:   priv,
+		Verify: pub,
+	}, nil
+}
+
+// NewEphemeralKey generates Ed25519 key which lives only in memory.
+func NewEphemeralKey(kid string) (Key, error) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return Key{}, err
+	}
+	return newKey(kid, priv, pub)
+}
@@ -4,8 +4,13 @@ import (
 	"encoding/hex"
 	"github.com/gosimple/slug"
 	"math/rand"
+	"regexp"
 )
 
+const maxCustomLength = 64
+
+var customPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
 func init() {
 	slug.MaxLength = 20
 }
@@ -17,3 +22,8 @@ func Make(title string) string {
 	ridString := hex.EncodeToString(b)
 	return slugString + "-" + ridString
 }
+
+// Valid reports whether custom slug chosen by user is usable in url as it is.
+func Valid(s string) bool {
+	return len(s) <= maxCustomLength && customPattern.MatchString(s)
+}